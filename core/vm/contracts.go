@@ -47,6 +47,13 @@ type PrecompiledContract interface {
 	Run(input []byte) ([]byte, error) // Run runs the precompiled contract
 }
 
+// blockPrecompile is implemented by precompiles whose output depends on the block being
+// executed. The EVM binds them to its block context before running them.
+type blockPrecompile interface {
+	PrecompiledContract
+	atBlock(ctx BlockContext) PrecompiledContract
+}
+
 // PrecompiledContracts contains the precompiled contracts supported at the given fork.
 type PrecompiledContracts map[common.Address]PrecompiledContract
 
@@ -1,23 +1,38 @@
 package vm
 
 import (
-	"fmt"
+	"errors"
 	"math/big"
-	"math/rand"
 	"strings"
-	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
-// randomContractAddr defines the precompile contract address for `random` precompile
+// randomPRNGContractAddr defines the precompile contract address for `randomPRNG` precompile
 var randomPRNGContractAddr = common.HexToAddress("0x0000000000000000000000000000000000069420")
 
-type randomPRNG struct{}
+const RandomPRNGGasCost = 1024
+
+var (
+	errMissingSelector = errors.New("function selector is missing")
+	errNoBlockContext  = errors.New("randomPRNG run outside of a block")
+)
+
+// randomPRNG returns a pseudo-random value derived from the number of the block being
+// executed. The EVM binds it to its block context before running it, see blockPrecompile.
+type randomPRNG struct {
+	blockNumber *big.Int
+}
+
+// atBlock implements blockPrecompile.
+func (p *randomPRNG) atBlock(ctx BlockContext) PrecompiledContract {
+	return &randomPRNG{blockNumber: ctx.BlockNumber}
+}
 
 func (p *randomPRNG) RequiredGas(input []byte) uint64 {
-	return uint64(1024)
+	return RandomPRNGGasCost
 }
 
 var randomPRNGABI = `[
@@ -36,6 +51,9 @@ var randomPRNGABI = `[
   }
 ]`
 
+// prngABI is the parsed form of randomPRNGABI.
+var prngABI = parseABI(randomPRNGABI)
+
 // parseABI parses the abijson string and returns the parsed abi object.
 func parseABI(abiJSON string) abi.ABI {
 	parsed, err := abi.JSON(strings.NewReader(abiJSON))
@@ -47,38 +65,26 @@ func parseABI(abiJSON string) abi.ABI {
 }
 
 func packRandomPRNGOutput(result *big.Int) ([]byte, error) {
-	parsedABI := parseABI(randomPRNGABI)
-	return parsedABI.Methods["randomPRNG"].Outputs.Pack(result)
+	return prngABI.Methods["randomPRNG"].Outputs.Pack(result)
 }
 
-// getRandomNumber generates a pseudo-random big.Int within the range of int64 using math/rand
-func getRandomNumber(blockNumber uint64) *big.Int {
-	// Seed the random generator with blockNumber for deterministic randomness
-	source := rand.NewSource(int64(blockNumber))
-	rng := rand.New(source)
+// randomPRNGDomain separates the hashes of randomPRNG from any other keccak of a block number.
+const randomPRNGDomain = "randomPRNG"
 
-	// Generate a random number in the range of [0, math.MaxInt64]
-	randomValue := rng.Int63()
-
-	return big.NewInt(randomValue)
+// getRandomNumber returns a pseudo-random value in [0, 2^256) for [blockNumber], as
+// keccak(randomPRNGDomain || blockNumber) with the block number encoded as a 32 byte
+// big-endian word. It is spelled out rather than left to a runtime PRNG, whose algorithm may
+// change between Go versions, so every node computes the same value for the same block.
+func getRandomNumber(blockNumber uint64) *big.Int {
+	return new(big.Int).SetBytes(crypto.Keccak256([]byte(randomPRNGDomain), common.BigToHash(new(big.Int).SetUint64(blockNumber)).Bytes()))
 }
 
 func (p *randomPRNG) Run(input []byte) ([]byte, error) {
 	if len(input) < 4 {
-		return nil, fmt.Errorf("Function selector is missing")
+		return nil, errMissingSelector
 	}
-
-	// Get the block number (you would need to pass this from the EVM context)
-	blockNumber := uint64(time.Now().Unix()) // Placeholder for block number, replace in a real environment
-
-	// Generate a random number based on the block number
-	resultBigInt := getRandomNumber(blockNumber)
-
-	// Pack resultBigInt into output
-	output, err := packRandomPRNGOutput(resultBigInt)
-	if err != nil {
-		return nil, err
+	if p.blockNumber == nil {
+		return nil, errNoBlockContext
 	}
-
-	return output, nil
+	return packRandomPRNGOutput(getRandomNumber(p.blockNumber.Uint64()))
 }
@@ -0,0 +1,80 @@
+package vm
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// callRandomPRNG calls randomPRNG through a Prague EVM executing block [blockNumber].
+func callRandomPRNG(t *testing.T, blockNumber int64) []byte {
+	t.Helper()
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	vmctx := BlockContext{
+		CanTransfer: func(StateDB, common.Address, *uint256.Int) bool { return true },
+		Transfer:    func(StateDB, common.Address, common.Address, *uint256.Int) {},
+		BlockNumber: big.NewInt(blockNumber),
+		Random:      &common.Hash{},
+	}
+	evm := NewEVM(vmctx, TxContext{}, statedb, params.MergedTestChainConfig, Config{})
+	if !evm.chainRules.IsPrague {
+		t.Fatal("test chain is not on Prague")
+	}
+	input := prngABI.Methods["randomPRNG"].ID
+	ret, gas, err := evm.Call(AccountRef(common.Address{}), randomPRNGContractAddr, input, RandomPRNGGasCost, new(uint256.Int))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gas != 0 {
+		t.Fatalf("got %d gas left, want 0", gas)
+	}
+	return ret
+}
+
+func TestRandomPRNGDeterministic(t *testing.T) {
+	first, second := callRandomPRNG(t, 42), callRandomPRNG(t, 42)
+	if !bytes.Equal(first, second) {
+		t.Fatalf("same block yielded %x and %x", first, second)
+	}
+	want, err := packRandomPRNGOutput(getRandomNumber(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, want) {
+		t.Fatalf("got %x, want %x", first, want)
+	}
+	if other := callRandomPRNG(t, 43); bytes.Equal(first, other) {
+		t.Fatalf("blocks 42 and 43 yielded the same value %x", first)
+	}
+}
+
+func TestRandomPRNGNoBlockContext(t *testing.T) {
+	input := prngABI.Methods["randomPRNG"].ID
+	if _, _, err := RunPrecompiledContract(PrecompiledContractsPrague[randomPRNGContractAddr], input, RandomPRNGGasCost, nil); err != errNoBlockContext {
+		t.Fatalf("got %v, want %v", err, errNoBlockContext)
+	}
+}
+
+func TestGetRandomNumberPinned(t *testing.T) {
+	// These values are part of consensus: a change here forks every chain using randomPRNG.
+	tests := []struct {
+		blockNumber uint64
+		want        string
+	}{
+		{0, "0x5a46968ef6f41a68f06f22ba55b6eb058da5c219781707f2715788ede0ec0079"},
+		{1, "0xb9b68021821d429d5736d396d8c49a9e42bc66f9115020e7f91f2602bbbd875c"},
+		{42, "0x89a6d4b3ddb086e323e91f03c6ee6c6716e69fdf8c7c0d638ff5fa8f8102d56e"},
+		{1 << 40, "0xe4d7ffe42d0a6549e4e1d7557123b15635cfe01b61aafc06855e8348df5da11b"},
+	}
+	for _, tt := range tests {
+		if got := common.BigToHash(getRandomNumber(tt.blockNumber)).Hex(); got != tt.want {
+			t.Errorf("block %d: got %s, want %s", tt.blockNumber, got, tt.want)
+		}
+	}
+}
@@ -42,6 +42,9 @@ type (
 
 func (evm *EVM) precompile(addr common.Address) (PrecompiledContract, bool) {
 	p, ok := evm.precompiles[addr]
+	if bp, isBlockPrecompile := p.(blockPrecompile); isBlockPrecompile {
+		p = bp.atBlock(evm.Context)
+	}
 	return p, ok
 }
 
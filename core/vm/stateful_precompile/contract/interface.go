@@ -36,6 +36,7 @@ type StateDB interface {
 	SetPredicateStorageSlots(address common.Address, predicates [][]byte)
 
 	GetTxHash() common.Hash
+	GetTxIndex() int
 
 	Snapshot() int
 	RevertToSnapshot(int)
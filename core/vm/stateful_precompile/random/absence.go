@@ -0,0 +1,177 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var errValuePresent = errors.New("value is part of the draw")
+
+// AbsenceProof shows that a value is not part of a draw committed to by a sorted merkle root.
+// The sorted values below Position are smaller than the value and the ones from Position on
+// are larger, so the value is absent if its two neighbours are proven adjacent.
+type AbsenceProof struct {
+	// Count is the number of values of the draw.
+	Count uint64
+	// Position is the number of values of the draw smaller than the absent value.
+	Position uint64
+	// Low is the largest smaller value, at Position-1, and LowProof its merkle proof. Both are
+	// ignored when Position is 0.
+	Low      *big.Int
+	LowProof []common.Hash
+	// High is the smallest larger value, at Position, and HighProof its merkle proof. Both are
+	// ignored when Position is Count.
+	High      *big.Int
+	HighProof []common.Hash
+}
+
+func PackProveAbsenceInput(value *big.Int) ([]byte, error) {
+	return randomABI.Pack("proveAbsence", value)
+}
+
+func UnpackProveAbsenceInput(input []byte) (*big.Int, error) {
+	var value *big.Int
+	if err := unpackInput("proveAbsence", input, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func PackProveAbsenceOutput(proof AbsenceProof) ([]byte, error) {
+	return randomABI.Methods["proveAbsence"].Outputs.Pack(
+		new(big.Int).SetUint64(proof.Count), new(big.Int).SetUint64(proof.Position),
+		proof.Low, hashesToWords(proof.LowProof), proof.High, hashesToWords(proof.HighProof),
+	)
+}
+
+func PackSortedMerkleRootOutput(root common.Hash) ([]byte, error) {
+	return randomABI.Methods["sortedMerkleRoot"].Outputs.Pack([32]byte(root))
+}
+
+// hashesToWords converts [hashes] to the form the ABI encoder expects for bytes32[].
+func hashesToWords(hashes []common.Hash) [][32]byte {
+	words := make([][32]byte, len(hashes))
+	for i, h := range hashes {
+		words[i] = h
+	}
+	return words
+}
+
+// sortedMerkleRoot returns the root committing to a draw of [count] values sorted in increasing
+// order with their tree [levels]: keccak(count || tree root). The count is part of the root so
+// that proofs can show that a value is the last one.
+func sortedMerkleRoot(count uint64, levels [][]common.Hash) common.Hash {
+	return crypto.Keccak256Hash(common.BigToHash(new(big.Int).SetUint64(count)).Bytes(), levels[len(levels)-1][0].Bytes())
+}
+
+// sortedMerkleTree sorts [values] in increasing order and returns them with their merkle tree,
+// whose leaves commit to each value at its sorted position.
+func sortedMerkleTree(values []*big.Int) ([]*big.Int, [][]common.Hash) {
+	sorted := make([]*big.Int, len(values))
+	copy(sorted, values)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	leaves := make([]common.Hash, len(sorted))
+	for i, v := range sorted {
+		leaves[i] = merkleLeaf(uint64(i), v)
+	}
+	return sorted, merkleTree(leaves)
+}
+
+// proveAbsence returns the proof that [value] is not among [sorted], or errValuePresent.
+func proveAbsence(sorted []*big.Int, levels [][]common.Hash, value *big.Int) (AbsenceProof, error) {
+	count := uint64(len(sorted))
+	position := uint64(sort.Search(len(sorted), func(i int) bool { return sorted[i].Cmp(value) >= 0 }))
+	if position < count && sorted[position].Cmp(value) == 0 {
+		return AbsenceProof{}, errValuePresent
+	}
+	proof := AbsenceProof{Count: count, Position: position, Low: new(big.Int), High: new(big.Int)}
+	if position > 0 {
+		proof.Low, proof.LowProof = sorted[position-1], merkleProof(levels, position-1)
+	}
+	if position < count {
+		proof.High, proof.HighProof = sorted[position], merkleProof(levels, position)
+	}
+	return proof, nil
+}
+
+// VerifyAbsenceProof reports whether [proof] shows that [value] is not part of the draw
+// committed to by the sorted merkle [root].
+func VerifyAbsenceProof(root common.Hash, value *big.Int, proof AbsenceProof) bool {
+	if proof.Count == 0 || proof.Position > proof.Count {
+		return false
+	}
+	// matches reports whether [neighbour] is proven at [index] of the committed draw.
+	matches := func(index uint64, neighbour *big.Int, siblings []common.Hash) bool {
+		node, ok := merkleRootFromProof(index, neighbour, siblings)
+		return ok && crypto.Keccak256Hash(common.BigToHash(new(big.Int).SetUint64(proof.Count)).Bytes(), node.Bytes()) == root
+	}
+	if proof.Position > 0 && (proof.Low.Cmp(value) >= 0 || !matches(proof.Position-1, proof.Low, proof.LowProof)) {
+		return false
+	}
+	if proof.Position < proof.Count && (proof.High.Cmp(value) <= 0 || !matches(proof.Position, proof.High, proof.HighProof)) {
+		return false
+	}
+	return true
+}
+
+// loadSortedMerkleDraw rebuilds the sorted tree of the draw last committed by [caller] through
+// randomMerkleRoot, charging the same gas as proveValue.
+func loadSortedMerkleDraw(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, suppliedGas uint64) ([]*big.Int, [][]common.Hash, uint64, error) {
+	n, nonce := loadMerkleDraw(accessibleState.GetStateDB(), addr, caller)
+	if n == 0 {
+		return nil, nil, suppliedGas, errNoMerkleDraw
+	}
+	remainingGas, err := contract.DeductGas(suppliedGas, RandomMerkleBaseGas+n*RandomMerklePerValueGas)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	values, _ := generateMerkleDraw(newCallerStreamAt(addr, caller, nonce), n)
+	sorted, levels := sortedMerkleTree(values)
+	return sorted, levels, remainingGas, nil
+}
+
+// SortedMerkleRootFunc returns the sorted merkle root of the draw last committed by the caller
+// through randomMerkleRoot, against which absence proofs are verified.
+func SortedMerkleRootFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	sorted, levels, remainingGas, err := loadSortedMerkleDraw(accessibleState, caller, addr, suppliedGas)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	ret, err = PackSortedMerkleRootOutput(sortedMerkleRoot(uint64(len(sorted)), levels))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
+
+func ProveAbsenceFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	value, err := UnpackProveAbsenceInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	sorted, levels, remainingGas, err := loadSortedMerkleDraw(accessibleState, caller, addr, suppliedGas)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	proof, err := proveAbsence(sorted, levels, value)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	ret, err = PackProveAbsenceOutput(proof)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,82 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+// wordsToHashes converts a bytes32[] output back to hashes.
+func wordsToHashes(words [][32]byte) []common.Hash {
+	hashes := make([]common.Hash, len(words))
+	for i, w := range words {
+		hashes[i] = w
+	}
+	return hashes
+}
+
+// runProveAbsence requests an absence proof of [value] for the draw of [testCaller].
+func runProveAbsence(state *mockAccessibleState, value *big.Int) (AbsenceProof, error) {
+	out, _, err := runMethod(state, testCaller, "proveAbsence", value)
+	if err != nil {
+		return AbsenceProof{}, err
+	}
+	return AbsenceProof{
+		Count:     out[0].(*big.Int).Uint64(),
+		Position:  out[1].(*big.Int).Uint64(),
+		Low:       out[2].(*big.Int),
+		LowProof:  wordsToHashes(out[3].([][32]byte)),
+		High:      out[4].(*big.Int),
+		HighProof: wordsToHashes(out[5].([][32]byte)),
+	}, nil
+}
+
+func TestProveAbsence(t *testing.T) {
+	const n = 13
+	state := newMockAccessibleState()
+	if _, err := runProveAbsence(state, big.NewInt(1)); err != errNoMerkleDraw {
+		t.Fatalf("got %v, want %v", err, errNoMerkleDraw)
+	}
+	mustRunMethod(t, state, testCaller, "randomMerkleRoot", big.NewInt(n))
+	root := common.Hash(mustRunMethod(t, state, testCaller, "sortedMerkleRoot")[0].([32]byte))
+
+	values := newCallerStream(randomNCSPRNGContractAddr, testCaller, state.state).values(n)
+	sorted, _ := sortedMerkleTree(values)
+
+	// Values below, between and above the draw.
+	absent := []*big.Int{
+		new(big.Int),
+		new(big.Int).Add(sorted[0], common.Big1),
+		new(big.Int).Sub(sorted[n/2], common.Big1),
+		math.MaxBig256,
+	}
+	for _, value := range absent {
+		proof, err := runProveAbsence(state, value)
+		if err != nil {
+			t.Fatalf("value %x: %v", value, err)
+		}
+		if !VerifyAbsenceProof(root, value, proof) {
+			t.Fatalf("value %x: valid absence proof rejected", value)
+		}
+	}
+
+	for _, value := range values {
+		if _, err := runProveAbsence(state, value); err != errValuePresent {
+			t.Fatalf("present value %x: got %v, want %v", value, err, errValuePresent)
+		}
+	}
+
+	// A proof for an absent value cannot be reused for a present neighbour.
+	proof, err := runProveAbsence(state, absent[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if VerifyAbsenceProof(root, sorted[n/2], proof) || VerifyAbsenceProof(root, sorted[n/2-1], proof) {
+		t.Fatalf("absence proof accepted for a present value")
+	}
+}
@@ -0,0 +1,92 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomAffineGasCost = 1024
+)
+
+var (
+	// AffineLinearBound bounds the linear coefficients a, b, c and d, which are drawn from
+	// [-1, 1] in 1e18 fixed point.
+	AffineLinearBound = big.NewInt(1e18)
+	// AffineTranslationBound bounds the translation e and f, which are drawn from [-100, 100]
+	// in 1e18 fixed point.
+	AffineTranslationBound = new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18))
+)
+
+// AffineTransform holds the parameters of the map (x, y) -> (a*x + b*y + e, c*x + d*y + f),
+// each in 1e18 fixed point.
+type AffineTransform struct {
+	A *big.Int
+	B *big.Int
+	C *big.Int
+	D *big.Int
+	E *big.Int
+	F *big.Int
+}
+
+func PackRandomAffineInput() ([]byte, error) {
+	return randomABI.Pack("randomAffine")
+}
+
+func PackRandomAffineOutput(t AffineTransform) ([]byte, error) {
+	return randomABI.Methods["randomAffine"].Outputs.Pack(t.A, t.B, t.C, t.D, t.E, t.F)
+}
+
+func UnpackRandomAffineOutput(data []byte) (AffineTransform, error) {
+	var t AffineTransform
+	outputs := randomABI.Methods["randomAffine"].Outputs
+	values, err := outputs.Unpack(data)
+	if err != nil {
+		return AffineTransform{}, err
+	}
+	if err := outputs.Copy(&t, values); err != nil {
+		return AffineTransform{}, err
+	}
+	return t, nil
+}
+
+// symmetric draws a value uniformly from [-bound, bound].
+func symmetric(stream *randomStream, bound *big.Int) *big.Int {
+	width := new(big.Int).Lsh(bound, 1)
+	v := stream.uniform(width.Add(width, common.Big1))
+	return v.Sub(v, bound)
+}
+
+// generateRandomAffine draws the six parameters in order a, b, c, d, e, f.
+func generateRandomAffine(stream *randomStream) AffineTransform {
+	return AffineTransform{
+		A: symmetric(stream, AffineLinearBound),
+		B: symmetric(stream, AffineLinearBound),
+		C: symmetric(stream, AffineLinearBound),
+		D: symmetric(stream, AffineLinearBound),
+		E: symmetric(stream, AffineTranslationBound),
+		F: symmetric(stream, AffineTranslationBound),
+	}
+}
+
+func RandomAffineFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomAffineGasCost); err != nil {
+		return nil, 0, err
+	}
+	if len(input) != 0 {
+		return nil, remainingGas, errInvalidInputLength
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackRandomAffineOutput(generateRandomAffine(stream))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,50 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRandomAffine(t *testing.T) {
+	state := newMockAccessibleState()
+	precompile := CreateRandomNCSPRNGPrecompile(DefaultConfig())
+	input, err := PackRandomAffineInput()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(nonce uint64) AffineTransform {
+		state.state.SetNonce(testCaller, nonce)
+		ret, _, err := precompile.Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		transform, err := UnpackRandomAffineOutput(ret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return transform
+	}
+	inRange := func(v, bound *big.Int) bool {
+		return v.CmpAbs(bound) <= 0
+	}
+	for nonce := uint64(0); nonce < 50; nonce++ {
+		tr := run(nonce)
+		for _, v := range []*big.Int{tr.A, tr.B, tr.C, tr.D} {
+			if !inRange(v, AffineLinearBound) {
+				t.Fatalf("nonce %d: linear coefficient %v out of range", nonce, v)
+			}
+		}
+		for _, v := range []*big.Int{tr.E, tr.F} {
+			if !inRange(v, AffineTranslationBound) {
+				t.Fatalf("nonce %d: translation %v out of range", nonce, v)
+			}
+		}
+		if again := run(nonce); again.A.Cmp(tr.A) != 0 || again.F.Cmp(tr.F) != 0 {
+			t.Fatalf("nonce %d: transform not reproducible", nonce)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/crypto/chacha20"
+)
+
+// Identifiers of the derivation algorithms selectable through randomWithAlgo. Every algorithm
+// derives its words from the same server seed, user seed and caller nonce as randomNCSPRNG.
+const (
+	// AlgoHMACSHA256 yields the words of the counter-mode HMAC-SHA256 stream, exactly the
+	// values returned by randomNCSPRNG.
+	AlgoHMACSHA256 = 0
+	// AlgoChaCha20 yields consecutive 32 byte blocks of the ChaCha20 keystream keyed by
+	// keccak(serverSeed || userSeed), with the caller nonce as the 12 byte big-endian nonce.
+	AlgoChaCha20 = 1
+	// AlgoKeccak yields keccak(serverSeed || userSeed || nonce || i) for the i-th word, with
+	// the nonce and counter encoded as 32 byte big-endian words, which can be recomputed with
+	// keccak256 alone, e.g. from Solidity.
+	AlgoKeccak = 2
+)
+
+const (
+	RandomWithAlgoBaseGas = 1024
+)
+
+var errUnknownAlgorithm = errors.New("unknown algorithm identifier")
+
+// RandomWithAlgoInput is the input of the randomWithAlgo method.
+type RandomWithAlgoInput struct {
+	AlgoId *big.Int
+	N      *big.Int
+}
+
+func PackRandomWithAlgoInput(algoId *big.Int, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomWithAlgo", algoId, n)
+}
+
+func UnpackRandomWithAlgoInput(input []byte) (RandomWithAlgoInput, error) {
+	var in RandomWithAlgoInput
+	if err := unpackInput("randomWithAlgo", input, &in); err != nil {
+		return RandomWithAlgoInput{}, err
+	}
+	if !in.AlgoId.IsUint64() || in.AlgoId.Uint64() > AlgoKeccak {
+		return RandomWithAlgoInput{}, errUnknownAlgorithm
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return RandomWithAlgoInput{}, errTooManyValues
+	}
+	return in, nil
+}
+
+func PackRandomWithAlgoOutput(randomValues []*big.Int, algoId *big.Int) ([]byte, error) {
+	return randomABI.Methods["randomWithAlgo"].Outputs.Pack(randomValues, algoId)
+}
+
+// generateChaCha20 returns the first [n] 32 byte blocks of the ChaCha20 keystream, see
+// AlgoChaCha20.
+func generateChaCha20(serverSeed []byte, userSeed []byte, nonce uint64, n uint64) ([]*big.Int, error) {
+	cipher, err := chacha20.NewUnauthenticatedCipher(crypto.Keccak256(serverSeed, userSeed), new(big.Int).SetUint64(nonce).FillBytes(make([]byte, chacha20.NonceSize)))
+	if err != nil {
+		return nil, err
+	}
+	keystream := make([]byte, n*common.HashLength)
+	cipher.XORKeyStream(keystream, keystream)
+
+	values := make([]*big.Int, n)
+	for i := range values {
+		values[i] = new(big.Int).SetBytes(keystream[i*common.HashLength : (i+1)*common.HashLength])
+	}
+	return values, nil
+}
+
+// generateKeccak returns the first [n] words of the keccak construction, see AlgoKeccak.
+func generateKeccak(serverSeed []byte, userSeed []byte, nonce uint64, n uint64) []*big.Int {
+	nonceWord := common.BigToHash(new(big.Int).SetUint64(nonce)).Bytes()
+	values := make([]*big.Int, n)
+	for i := range values {
+		counter := common.BigToHash(big.NewInt(int64(i))).Bytes()
+		values[i] = new(big.Int).SetBytes(crypto.Keccak256(serverSeed, userSeed, nonceWord, counter))
+	}
+	return values
+}
+
+// generateWithAlgo returns [n] values of [caller] at its current nonce derived with [algoId].
+func generateWithAlgo(precompileAddr common.Address, caller common.Address, algoId uint64, n uint64, state contract.StateDB) ([]*big.Int, error) {
+	key := activeServerSeed(state, precompileAddr)
+	seed, nonce := userSeed(key, caller), state.GetNonce(caller)
+	switch algoId {
+	case AlgoHMACSHA256:
+		return newRandomStream(key, seed, nonce).values(n), nil
+	case AlgoChaCha20:
+		return generateChaCha20(key, seed, nonce, n)
+	case AlgoKeccak:
+		return generateKeccak(key, seed, nonce, n), nil
+	default:
+		return nil, errUnknownAlgorithm
+	}
+}
+
+func RandomWithAlgoFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackRandomWithAlgoInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	n := in.N.Uint64()
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomWithAlgoBaseGas+n*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	values, err := generateWithAlgo(addr, caller, in.AlgoId.Uint64(), n, accessibleState.GetStateDB())
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	ret, err = PackRandomWithAlgoOutput(values, in.AlgoId)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,55 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestRandomWithAlgo(t *testing.T) {
+	const n = 4
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 9)
+
+	key := serverSeed(randomNCSPRNGContractAddr)
+	seed := userSeed(key, testCaller)
+	nonceWord := common.BigToHash(big.NewInt(9)).Bytes()
+	stream := newCallerStream(randomNCSPRNGContractAddr, testCaller, state.state)
+
+	// The first word of each algorithm, computed independently of the implementation.
+	wants := map[int64]*big.Int{
+		AlgoHMACSHA256: stream.next(),
+		AlgoKeccak:     new(big.Int).SetBytes(crypto.Keccak256(key, seed, nonceWord, common.Hash{}.Bytes())),
+	}
+
+	firsts := make(map[string]bool)
+	for _, algoId := range []int64{AlgoHMACSHA256, AlgoChaCha20, AlgoKeccak} {
+		out := mustRunMethod(t, state, testCaller, "randomWithAlgo", big.NewInt(algoId), big.NewInt(n))
+		values, got := out[0].([]*big.Int), out[1].(*big.Int)
+		if got.Int64() != algoId {
+			t.Errorf("algorithm %d: output reports algorithm %v", algoId, got)
+		}
+		if len(values) != n {
+			t.Fatalf("algorithm %d: got %d values, want %d", algoId, len(values), n)
+		}
+		if want, ok := wants[algoId]; ok && values[0].Cmp(want) != 0 {
+			t.Errorf("algorithm %d: got first value %x, want %x", algoId, values[0], want)
+		}
+		firsts[values[0].String()] = true
+	}
+	if len(firsts) != 3 {
+		t.Errorf("algorithms do not produce distinct values")
+	}
+}
+
+func TestRandomWithAlgoUnknown(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "randomWithAlgo", big.NewInt(AlgoKeccak+1), big.NewInt(1)); err != errUnknownAlgorithm {
+		t.Fatalf("got %v, want %v", err, errUnknownAlgorithm)
+	}
+}
@@ -0,0 +1,154 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	AntiClusteredBaseGas = 1024 + (MaxAntiClusterMemory+1)*(contract.ReadGasCostPerSlot+contract.WriteGasCostPerSlot)
+	// AntiClusteredPerValueGas covers the expected resampling of every value.
+	AntiClusteredPerValueGas = 4 * RandomPerValueGas
+
+	// MaxAntiClusterMemory bounds the number of past draws remembered per caller.
+	MaxAntiClusterMemory = 8
+	// maxAntiClusterAttempts bounds the resampling of a single value. With at most
+	// MaxAntiClusterMemory remembered draws at least 1/9 of the range is always free, so
+	// exhausting it happens with probability below (8/9)^256.
+	maxAntiClusterAttempts = 256
+)
+
+var (
+	errInvalidAntiClusterMemory = errors.New("memory exceeds MaxAntiClusterMemory")
+	errZeroRange                = errors.New("range must be non-zero")
+	errAntiClusterExhausted     = errors.New("no well-spaced value found")
+)
+
+// AntiClusteredInput is the input of the antiClustered method.
+type AntiClusteredInput struct {
+	Max    *big.Int
+	Memory *big.Int
+	N      *big.Int
+}
+
+func PackAntiClusteredInput(max *big.Int, memory *big.Int, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("antiClustered", max, memory, n)
+}
+
+func UnpackAntiClusteredInput(input []byte) (AntiClusteredInput, error) {
+	var in AntiClusteredInput
+	if err := unpackInput("antiClustered", input, &in); err != nil {
+		return AntiClusteredInput{}, err
+	}
+	if in.Max.Sign() == 0 {
+		return AntiClusteredInput{}, errZeroRange
+	}
+	if !in.Memory.IsUint64() || in.Memory.Uint64() > MaxAntiClusterMemory {
+		return AntiClusteredInput{}, errInvalidAntiClusterMemory
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return AntiClusteredInput{}, errTooManyValues
+	}
+	return in, nil
+}
+
+func PackAntiClusteredOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["antiClustered"].Outputs.Pack(randomValues)
+}
+
+// antiClusterSpacing returns the minimum distance a new draw in [0, max) must keep from each
+// of the last [memory] draws. The distance metric is the absolute difference |a - b| on the
+// integer line (no wrap-around). The spacing is max / (2 * (memory + 1)), so the remembered
+// draws exclude less than memory/(memory+1) of the range and resampling always terminates.
+func antiClusterSpacing(max *big.Int, memory uint64) *big.Int {
+	return new(big.Int).Div(max, new(big.Int).SetUint64(2*(memory+1)))
+}
+
+// loadAntiClusterHistory returns the remembered draws of [caller], oldest first.
+func loadAntiClusterHistory(state contract.StateDB, addr common.Address, caller common.Address) []*big.Int {
+	count := state.GetState(addr, stateKey("anticluster.count", caller.Bytes())).Big().Uint64()
+	history := make([]*big.Int, count)
+	for i := range history {
+		history[i] = state.GetState(addr, stateKey("anticluster.value", caller.Bytes(), common.BigToHash(big.NewInt(int64(i))).Bytes())).Big()
+	}
+	return history
+}
+
+// storeAntiClusterHistory remembers the last MaxAntiClusterMemory entries of [history].
+func storeAntiClusterHistory(state contract.StateDB, addr common.Address, caller common.Address, history []*big.Int) {
+	if len(history) > MaxAntiClusterMemory {
+		history = history[len(history)-MaxAntiClusterMemory:]
+	}
+	for i, v := range history {
+		state.SetState(addr, stateKey("anticluster.value", caller.Bytes(), common.BigToHash(big.NewInt(int64(i))).Bytes()), common.BigToHash(v))
+	}
+	state.SetState(addr, stateKey("anticluster.count", caller.Bytes()), common.BigToHash(big.NewInt(int64(len(history)))))
+}
+
+// generateAntiClustered draws [n] values in [0, max), resampling every draw closer than the
+// anti-cluster spacing to any of the last [memory] entries of [history]. Accepted draws are
+// appended to the returned history.
+func generateAntiClustered(stream *randomStream, history []*big.Int, max *big.Int, memory uint64, n uint64) ([]*big.Int, []*big.Int, error) {
+	spacing := antiClusterSpacing(max, memory)
+	values := make([]*big.Int, n)
+	distance := new(big.Int)
+	for i := range values {
+		recent := history[uint64(len(history))-min(uint64(len(history)), memory):]
+		for attempt := 0; ; attempt++ {
+			if attempt == maxAntiClusterAttempts {
+				return nil, nil, errAntiClusterExhausted
+			}
+			candidate := stream.uniform(max)
+			clustered := false
+			for _, prev := range recent {
+				if distance.Sub(candidate, prev).Abs(distance).Cmp(spacing) < 0 {
+					clustered = true
+					break
+				}
+			}
+			if !clustered {
+				values[i] = candidate
+				break
+			}
+		}
+		history = append(history, values[i])
+	}
+	return values, history, nil
+}
+
+func AntiClusteredFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackAntiClusteredInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	n := in.N.Uint64()
+	if remainingGas, err = contract.DeductGas(suppliedGas, AntiClusteredBaseGas+n*AntiClusteredPerValueGas); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+
+	state := accessibleState.GetStateDB()
+	history := loadAntiClusterHistory(state, addr, caller)
+	stream := newCallerStream(addr, caller, state)
+	values, history, err := generateAntiClustered(stream, history, in.Max, in.Memory.Uint64(), n)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	storeAntiClusterHistory(state, addr, caller, history)
+
+	ret, err = PackAntiClusteredOutput(values)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,45 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestAntiClusteredSpacing(t *testing.T) {
+	const memory = 4
+	state := newMockAccessibleState()
+	limit := big.NewInt(1000)
+	spacing := antiClusterSpacing(limit, memory)
+
+	// Draws of consecutive calls must stay apart too, as the history lives in state.
+	var all []*big.Int
+	for nonce := uint64(0); nonce < 10; nonce++ {
+		state.state.SetNonce(testCaller, nonce)
+		values := mustRunMethod(t, state, testCaller, "antiClustered", limit, big.NewInt(memory), big.NewInt(20))[0].([]*big.Int)
+		all = append(all, values...)
+	}
+	for i, v := range all {
+		if v.Cmp(limit) >= 0 {
+			t.Fatalf("value %d: %v out of range", i, v)
+		}
+		for j := max(0, i-memory); j < i; j++ {
+			if d := new(big.Int).Sub(v, all[j]); d.Abs(d).Cmp(spacing) < 0 {
+				t.Fatalf("value %d (%v) within %v of value %d (%v)", i, v, spacing, j, all[j])
+			}
+		}
+	}
+}
+
+func TestAntiClusteredReadOnly(t *testing.T) {
+	state := newMockAccessibleState()
+	input, _ := PackAntiClusteredInput(big.NewInt(10), big.NewInt(1), big.NewInt(1))
+	if _, _, err := CreateRandomNCSPRNGPrecompile(DefaultConfig()).Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, true); err == nil {
+		t.Fatalf("static call succeeded")
+	}
+	if len(state.state.storage) != 0 {
+		t.Fatalf("static call wrote state")
+	}
+}
@@ -0,0 +1,49 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// BlockAuditGasCost is charged by randomNCSPRNG draws that are not read-only for checking and
+// recording the last audited block when the block audit log is enabled.
+const BlockAuditGasCost = contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot
+
+// auditedBlockKey is the slot holding one plus the number of the last block a
+// BlockEntropyAudit event was emitted for, or zero if none was.
+var auditedBlockKey = stateKey("audit.block")
+
+// auditBlockEntropy emits the BlockEntropyAudit event of the block of [blockCtx] unless it was already
+// emitted. The event records the entropy anchors every randomNCSPRNG draw of the block is
+// derived from: the hash of the server seed, which commits to it without disclosing a
+// configured seed, the EntropySource bits of the seed and the rolling commitment the block
+// starts from.
+func auditBlockEntropy(state contract.StateDB, precompileAddr common.Address, blockCtx *vm.BlockContext, o options) error {
+	blockNumber := blockCtx.BlockNumber.Uint64()
+	marker := common.BigToHash(new(big.Int).SetUint64(blockNumber + 1))
+	if state.GetState(precompileAddr, auditedBlockKey) == marker {
+		return nil
+	}
+	state.SetState(precompileAddr, auditedBlockKey, marker)
+
+	key, sources := ncsprngServerSeed(state, precompileAddr, blockCtx, o)
+	event := randomABI.Events["BlockEntropyAudit"]
+	data, err := event.Inputs.NonIndexed().Pack(
+		crypto.Keccak256Hash(key),
+		new(big.Int).SetUint64(sources),
+		state.GetState(precompileAddr, rollingCommitmentKey),
+	)
+	if err != nil {
+		return err
+	}
+	topics := []common.Hash{event.ID, common.BigToHash(new(big.Int).SetUint64(blockNumber))}
+	state.AddLog(precompileAddr, topics, data, blockNumber)
+	return nil
+}
@@ -0,0 +1,55 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestBlockAuditLog(t *testing.T) {
+	state := newMockAccessibleState()
+	event := randomABI.Events["BlockEntropyAudit"]
+
+	var commitments []common.Hash
+	for block := int64(1); block <= 3; block++ {
+		state.blockCtx.BlockNumber = big.NewInt(block)
+		commitments = append(commitments, state.state.GetState(randomNCSPRNGContractAddr, rollingCommitmentKey))
+		for nonce := uint64(0); nonce < 3; nonce++ {
+			state.state.SetNonce(testCaller, uint64(block)*10+nonce)
+			// Draws of the last block are all read-only and must not be audited.
+			runRandomNCSPRNG(t, state, 2, block == 3, WithBlockAuditLog())
+		}
+	}
+
+	topics, data := state.state.GetLogData()
+	var audited []int64
+	for i := range topics {
+		if topics[i][0] != event.ID {
+			continue
+		}
+		block := topics[i][1].Big().Int64()
+		audited = append(audited, block)
+
+		out, err := event.Inputs.NonIndexed().Unpack(data[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := common.Hash(out[0].([32]byte)), crypto.Keccak256Hash(serverSeed(randomNCSPRNGContractAddr)); got != want {
+			t.Errorf("block %d: server seed hash %v, want %v", block, got, want)
+		}
+		if got := out[1].(*big.Int).Uint64(); got != EntropySourceDefaultSeed {
+			t.Errorf("block %d: sources %b, want %b", block, got, EntropySourceDefaultSeed)
+		}
+		if got, want := common.Hash(out[2].([32]byte)), commitments[block-1]; got != want {
+			t.Errorf("block %d: commitment %v, want the one the block started from %v", block, got, want)
+		}
+	}
+	if len(audited) != 2 || audited[0] != 1 || audited[1] != 2 {
+		t.Fatalf("audited blocks %v, want [1 2]", audited)
+	}
+}
@@ -0,0 +1,181 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Beacon signatures are checked with the BLS12-381 implementation of gnark-crypto, the library
+// behind the BLS12-381 precompiles of the EVM, so the method adds no dependency to the node.
+// Signatures are minimal-pubkey-size BLS signatures: public keys are compressed G1 points,
+// signatures compressed G2 points over the beacon round hashed to G2 with beaconSignatureDST.
+// A round is its number as an 8 byte big-endian word.
+const (
+	// VerifyBeaconAndDrawBaseGas covers hashing the round to G2, a two pair pairing check and
+	// recording the round used by the caller.
+	VerifyBeaconAndDrawBaseGas = 2*params.Bls12381MapG2Gas + params.Bls12381PairingBaseGas + 2*params.Bls12381PairingPerPairGas + contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot
+
+	// BeaconRoundLength is the length of an encoded beacon round.
+	BeaconRoundLength = 8
+)
+
+// beaconSignatureDST is the hash to curve domain separation tag of the beacon signatures, the
+// one of the IETF BLS signature scheme with signatures in G2.
+var beaconSignatureDST = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_NUL_")
+
+// Every published round stays verifiable forever, so a signature alone would let a caller
+// replay whichever past round gives it a favourable draw. Rounds are therefore bound to the
+// chain twice: a round must not be older than the BeaconSchedule allows at the time of the
+// block, and every caller must use strictly increasing rounds, so none is used twice.
+var (
+	errNoBeaconPublicKey      = errors.New("precompile has no beacon public key")
+	errNoBeaconSchedule       = errors.New("precompile has no beacon schedule")
+	errInvalidBeaconRound     = errors.New("beacon round must be an 8 byte big-endian round number")
+	errStaleBeaconRound       = errors.New("beacon round is too old for the block time")
+	errBeaconRoundUsed        = errors.New("beacon round is not newer than the last one used by caller")
+	errInvalidBeaconSignature = errors.New("invalid beacon signature")
+)
+
+// BeaconSchedule describes when a randomness beacon publishes its rounds: round 1 at
+// GenesisTime, then one round every Period seconds, as drand does.
+type BeaconSchedule struct {
+	GenesisTime uint64
+	Period      uint64
+
+	// MaxLag is the number of rounds a round may lag behind the latest one at the time of the
+	// block, to leave time for the transaction using it to be included. A caller can choose
+	// among at most MaxLag+1 rounds.
+	MaxLag uint64
+}
+
+// latestRound returns the latest round published at [time], 0 before the genesis.
+func (s BeaconSchedule) latestRound(time uint64) uint64 {
+	if time < s.GenesisTime {
+		return 0
+	}
+	return (time-s.GenesisTime)/s.Period + 1
+}
+
+// isStale reports whether [round] lags more than MaxLag rounds behind the latest round at [time].
+func (s BeaconSchedule) isStale(round uint64, time uint64) bool {
+	latest := s.latestRound(time)
+	return latest > s.MaxLag && round < latest-s.MaxLag
+}
+
+// VerifyBeaconAndDrawInput is the input of the verifyBeaconAndDraw method.
+type VerifyBeaconAndDrawInput struct {
+	BeaconRound  []byte
+	BlsSignature []byte
+	N            *big.Int
+}
+
+func PackVerifyBeaconAndDrawInput(beaconRound []byte, blsSignature []byte, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("verifyBeaconAndDraw", beaconRound, blsSignature, n)
+}
+
+func UnpackVerifyBeaconAndDrawInput(input []byte) (VerifyBeaconAndDrawInput, error) {
+	var in VerifyBeaconAndDrawInput
+	if err := unpackInput("verifyBeaconAndDraw", input, &in); err != nil {
+		return VerifyBeaconAndDrawInput{}, err
+	}
+	if len(in.BeaconRound) != BeaconRoundLength {
+		return VerifyBeaconAndDrawInput{}, errInvalidBeaconRound
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return VerifyBeaconAndDrawInput{}, errTooManyValues
+	}
+	return in, nil
+}
+
+func PackVerifyBeaconAndDrawOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["verifyBeaconAndDraw"].Outputs.Pack(randomValues)
+}
+
+// verifyBeaconSignature reports whether [signature] is a valid signature of [round] by
+// [publicKey], i.e. e(publicKey, H(round)) == e(g1, signature).
+func verifyBeaconSignature(publicKey *bls12381.G1Affine, round []byte, signature []byte) bool {
+	if len(signature) != bls12381.SizeOfG2AffineCompressed {
+		return false
+	}
+	var sig bls12381.G2Affine
+	if _, err := sig.SetBytes(signature); err != nil || sig.IsInfinity() {
+		return false
+	}
+	message, err := bls12381.HashToG2(round, beaconSignatureDST)
+	if err != nil {
+		return false
+	}
+	_, _, g1, _ := bls12381.Generators()
+	var negG1 bls12381.G1Affine
+	negG1.Neg(&g1)
+	ok, err := bls12381.PairingCheck([]bls12381.G1Affine{*publicKey, negG1}, []bls12381.G2Affine{message, sig})
+	return err == nil && ok
+}
+
+// beaconRoundKey returns the slot holding the last beacon round used by [caller].
+func beaconRoundKey(caller common.Address) common.Hash {
+	return stateKey("beacon.round", caller.Bytes())
+}
+
+// newBeaconStream returns the stream of the verified beacon [signature] for [caller]. BLS
+// signatures are unique, so the signature of a round is as unpredictable as the round itself
+// until the beacon publishes it, and nobody can grind it.
+func newBeaconStream(precompileAddr common.Address, caller common.Address, signature []byte) *randomStream {
+	return newKeyedStream(precompileAddr, "verifyBeaconAndDraw", append(caller.Bytes(), signature...))
+}
+
+// NewVerifyBeaconAndDrawFunc returns the verifyBeaconAndDraw handler, which returns [n] values
+// derived from a round of the randomness beacon signing with [publicKey] on [schedule], once it
+// verified the signature of the round and that the round is fresh and new for the caller.
+// Anyone can rerun the draw from the published round.
+func NewVerifyBeaconAndDrawFunc(publicKey *bls12381.G1Affine, schedule *BeaconSchedule) contract.RunStatefulPrecompileFunc {
+	return func(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		in, err := UnpackVerifyBeaconAndDrawInput(input)
+		if err != nil {
+			return nil, suppliedGas, err
+		}
+		n := in.N.Uint64()
+		if remainingGas, err = contract.DeductGas(suppliedGas, VerifyBeaconAndDrawBaseGas+n*RandomPerValueGas); err != nil {
+			return nil, 0, err
+		}
+		if readOnly {
+			return nil, remainingGas, vm.ErrWriteProtection
+		}
+		if publicKey == nil {
+			return nil, remainingGas, errNoBeaconPublicKey
+		}
+		if schedule == nil || schedule.Period == 0 {
+			return nil, remainingGas, errNoBeaconSchedule
+		}
+
+		round := binary.BigEndian.Uint64(in.BeaconRound)
+		if schedule.isStale(round, accessibleState.GetBlockContext().Time) {
+			return nil, remainingGas, errStaleBeaconRound
+		}
+		state := accessibleState.GetStateDB()
+		if round <= state.GetState(addr, beaconRoundKey(caller)).Big().Uint64() {
+			return nil, remainingGas, errBeaconRoundUsed
+		}
+		if !verifyBeaconSignature(publicKey, in.BeaconRound, in.BlsSignature) {
+			return nil, remainingGas, errInvalidBeaconSignature
+		}
+		state.SetState(addr, beaconRoundKey(caller), common.BigToHash(new(big.Int).SetUint64(round)))
+
+		ret, err = PackVerifyBeaconAndDrawOutput(newBeaconStream(addr, caller, in.BlsSignature).values(n))
+		if err != nil {
+			return nil, remainingGas, err
+		}
+
+		return ret, remainingGas, nil
+	}
+}
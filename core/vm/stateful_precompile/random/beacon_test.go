@@ -0,0 +1,133 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// testBeaconSchedule publishes round 34 at the time of the mock block, and accepts rounds 32
+// to 34 in it.
+var testBeaconSchedule = BeaconSchedule{GenesisTime: 1_700_000_000 - 33*30, Period: 30, MaxLag: 2}
+
+// beaconRound returns the encoding of round [number].
+func beaconRound(number uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, number)
+}
+
+// signBeaconRound returns the public key of [secretKey] and its signature of [round].
+func signBeaconRound(t *testing.T, secretKey *big.Int, round []byte) (*bls12381.G1Affine, []byte) {
+	t.Helper()
+	_, _, g1, _ := bls12381.Generators()
+	publicKey := new(bls12381.G1Affine).ScalarMultiplication(&g1, secretKey)
+	message, err := bls12381.HashToG2(round, beaconSignatureDST)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := new(bls12381.G2Affine).ScalarMultiplication(&message, secretKey).Bytes()
+	return publicKey, signature[:]
+}
+
+func runVerifyBeaconAndDraw(state *mockAccessibleState, publicKey *bls12381.G1Affine, round, signature []byte, n int64, opts ...Option) ([]*big.Int, error) {
+	input, err := PackVerifyBeaconAndDrawInput(round, signature, big.NewInt(n))
+	if err != nil {
+		return nil, err
+	}
+	opts = append([]Option{WithBeaconPublicKey(publicKey), WithBeaconSchedule(testBeaconSchedule)}, opts...)
+	ret, _, err := CreateRandomNCSPRNGPrecompile(DefaultConfig(), opts...).Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, false)
+	if err != nil {
+		return nil, err
+	}
+	out, err := randomABI.Methods["verifyBeaconAndDraw"].Outputs.Unpack(ret)
+	if err != nil {
+		return nil, err
+	}
+	return out[0].([]*big.Int), nil
+}
+
+func TestVerifyBeaconAndDraw(t *testing.T) {
+	state := newMockAccessibleState()
+	round := beaconRound(34)
+	publicKey, signature := signBeaconRound(t, big.NewInt(0x5ec7e7), round)
+
+	values, err := runVerifyBeaconAndDraw(state, publicKey, round, signature, 3)
+	if err != nil {
+		t.Fatalf("valid signature rejected: %v", err)
+	}
+	stream := newBeaconStream(randomNCSPRNGContractAddr, testCaller, signature)
+	for i, v := range values {
+		if want := stream.next(); v.Cmp(want) != 0 {
+			t.Fatalf("value %d: got %x, want %x", i, v, want)
+		}
+	}
+
+	// A signature of another round, or by another key, is rejected.
+	state = newMockAccessibleState()
+	_, otherRoundSignature := signBeaconRound(t, big.NewInt(0x5ec7e7), beaconRound(33))
+	_, otherKeySignature := signBeaconRound(t, big.NewInt(0xbad), round)
+	for name, sig := range map[string][]byte{
+		"other round": otherRoundSignature,
+		"other key":   otherKeySignature,
+		"truncated":   signature[:len(signature)-1],
+		"garbage":     make([]byte, len(signature)),
+	} {
+		if _, err := runVerifyBeaconAndDraw(state, publicKey, round, sig, 3); err != errInvalidBeaconSignature {
+			t.Errorf("%s: got %v, want %v", name, err, errInvalidBeaconSignature)
+		}
+	}
+
+	if _, err := runVerifyBeaconAndDraw(state, nil, round, signature, 3); err != errNoBeaconPublicKey {
+		t.Errorf("without key: got %v, want %v", err, errNoBeaconPublicKey)
+	}
+	if _, err := runVerifyBeaconAndDraw(state, publicKey, round, signature, 3, WithBeaconSchedule(BeaconSchedule{})); err != errNoBeaconSchedule {
+		t.Errorf("without schedule: got %v, want %v", err, errNoBeaconSchedule)
+	}
+	if _, err := runVerifyBeaconAndDraw(state, publicKey, []byte("beacon round 34"), signature, 3); err != errInvalidBeaconRound {
+		t.Errorf("unnumbered round: got %v, want %v", err, errInvalidBeaconRound)
+	}
+}
+
+func TestVerifyBeaconAndDrawReplay(t *testing.T) {
+	state := newMockAccessibleState()
+	secretKey := big.NewInt(0x5ec7e7)
+	draw := func(number uint64) error {
+		publicKey, signature := signBeaconRound(t, secretKey, beaconRound(number))
+		_, err := runVerifyBeaconAndDraw(state, publicKey, beaconRound(number), signature, 1)
+		return err
+	}
+
+	// Rounds published long before the block cannot be picked from history.
+	if err := draw(31); err != errStaleBeaconRound {
+		t.Fatalf("stale round: got %v, want %v", err, errStaleBeaconRound)
+	}
+	if err := draw(32); err != nil {
+		t.Fatalf("oldest fresh round: %v", err)
+	}
+	// A round cannot be used twice, nor can an older one be used after it.
+	if err := draw(32); err != errBeaconRoundUsed {
+		t.Fatalf("replayed round: got %v, want %v", err, errBeaconRoundUsed)
+	}
+	if err := draw(34); err != nil {
+		t.Fatalf("newer round: %v", err)
+	}
+	if err := draw(33); err != errBeaconRoundUsed {
+		t.Fatalf("older round: got %v, want %v", err, errBeaconRoundUsed)
+	}
+
+	// Recording the round writes state, so static calls are rejected.
+	publicKey, signature := signBeaconRound(t, secretKey, beaconRound(35))
+	input, err := PackVerifyBeaconAndDrawInput(beaconRound(35), signature, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	precompile := CreateRandomNCSPRNGPrecompile(DefaultConfig(), WithBeaconPublicKey(publicKey), WithBeaconSchedule(testBeaconSchedule))
+	if _, _, err := precompile.Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, true); err != vm.ErrWriteProtection {
+		t.Fatalf("static call: got %v, want %v", err, vm.ErrWriteProtection)
+	}
+}
@@ -0,0 +1,57 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// BlockSeedGasCost is charged by randomNCSPRNG for reading and rotating the block seed when
+// per-block seeds are enabled.
+const BlockSeedGasCost = 2*contract.ReadGasCostPerSlot + 2*contract.WriteGasCostPerSlot
+
+// The block seed is a hash chain kept in the precompile storage: the first randomNCSPRNG draw of
+// every block replaces the stored seed with keccak(seed || blockNumber), starting from the zero
+// seed, and records the block it was rotated in so later draws of the block reuse it.
+var (
+	blockSeedKey      = stateKey("blockseed.seed")
+	blockSeedBlockKey = stateKey("blockseed.block")
+)
+
+// nextBlockSeed returns the block seed following [seed] in the block [blockNumber].
+func nextBlockSeed(seed common.Hash, blockNumber uint64) common.Hash {
+	return crypto.Keccak256Hash(seed.Bytes(), common.BigToHash(new(big.Int).SetUint64(blockNumber)).Bytes())
+}
+
+// blockSeedServerSeed returns the server seed [seed] once the stored block seed is mixed in,
+// together with the EntropySource bits it adds, when per-block seeds are enabled in [o]. Until
+// the first rotation the seed is left unchanged; afterwards it is keccak(seed || blockSeed).
+// The stored seed is read as is, so a read-only call in a block that has not rotated it yet is
+// keyed with the seed of the last block that did.
+func blockSeedServerSeed(state contract.StateDB, precompileAddr common.Address, seed []byte, o options) ([]byte, uint64) {
+	if !o.blockSeed {
+		return seed, 0
+	}
+	blockSeed := state.GetState(precompileAddr, blockSeedKey)
+	if blockSeed == (common.Hash{}) {
+		return seed, EntropySourceBlockSeed
+	}
+	return crypto.Keccak256(seed, blockSeed.Bytes()), EntropySourceBlockSeed
+}
+
+// rotateBlockSeed advances the block seed to the block [blockNumber] unless it was already
+// rotated in it. The block is stored as blockNumber+1 so that block 0 differs from an empty
+// slot.
+func rotateBlockSeed(state contract.StateDB, precompileAddr common.Address, blockNumber uint64) {
+	rotatedIn := common.BigToHash(new(big.Int).SetUint64(blockNumber + 1))
+	if state.GetState(precompileAddr, blockSeedBlockKey) == rotatedIn {
+		return
+	}
+	state.SetState(precompileAddr, blockSeedKey, nextBlockSeed(state.GetState(precompileAddr, blockSeedKey), blockNumber))
+	state.SetState(precompileAddr, blockSeedBlockKey, rotatedIn)
+}
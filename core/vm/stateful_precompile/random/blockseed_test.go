@@ -0,0 +1,64 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRandomNCSPRNGBlockSeed(t *testing.T) {
+	state := newMockAccessibleState()
+	state.blockCtx.BlockNumber = big.NewInt(10)
+	storedSeed := func() common.Hash { return state.state.GetState(randomNCSPRNGContractAddr, blockSeedKey) }
+
+	// The first draw of the block rotates the seed from zero.
+	first := runRandomNCSPRNG(t, state, 2, false, WithBlockSeed())
+	seed10 := nextBlockSeed(common.Hash{}, 10)
+	if got := storedSeed(); got != seed10 {
+		t.Fatalf("got seed %x after the first draw, want %x", got, seed10)
+	}
+	plain := runRandomNCSPRNG(t, state, 2, true)
+	if first[0].Cmp(plain[0]) == 0 {
+		t.Fatal("block seed did not change the draw")
+	}
+
+	// Later draws of the block reuse it.
+	if again := runRandomNCSPRNG(t, state, 2, false, WithBlockSeed()); again[0].Cmp(first[0]) != 0 {
+		t.Fatal("second draw of the block yielded different values at the same nonce")
+	}
+	if got := storedSeed(); got != seed10 {
+		t.Fatalf("second draw of the block rotated the seed to %x", got)
+	}
+
+	// A static call in the next block reads the seed as last rotated and leaves it alone.
+	state.blockCtx.BlockNumber = big.NewInt(11)
+	if static := runRandomNCSPRNG(t, state, 2, true, WithBlockSeed()); static[0].Cmp(first[0]) != 0 {
+		t.Fatal("static call was not keyed with the last rotated seed")
+	}
+	if got := storedSeed(); got != seed10 {
+		t.Fatalf("static call rotated the seed to %x", got)
+	}
+
+	// The first draw of the next block rotates it from the previous one.
+	next := runRandomNCSPRNG(t, state, 2, false, WithBlockSeed())
+	if got, want := storedSeed(), nextBlockSeed(seed10, 11); got != want {
+		t.Fatalf("got seed %x in the next block, want %x", got, want)
+	}
+	if next[0].Cmp(first[0]) == 0 {
+		t.Fatal("draw did not change with the block")
+	}
+
+	// The witness captures the stored seed.
+	w := NewRandomnessWitness(state.state, randomNCSPRNGContractAddr, testCaller, state.blockCtx, 2, WithBlockSeed())
+	want, err := ComputeFromWitness(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want[0].Cmp(next[0]) != 0 {
+		t.Errorf("witness computed %x, want %x", want[0], next[0])
+	}
+}
@@ -0,0 +1,143 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	CommitBoardGasCost   = 1024 + contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot
+	RevealCellBaseGas    = 1024 + contract.ReadGasCostPerSlot
+	RevealCellPerNodeGas = 64
+)
+
+// Hidden-information games, e.g. battleship, commit to a whole board up front and reveal it cell
+// by cell. Cell i holding value v is hidden behind the leaf keccak(v || salt || i), with a
+// secret salt per cell, and the board commitment is the root of the merkle tree over the
+// leaves, built like the ones of randomMerkleRoot. A game contract publishes the root with
+// commitBoard before play starts; revealCell then only accepts a cell proven to belong to it,
+// so the board cannot be changed mid-game. The single cell signature of a reveal cannot be
+// bound to one commitment on its own, hence the merkle proof it takes.
+var (
+	errBoardCommitted     = errors.New("a board is already committed for caller")
+	errNoBoardCommitment  = errors.New("no board committed for caller")
+	errInvalidCellReveal  = errors.New("cell reveal does not match the committed board")
+	errCellIndexTooLarge  = errors.New("cell index does not fit in a uint64")
+	errBoardProofTooShort = errors.New("proof does not reach the root for the cell index")
+)
+
+// CommitBoardInput is the input of the commitBoard method.
+type CommitBoardInput struct {
+	BoardCommitment [32]byte
+}
+
+// RevealCellInput is the input of the revealCell method.
+type RevealCellInput struct {
+	CellIndex *big.Int
+	Value     *big.Int
+	Salt      [32]byte
+	Proof     [][32]byte
+}
+
+func PackCommitBoardInput(boardCommitment common.Hash) ([]byte, error) {
+	return randomABI.Pack("commitBoard", [32]byte(boardCommitment))
+}
+
+func UnpackCommitBoardInput(input []byte) (common.Hash, error) {
+	var in CommitBoardInput
+	if err := unpackInput("commitBoard", input, &in); err != nil {
+		return common.Hash{}, err
+	}
+	return in.BoardCommitment, nil
+}
+
+func PackRevealCellInput(cellIndex *big.Int, value *big.Int, salt common.Hash, proof []common.Hash) ([]byte, error) {
+	return randomABI.Pack("revealCell", cellIndex, value, [32]byte(salt), hashesToWords(proof))
+}
+
+// UnpackRevealCellInput returns the index of the cell, its value, its salt and its proof.
+func UnpackRevealCellInput(input []byte) (uint64, *big.Int, common.Hash, []common.Hash, error) {
+	var in RevealCellInput
+	if err := unpackInput("revealCell", input, &in); err != nil {
+		return 0, nil, common.Hash{}, nil, err
+	}
+	if !in.CellIndex.IsUint64() {
+		return 0, nil, common.Hash{}, nil, errCellIndexTooLarge
+	}
+	proof := make([]common.Hash, len(in.Proof))
+	for i, node := range in.Proof {
+		proof[i] = node
+	}
+	return in.CellIndex.Uint64(), in.Value, in.Salt, proof, nil
+}
+
+// BoardCellLeaf returns the leaf hiding [value] in cell [cellIndex] under [salt].
+func BoardCellLeaf(cellIndex uint64, value *big.Int, salt common.Hash) common.Hash {
+	return crypto.Keccak256Hash(common.BigToHash(value).Bytes(), salt.Bytes(), common.BigToHash(new(big.Int).SetUint64(cellIndex)).Bytes())
+}
+
+// boardCommitmentKey returns the slot holding the board committed by [caller].
+func boardCommitmentKey(caller common.Address) common.Hash {
+	return stateKey("board.commitment", caller.Bytes())
+}
+
+// CommitBoardFunc records the board commitment of the calling game. It cannot be replaced once
+// committed.
+func CommitBoardFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, CommitBoardGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+
+	boardCommitment, err := UnpackCommitBoardInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	if boardCommitment == (common.Hash{}) {
+		return nil, remainingGas, errZeroCommitment
+	}
+
+	state := accessibleState.GetStateDB()
+	if state.GetState(addr, boardCommitmentKey(caller)) != (common.Hash{}) {
+		return nil, remainingGas, errBoardCommitted
+	}
+	state.SetState(addr, boardCommitmentKey(caller), boardCommitment)
+
+	return []byte{}, remainingGas, nil
+}
+
+// RevealCellFunc succeeds if [value] and [salt] open cell [cellIndex] of the board committed
+// by the calling game, and fails with errInvalidCellReveal otherwise.
+func RevealCellFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	cellIndex, value, salt, proof, err := UnpackRevealCellInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, RevealCellBaseGas+uint64(len(proof))*RevealCellPerNodeGas); err != nil {
+		return nil, 0, err
+	}
+
+	boardCommitment := accessibleState.GetStateDB().GetState(addr, boardCommitmentKey(caller))
+	if boardCommitment == (common.Hash{}) {
+		return nil, remainingGas, errNoBoardCommitment
+	}
+	root, ok := merkleRootFromLeaf(cellIndex, BoardCellLeaf(cellIndex, value, salt), proof)
+	if !ok {
+		return nil, remainingGas, errBoardProofTooShort
+	}
+	if root != boardCommitment {
+		return nil, remainingGas, errInvalidCellReveal
+	}
+
+	return []byte{}, remainingGas, nil
+}
@@ -0,0 +1,78 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+func TestBoardReveal(t *testing.T) {
+	state := newMockAccessibleState()
+
+	// A 5x5 board with a ship on the cells of the main diagonal.
+	const cells = 25
+	values := make([]*big.Int, cells)
+	salts := make([]common.Hash, cells)
+	leaves := make([]common.Hash, cells)
+	for i := range leaves {
+		values[i] = new(big.Int)
+		if i%6 == 0 {
+			values[i].SetUint64(1)
+		}
+		salts[i] = common.BigToHash(big.NewInt(int64(1000 + i)))
+		leaves[i] = BoardCellLeaf(uint64(i), values[i], salts[i])
+	}
+	levels := merkleTree(leaves)
+	root := levels[len(levels)-1][0]
+
+	if _, _, err := runMethod(state, testCaller, "revealCell", big.NewInt(0), values[0], [32]byte(salts[0]), hashesToWords(merkleProof(levels, 0))); err != errNoBoardCommitment {
+		t.Fatalf("reveal before commit: got %v, want %v", err, errNoBoardCommitment)
+	}
+	mustRunMethod(t, state, testCaller, "commitBoard", [32]byte(root))
+	if _, _, err := runMethod(state, testCaller, "commitBoard", [32]byte(common.HexToHash("0xb0a2d"))); err != errBoardCommitted {
+		t.Fatalf("second commit: got %v, want %v", err, errBoardCommitted)
+	}
+
+	for i := range leaves {
+		mustRunMethod(t, state, testCaller, "revealCell", big.NewInt(int64(i)), values[i], [32]byte(salts[i]), hashesToWords(merkleProof(levels, uint64(i))))
+	}
+
+	// Claiming a miss on a hit, using another salt or moving a cell is rejected.
+	proof := hashesToWords(merkleProof(levels, 6))
+	tampered := []struct {
+		name  string
+		index int64
+		value *big.Int
+		salt  common.Hash
+	}{
+		{"value", 6, big.NewInt(0), salts[6]},
+		{"salt", 6, values[6], salts[7]},
+		{"index", 7, values[6], salts[6]},
+	}
+	for _, tt := range tampered {
+		if _, _, err := runMethod(state, testCaller, "revealCell", big.NewInt(tt.index), tt.value, [32]byte(tt.salt), proof); err != errInvalidCellReveal {
+			t.Errorf("tampered %s: got %v, want %v", tt.name, err, errInvalidCellReveal)
+		}
+	}
+
+	// Boards are kept per game.
+	other := common.HexToAddress("0x0be")
+	if _, _, err := runMethod(state, other, "revealCell", big.NewInt(6), values[6], [32]byte(salts[6]), proof); err != errNoBoardCommitment {
+		t.Errorf("reveal by another game: got %v, want %v", err, errNoBoardCommitment)
+	}
+}
+
+func TestCommitBoardReadOnly(t *testing.T) {
+	input, err := PackCommitBoardInput(common.HexToHash("0xb0a2d"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := CreateRandomNCSPRNGPrecompile(DefaultConfig()).Run(newMockAccessibleState(), testCaller, randomNCSPRNGContractAddr, input, testGas, true); err != vm.ErrWriteProtection {
+		t.Fatalf("got %v, want %v", err, vm.ErrWriteProtection)
+	}
+}
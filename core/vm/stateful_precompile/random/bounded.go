@@ -0,0 +1,95 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomBoundedBaseGas = 1024
+	// RandomBoundedDistinctPerValueGas covers the resampling of duplicates when distinct
+	// values are requested.
+	RandomBoundedDistinctPerValueGas = 8 * RandomPerValueGas
+)
+
+var errDistinctImpossible = errors.New("cannot draw more distinct values than the bound")
+
+// RandomBoundedInput is the input of the randomBounded method.
+type RandomBoundedInput struct {
+	Bound    *big.Int
+	N        *big.Int
+	Distinct bool
+}
+
+func PackRandomBoundedInput(bound *big.Int, n *big.Int, distinct bool) ([]byte, error) {
+	return randomABI.Pack("randomBounded", bound, n, distinct)
+}
+
+func UnpackRandomBoundedInput(input []byte) (RandomBoundedInput, error) {
+	var in RandomBoundedInput
+	if err := unpackInput("randomBounded", input, &in); err != nil {
+		return RandomBoundedInput{}, err
+	}
+	if in.Bound.Sign() == 0 {
+		return RandomBoundedInput{}, errZeroRange
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return RandomBoundedInput{}, errTooManyValues
+	}
+	if in.Distinct && in.N.Cmp(in.Bound) > 0 {
+		return RandomBoundedInput{}, errDistinctImpossible
+	}
+	return in, nil
+}
+
+func PackRandomBoundedOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomBounded"].Outputs.Pack(randomValues)
+}
+
+// generateRandomBounded draws [n] values uniformly from [0, bound). If [distinct] is set, a
+// draw equal to an earlier one of the same call is discarded and redrawn, so the returned
+// values are pairwise different. Values are never deduplicated across calls.
+func generateRandomBounded(stream *randomStream, bound *big.Int, n uint64, distinct bool) []*big.Int {
+	values := make([]*big.Int, 0, n)
+	seen := make(map[string]bool)
+	for uint64(len(values)) < n {
+		v := stream.uniform(bound)
+		if distinct {
+			if seen[string(v.Bytes())] {
+				continue
+			}
+			seen[string(v.Bytes())] = true
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+func RandomBoundedFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackRandomBoundedInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	perValueGas := uint64(RandomPerValueGas)
+	if in.Distinct {
+		perValueGas = RandomBoundedDistinctPerValueGas
+	}
+	n := in.N.Uint64()
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomBoundedBaseGas+n*perValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackRandomBoundedOutput(generateRandomBounded(stream, in.Bound, n, in.Distinct))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,42 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRandomBoundedDistinct(t *testing.T) {
+	state := newMockAccessibleState()
+
+	// Drawing the whole range forces many duplicates to be resampled.
+	for _, tt := range []struct{ bound, n int64 }{{10, 10}, {100, 60}, {1 << 40, 200}} {
+		values := mustRunMethod(t, state, testCaller, "randomBounded", big.NewInt(tt.bound), big.NewInt(tt.n), true)[0].([]*big.Int)
+		if int64(len(values)) != tt.n {
+			t.Fatalf("bound %d: got %d values, want %d", tt.bound, len(values), tt.n)
+		}
+		seen := make(map[int64]bool)
+		for _, v := range values {
+			if v.Int64() >= tt.bound || seen[v.Int64()] {
+				t.Fatalf("bound %d: value %v out of range or repeated", tt.bound, v)
+			}
+			seen[v.Int64()] = true
+		}
+	}
+
+	// Without the flag duplicates are kept.
+	values := mustRunMethod(t, state, testCaller, "randomBounded", big.NewInt(2), big.NewInt(20), false)[0].([]*big.Int)
+	if len(values) != 20 {
+		t.Fatalf("got %d values, want 20", len(values))
+	}
+}
+
+func TestRandomBoundedDistinctImpossible(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "randomBounded", big.NewInt(5), big.NewInt(6), true); err != errDistinctImpossible {
+		t.Fatalf("got %v, want %v", err, errDistinctImpossible)
+	}
+	mustRunMethod(t, state, testCaller, "randomBounded", big.NewInt(5), big.NewInt(6), false)
+}
@@ -0,0 +1,105 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomBoundedSumBaseGas = 1024
+	// RandomBoundedSumPerValueGas covers the draw of a cut point and its sorting.
+	RandomBoundedSumPerValueGas = 2 * RandomPerValueGas
+)
+
+var errBoundedSumOverflow = errors.New("cap + k overflows uint256")
+
+// RandomBoundedSumInput is the input of the randomBoundedSum method.
+type RandomBoundedSumInput struct {
+	Cap *big.Int
+	K   *big.Int
+}
+
+func PackRandomBoundedSumInput(cap *big.Int, k *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomBoundedSum", cap, k)
+}
+
+func UnpackRandomBoundedSumInput(input []byte) (RandomBoundedSumInput, error) {
+	var in RandomBoundedSumInput
+	if err := unpackInput("randomBoundedSum", input, &in); err != nil {
+		return RandomBoundedSumInput{}, err
+	}
+	if !in.K.IsUint64() || in.K.Uint64() > MaxRandomValues {
+		return RandomBoundedSumInput{}, errTooManyValues
+	}
+	// The cut points are drawn below cap + k, which must be a valid uniform bound.
+	if new(big.Int).Add(in.Cap, in.K).Cmp(two256) > 0 {
+		return RandomBoundedSumInput{}, errBoundedSumOverflow
+	}
+	return in, nil
+}
+
+func PackRandomBoundedSumOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomBoundedSum"].Outputs.Pack(randomValues)
+}
+
+// distinctSample returns [k] distinct values drawn uniformly from [0, n) in increasing order,
+// using Floyd's algorithm so that exactly k words are consumed whatever the size of n.
+// [k] must not exceed [n].
+func distinctSample(stream *randomStream, n *big.Int, k uint64) []*big.Int {
+	seen := make(map[common.Hash]bool, k)
+	sample := make([]*big.Int, 0, k)
+	j := new(big.Int).Sub(n, new(big.Int).SetUint64(k))
+	for i := uint64(0); i < k; i++ {
+		t := stream.uniform(new(big.Int).Add(j, common.Big1))
+		if seen[common.BigToHash(t)] {
+			t = new(big.Int).Set(j)
+		}
+		seen[common.BigToHash(t)] = true
+		sample = append(sample, t)
+		j.Add(j, common.Big1)
+	}
+	sort.Slice(sample, func(a, b int) bool { return sample[a].Cmp(sample[b]) < 0 })
+	return sample
+}
+
+// generateRandomBoundedSum draws [k] non-negative values whose sum is at most [cap], uniformly
+// among all such integer vectors. By stars and bars, those vectors are in bijection with the
+// k-subsets of [0, cap+k): with sorted cut points c_1 < ... < c_k, x_1 = c_1 and
+// x_i = c_i - c_i-1 - 1, leaving cap - sum(x) as unallocated slack.
+func generateRandomBoundedSum(stream *randomStream, cap *big.Int, k uint64) []*big.Int {
+	cuts := distinctSample(stream, new(big.Int).Add(cap, new(big.Int).SetUint64(k)), k)
+	values := make([]*big.Int, k)
+	previous := big.NewInt(-1)
+	for i, cut := range cuts {
+		values[i] = new(big.Int).Sub(cut, previous)
+		values[i].Sub(values[i], common.Big1)
+		previous = cut
+	}
+	return values
+}
+
+func RandomBoundedSumFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackRandomBoundedSumInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	k := in.K.Uint64()
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomBoundedSumBaseGas+k*RandomBoundedSumPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackRandomBoundedSumOutput(generateRandomBoundedSum(stream, in.Cap, k))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,61 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+func TestRandomBoundedSum(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, tc := range []struct{ cap, k int64 }{{0, 4}, {1, 1}, {100, 10}, {1_000_000, 64}} {
+		for nonce := uint64(0); nonce < 20; nonce++ {
+			state.state.SetNonce(testCaller, nonce)
+			values := mustRunMethod(t, state, testCaller, "randomBoundedSum", big.NewInt(tc.cap), big.NewInt(tc.k))[0].([]*big.Int)
+			if int64(len(values)) != tc.k {
+				t.Fatalf("cap %d: got %d values, want %d", tc.cap, len(values), tc.k)
+			}
+			sum := new(big.Int)
+			for _, v := range values {
+				if v.Sign() < 0 {
+					t.Fatalf("cap %d: negative value %v", tc.cap, v)
+				}
+				sum.Add(sum, v)
+			}
+			if sum.Cmp(big.NewInt(tc.cap)) > 0 {
+				t.Fatalf("cap %d: values %v sum to %v", tc.cap, values, sum)
+			}
+		}
+	}
+}
+
+func TestRandomBoundedSumCoverage(t *testing.T) {
+	// With cap 3 and k 2 the feasible region holds the 10 pairs (x, y) with x + y <= 3.
+	state := newMockAccessibleState()
+	counts := make(map[[2]int64]int)
+	for nonce := uint64(0); nonce < 2000; nonce++ {
+		state.state.SetNonce(testCaller, nonce)
+		values := mustRunMethod(t, state, testCaller, "randomBoundedSum", big.NewInt(3), big.NewInt(2))[0].([]*big.Int)
+		counts[[2]int64{values[0].Int64(), values[1].Int64()}]++
+	}
+	if len(counts) != 10 {
+		t.Fatalf("got %d distinct points, want all 10 of the feasible region", len(counts))
+	}
+	// Each point expects 200 draws with a standard deviation of about 13.
+	for point, count := range counts {
+		if count < 140 || count > 260 {
+			t.Errorf("point %v drawn %d times, expected about 200", point, count)
+		}
+	}
+}
+
+func TestRandomBoundedSumOverflow(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "randomBoundedSum", math.MaxBig256, big.NewInt(2)); err != errBoundedSumOverflow {
+		t.Fatalf("got %v, want %v", err, errBoundedSumOverflow)
+	}
+}
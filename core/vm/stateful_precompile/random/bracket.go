@@ -0,0 +1,99 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomBracketBaseGas = 1024
+
+	// MaxBracketPlayers bounds the number of players seeded by randomBracket.
+	MaxBracketPlayers = MaxRandomValues
+)
+
+var errInvalidPlayerCount = errors.New("number of players must be between 1 and MaxBracketPlayers")
+
+func PackRandomBracketInput(numPlayers *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomBracket", numPlayers)
+}
+
+func UnpackRandomBracketInput(input []byte) (uint64, error) {
+	var numPlayers *big.Int
+	if err := unpackInput("randomBracket", input, &numPlayers); err != nil {
+		return 0, err
+	}
+	if numPlayers.Sign() == 0 || !numPlayers.IsUint64() || numPlayers.Uint64() > MaxBracketPlayers {
+		return 0, errInvalidPlayerCount
+	}
+	return numPlayers.Uint64(), nil
+}
+
+func PackRandomBracketOutput(bracket []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomBracket"].Outputs.Pack(bracket)
+}
+
+// bracketSize returns the smallest power of two holding [numPlayers] players.
+func bracketSize(numPlayers uint64) uint64 {
+	if numPlayers <= 1 {
+		return 1
+	}
+	return 1 << bits.Len64(numPlayers-1)
+}
+
+// bracketSeedOrder returns the seeds, numbered from 1, of the slots of a standard bracket of
+// [size] slots: slots 2k and 2k+1 meet in the first round, seed s meets seed size+1-s, and the
+// two top seeds can only meet in the final.
+func bracketSeedOrder(size uint64) []uint64 {
+	order := []uint64{1}
+	for n := uint64(2); n <= size; n *= 2 {
+		next := make([]uint64, 0, n)
+		for _, seed := range order {
+			next = append(next, seed, n+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// generateRandomBracket shuffles players 1 to [numPlayers] into a random seeding and lays it
+// out as a standard bracket padded to a power of two. Seeds beyond numPlayers are byes,
+// encoded as 0: they only depend on numPlayers and always face the top seeds in the first
+// round, so no match opposes two byes.
+func generateRandomBracket(stream *randomStream, numPlayers uint64) []*big.Int {
+	seeding := stream.partialPermutation(numPlayers, numPlayers)
+	order := bracketSeedOrder(bracketSize(numPlayers))
+	bracket := make([]*big.Int, len(order))
+	for i, seed := range order {
+		bracket[i] = new(big.Int)
+		if seed <= numPlayers {
+			bracket[i].SetUint64(seeding[seed-1] + 1)
+		}
+	}
+	return bracket
+}
+
+func RandomBracketFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	numPlayers, err := UnpackRandomBracketInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomBracketBaseGas+bracketSize(numPlayers)*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackRandomBracketOutput(generateRandomBracket(stream, numPlayers))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
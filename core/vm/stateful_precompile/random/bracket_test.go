@@ -0,0 +1,64 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRandomBracket(t *testing.T) {
+	const numPlayers = 11
+	state := newMockAccessibleState()
+
+	var byes []int
+	for nonce := uint64(0); nonce < 16; nonce++ {
+		state.state.SetNonce(testCaller, nonce)
+		bracket := mustRunMethod(t, state, testCaller, "randomBracket", big.NewInt(numPlayers))[0].([]*big.Int)
+		if len(bracket) != 16 {
+			t.Fatalf("got a bracket of %d slots, want 16", len(bracket))
+		}
+
+		seen := make(map[int64]bool)
+		var slots []int
+		for i, player := range bracket {
+			if player.Sign() == 0 {
+				slots = append(slots, i)
+				continue
+			}
+			if player.Int64() > numPlayers || seen[player.Int64()] {
+				t.Fatalf("nonce %d: invalid or repeated player %v", nonce, player)
+			}
+			seen[player.Int64()] = true
+		}
+		if len(seen) != numPlayers {
+			t.Fatalf("nonce %d: got %d players, want %d", nonce, len(seen), numPlayers)
+		}
+		for _, slot := range slots {
+			if bracket[slot^1].Sign() == 0 {
+				t.Fatalf("nonce %d: two byes meet in slots %d and %d", nonce, slot, slot^1)
+			}
+		}
+
+		if byes == nil {
+			byes = slots
+			continue
+		}
+		if len(slots) != len(byes) {
+			t.Fatalf("nonce %d: bye slots %v differ from %v", nonce, slots, byes)
+		}
+		for i := range slots {
+			if slots[i] != byes[i] {
+				t.Fatalf("nonce %d: bye slots %v differ from %v", nonce, slots, byes)
+			}
+		}
+	}
+}
+
+func TestRandomBracketZeroPlayers(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "randomBracket", new(big.Int)); err != errInvalidPlayerCount {
+		t.Fatalf("got %v, want %v", err, errInvalidPlayerCount)
+	}
+}
@@ -0,0 +1,67 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	AbBucketGasCost = 1024
+)
+
+var errZeroBuckets = errors.New("number of buckets must be non-zero")
+
+// AbBucketInput is the input of the abBucket method.
+type AbBucketInput struct {
+	User       common.Address
+	NumBuckets *big.Int
+}
+
+func PackAbBucketInput(user common.Address, numBuckets *big.Int) ([]byte, error) {
+	return randomABI.Pack("abBucket", user, numBuckets)
+}
+
+func UnpackAbBucketInput(input []byte) (AbBucketInput, error) {
+	var in AbBucketInput
+	if err := unpackInput("abBucket", input, &in); err != nil {
+		return AbBucketInput{}, err
+	}
+	if in.NumBuckets.Sign() == 0 {
+		return AbBucketInput{}, errZeroBuckets
+	}
+	return in, nil
+}
+
+func PackAbBucketOutput(bucket *big.Int) ([]byte, error) {
+	return randomABI.Methods["abBucket"].Outputs.Pack(bucket)
+}
+
+// abBucket returns the bucket in [0, numBuckets) of [user]. It is drawn from a stream keyed by
+// the user alone, so it never changes for a given number of buckets, whoever asks and whenever.
+func abBucket(precompileAddr common.Address, user common.Address, numBuckets *big.Int) *big.Int {
+	return newKeyedStream(precompileAddr, "abBucket", user.Bytes()).uniform(numBuckets)
+}
+
+func AbBucketFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, AbBucketGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	in, err := UnpackAbBucketInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	ret, err = PackAbBucketOutput(abBucket(addr, in.User, in.NumBuckets))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
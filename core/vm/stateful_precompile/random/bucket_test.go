@@ -0,0 +1,60 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestAbBucketSticky(t *testing.T) {
+	state := newMockAccessibleState()
+	user := common.HexToAddress("0x00000000000000000000000000000000000beef0")
+
+	first := mustRunMethod(t, state, testCaller, "abBucket", user, big.NewInt(10))[0].(*big.Int)
+	for nonce := uint64(1); nonce < 5; nonce++ {
+		state.state.SetNonce(testCaller, nonce)
+		state.blockCtx.BlockNumber = new(big.Int).SetUint64(nonce)
+		if got := mustRunMethod(t, state, testCaller, "abBucket", user, big.NewInt(10))[0].(*big.Int); got.Cmp(first) != 0 {
+			t.Fatalf("nonce %d: bucket changed from %v to %v", nonce, first, got)
+		}
+	}
+	other := common.HexToAddress("0x00000000000000000000000000000000000beef1")
+	if got := mustRunMethod(t, state, other, "abBucket", user, big.NewInt(10))[0].(*big.Int); got.Cmp(first) != 0 {
+		t.Fatalf("bucket depends on the caller: %v != %v", got, first)
+	}
+}
+
+func TestAbBucketBalanced(t *testing.T) {
+	const (
+		users   = 4000
+		buckets = 4
+	)
+	state := newMockAccessibleState()
+
+	counts := make([]int, buckets)
+	for i := int64(0); i < users; i++ {
+		user := common.BigToAddress(big.NewInt(i + 1))
+		bucket := mustRunMethod(t, state, testCaller, "abBucket", user, big.NewInt(buckets))[0].(*big.Int)
+		if !bucket.IsInt64() || bucket.Int64() >= buckets {
+			t.Fatalf("bucket %v out of range", bucket)
+		}
+		counts[bucket.Int64()]++
+	}
+	// Each bucket expects 1000 users with a standard deviation of about 27.
+	for bucket, count := range counts {
+		if count < 880 || count > 1120 {
+			t.Errorf("bucket %d: got %d users, expected about %d", bucket, count, users/buckets)
+		}
+	}
+}
+
+func TestAbBucketZeroBuckets(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "abBucket", testCaller, new(big.Int)); err != errZeroBuckets {
+		t.Fatalf("got %v, want %v", err, errZeroBuckets)
+	}
+}
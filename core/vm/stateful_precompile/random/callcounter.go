@@ -0,0 +1,47 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// CallCounterGasCost is charged by randomNCSPRNG for reading and advancing the call counter of
+// the caller when per-call counters are enabled.
+const CallCounterGasCost = contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot
+
+// callCounterKey returns the slot holding the number of randomNCSPRNG draws made by [caller].
+func callCounterKey(caller common.Address) common.Hash {
+	return stateKey("call.counter", caller.Bytes())
+}
+
+// callCounterServerSeed returns the server seed [seed] of the streams of [caller] once its call
+// counter is mixed in, together with the EntropySource bits it adds, when per-call counters are
+// enabled in [o]. A caller that never drew keeps [seed] unchanged, so its first draw is the one
+// a precompile built without the option returns; later draws are keyed with
+// keccak(seed || caller || counter). The counter lives in the precompile storage rather than in
+// the account nonce, which the precompile must not alter behind the transaction processing.
+func callCounterServerSeed(state contract.StateDB, precompileAddr common.Address, caller common.Address, seed []byte, o options) ([]byte, uint64) {
+	if !o.callCounter {
+		return seed, 0
+	}
+	counter := state.GetState(precompileAddr, callCounterKey(caller))
+	if counter == (common.Hash{}) {
+		return seed, EntropySourceCallCounter
+	}
+	return crypto.Keccak256(seed, caller.Bytes(), counter.Bytes()), EntropySourceCallCounter
+}
+
+// advanceCallCounter increments the call counter of [caller], so that its next randomNCSPRNG
+// draw in the same transaction returns different values.
+func advanceCallCounter(state contract.StateDB, precompileAddr common.Address, caller common.Address) {
+	key := callCounterKey(caller)
+	counter := state.GetState(precompileAddr, key)
+	binary.BigEndian.PutUint64(counter[common.HashLength-8:], binary.BigEndian.Uint64(counter[common.HashLength-8:])+1)
+	state.SetState(precompileAddr, key, counter)
+}
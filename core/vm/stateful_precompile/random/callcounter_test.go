@@ -0,0 +1,88 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+func TestRandomNCSPRNGCallCounter(t *testing.T) {
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 3)
+
+	// The first draw matches a precompile built without the option.
+	plain := runRandomNCSPRNG(t, state, 4, true)
+	seen := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		values := runRandomNCSPRNG(t, state, 4, false, WithCallCounter())
+		if i == 0 {
+			for j := range values {
+				if values[j].Cmp(plain[j]) != 0 {
+					t.Fatalf("value %d: got %x, want %x", j, values[j], plain[j])
+				}
+			}
+		}
+		key := values[0].String()
+		if seen[key] {
+			t.Fatalf("draw %d repeated an earlier draw at the same nonce", i)
+		}
+		seen[key] = true
+	}
+	if got := state.state.GetState(randomNCSPRNGContractAddr, callCounterKey(testCaller)).Big(); got.Cmp(big.NewInt(3)) != 0 {
+		t.Errorf("got counter %d, want 3", got)
+	}
+
+	// The witness captures the counter, so the next draw can be recomputed off-chain.
+	w := NewRandomnessWitness(state.state, randomNCSPRNGContractAddr, testCaller, state.blockCtx, 4, WithCallCounter())
+	want, err := ComputeFromWitness(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := runRandomNCSPRNG(t, state, 4, false, WithCallCounter())
+	for j := range values {
+		if values[j].Cmp(want[j]) != 0 {
+			t.Errorf("value %d: got %x, want %x", j, values[j], want[j])
+		}
+	}
+}
+
+func TestRandomNCSPRNGCallCounterReadOnly(t *testing.T) {
+	state := newMockAccessibleState()
+	input, err := PackRandomNCSPRNGInput(big.NewInt(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := CreateRandomNCSPRNGPrecompile(DefaultConfig(), WithCallCounter()).Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, true); err != vm.ErrWriteProtection {
+		t.Fatalf("got %v, want %v", err, vm.ErrWriteProtection)
+	}
+	if len(state.state.storage) != 0 {
+		t.Errorf("static call wrote %d slots", len(state.state.storage))
+	}
+}
+
+func TestRandomNCSPRNGCallCounterFromSolidity(t *testing.T) {
+	state := newMockAccessibleState()
+	opts := []Option{WithCallCounter()}
+	// Both overloads are called without STATICCALL, so the counter advances on every call.
+	first, err := runMethodAsSolidity(state, testCaller, opts, "randomNCSPRNG", big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runMethodAsSolidity(state, testCaller, opts, "randomNCSPRNG0", big.NewInt(2), [32]byte{1}); err != nil {
+		t.Fatal(err)
+	}
+	if got := state.state.GetState(randomNCSPRNGContractAddr, callCounterKey(testCaller)).Big(); got.Cmp(big.NewInt(2)) != 0 {
+		t.Fatalf("call counter is %v after two calls, want 2", got)
+	}
+	again, err := runMethodAsSolidity(state, testCaller, opts, "randomNCSPRNG", big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again[0].([]*big.Int)[0].Cmp(first[0].([]*big.Int)[0]) == 0 {
+		t.Fatal("calls at the same nonce returned the same values")
+	}
+}
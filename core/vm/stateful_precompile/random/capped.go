@@ -0,0 +1,124 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	CappedWeightedPickBaseGas = 1024
+	// CappedWeightedPickPerWeightGas covers clamping a weight and adding it to the running sum.
+	CappedWeightedPickPerWeightGas = 16
+
+	// MaxCappedWeights bounds the number of weights of cappedWeightedPick.
+	MaxCappedWeights = MaxRandomValues
+)
+
+var (
+	errInvalidWeightCount = errors.New("number of weights must be between 1 and MaxRandomValues")
+	errZeroTotalWeight    = errors.New("weights sum to zero")
+)
+
+// CappedWeightedPickInput is the input of the cappedWeightedPick method.
+type CappedWeightedPickInput struct {
+	Weights   []*big.Int
+	MaxWeight *big.Int
+	N         *big.Int
+}
+
+func PackCappedWeightedPickInput(weights []*big.Int, maxWeight *big.Int, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("cappedWeightedPick", weights, maxWeight, n)
+}
+
+func UnpackCappedWeightedPickInput(input []byte) (CappedWeightedPickInput, error) {
+	var in CappedWeightedPickInput
+	if err := unpackInput("cappedWeightedPick", input, &in); err != nil {
+		return CappedWeightedPickInput{}, err
+	}
+	if len(in.Weights) == 0 || len(in.Weights) > MaxCappedWeights {
+		return CappedWeightedPickInput{}, errInvalidWeightCount
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return CappedWeightedPickInput{}, errTooManyValues
+	}
+	return in, nil
+}
+
+func PackCappedWeightedPickOutput(picks []*big.Int) ([]byte, error) {
+	return randomABI.Methods["cappedWeightedPick"].Outputs.Pack(picks)
+}
+
+// cappedCumulativeWeights clamps every weight of [weights] to [maxWeight] and returns their
+// running sums, see cumulativeWeights.
+func cappedCumulativeWeights(weights []*big.Int, maxWeight *big.Int) ([]*big.Int, error) {
+	capped := make([]*big.Int, len(weights))
+	for i, w := range weights {
+		if w.Cmp(maxWeight) > 0 {
+			w = maxWeight
+		}
+		capped[i] = w
+	}
+	return cumulativeWeights(capped)
+}
+
+// generateCappedWeightedPick draws [n] indices of [weights], each with probability
+// proportional to its weight clamped to [maxWeight], so no entity is picked more often than
+// maxWeight over the total clamped weight. Picks are independent, so an index can be picked
+// more than once.
+func generateCappedWeightedPick(stream *randomStream, weights []*big.Int, maxWeight *big.Int, n uint64) ([]*big.Int, error) {
+	cumulative, err := cappedCumulativeWeights(weights, maxWeight)
+	if err != nil {
+		return nil, err
+	}
+	total := cumulative[len(cumulative)-1]
+	if total.Sign() == 0 {
+		return nil, errZeroTotalWeight
+	}
+	return pickCumulative(stream, cumulative, n), nil
+}
+
+// pickCumulative draws [n] indices with probability proportional to the weights whose running
+// sums are [cumulative], which must end with a positive total. Every pick is located by binary
+// search in the running sums.
+func pickCumulative(stream *randomStream, cumulative []*big.Int, n uint64) []*big.Int {
+	total := cumulative[len(cumulative)-1]
+	picks := make([]*big.Int, n)
+	for i := range picks {
+		v := stream.uniform(total)
+		index := sort.Search(len(cumulative), func(j int) bool { return cumulative[j].Cmp(v) > 0 })
+		picks[i] = big.NewInt(int64(index))
+	}
+	return picks
+}
+
+// CappedWeightedPickFunc draws [n] weighted picks after clamping every weight to [maxWeight],
+// e.g. to keep any single staker from dominating a stake-weighted selection.
+func CappedWeightedPickFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackCappedWeightedPickInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	n := in.N.Uint64()
+	if remainingGas, err = contract.DeductGas(suppliedGas, CappedWeightedPickBaseGas+uint64(len(in.Weights))*CappedWeightedPickPerWeightGas+n*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	picks, err := generateCappedWeightedPick(stream, in.Weights, in.MaxWeight, n)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	ret, err = PackCappedWeightedPickOutput(picks)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
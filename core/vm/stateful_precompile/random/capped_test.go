@@ -0,0 +1,62 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+func TestCappedWeightedPick(t *testing.T) {
+	state := newMockAccessibleState()
+	// The whale holds 100 times the stake of the others, but is capped to their weight.
+	weights := []*big.Int{big.NewInt(1000), big.NewInt(10), big.NewInt(10), big.NewInt(10)}
+	picks := mustRunMethod(t, state, testCaller, "cappedWeightedPick", weights, big.NewInt(10), big.NewInt(MaxRandomValues))[0].([]*big.Int)
+	if len(picks) != MaxRandomValues {
+		t.Fatalf("got %d picks, want %d", len(picks), MaxRandomValues)
+	}
+	counts := make([]int, len(weights))
+	for _, p := range picks {
+		if !p.IsInt64() || p.Int64() >= int64(len(weights)) {
+			t.Fatalf("pick %v out of range", p)
+		}
+		counts[p.Int64()]++
+	}
+	// Each entity expects 256 picks with a standard deviation of about 14.
+	for i, c := range counts {
+		if c < 200 || c > 312 {
+			t.Errorf("entity %d picked %d times of %d, want about 256", i, c, MaxRandomValues)
+		}
+	}
+
+	// A zero weight is never picked, and a weight below the cap keeps its share.
+	weights = []*big.Int{big.NewInt(0), big.NewInt(5), big.NewInt(100)}
+	for _, p := range mustRunMethod(t, state, testCaller, "cappedWeightedPick", weights, big.NewInt(5), big.NewInt(100))[0].([]*big.Int) {
+		if p.Sign() == 0 {
+			t.Fatalf("picked an entity of zero weight")
+		}
+	}
+}
+
+func TestCappedWeightedPickInvalid(t *testing.T) {
+	state := newMockAccessibleState()
+	tests := []struct {
+		name      string
+		weights   []*big.Int
+		maxWeight *big.Int
+		want      error
+	}{
+		{"no weights", []*big.Int{}, big.NewInt(10), errInvalidWeightCount},
+		{"zero weights", []*big.Int{big.NewInt(0), big.NewInt(0)}, big.NewInt(10), errZeroTotalWeight},
+		{"zero cap", []*big.Int{big.NewInt(5)}, big.NewInt(0), errZeroTotalWeight},
+		{"overflow", []*big.Int{math.MaxBig256, math.MaxBig256}, math.MaxBig256, errWeightSumOverflow},
+	}
+	for _, tt := range tests {
+		if _, _, err := runMethod(state, testCaller, "cappedWeightedPick", tt.weights, tt.maxWeight, big.NewInt(1)); err != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	DealCardsGasCost = 4096
+
+	// DeckSize is the number of cards in the deck dealt by dealCards.
+	DeckSize = 52
+)
+
+var errTooManyCards = errors.New("cannot deal more cards than the deck holds")
+
+// DealCardsInput is the input of the dealCards method.
+type DealCardsInput struct {
+	NumCards *big.Int
+}
+
+func PackDealCardsInput(numCards *big.Int) ([]byte, error) {
+	return randomABI.Pack("dealCards", numCards)
+}
+
+func UnpackDealCardsInput(input []byte) (uint64, error) {
+	var in DealCardsInput
+	if err := unpackInput("dealCards", input, &in); err != nil {
+		return 0, err
+	}
+	if !in.NumCards.IsUint64() || in.NumCards.Uint64() > DeckSize {
+		return 0, errTooManyCards
+	}
+	return in.NumCards.Uint64(), nil
+}
+
+func PackDealCardsOutput(cards []*big.Int) ([]byte, error) {
+	return randomABI.Methods["dealCards"].Outputs.Pack(cards)
+}
+
+// generateDealCards deals [numCards] distinct cards off a shuffled deck of DeckSize cards.
+func generateDealCards(stream *randomStream, numCards uint64) []*big.Int {
+	cards := make([]*big.Int, numCards)
+	for i, card := range stream.partialPermutation(DeckSize, numCards) {
+		cards[i] = new(big.Int).SetUint64(card)
+	}
+	return cards
+}
+
+func DealCardsFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, DealCardsGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	numCards, err := UnpackDealCardsInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackDealCardsOutput(generateDealCards(stream, numCards))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,52 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestDealCards(t *testing.T) {
+	const (
+		hand  = 13
+		deals = 1000
+	)
+	state := newMockAccessibleState()
+
+	var counts [DeckSize]int
+	for nonce := uint64(0); nonce < deals; nonce++ {
+		state.state.SetNonce(testCaller, nonce)
+		out := mustRunMethod(t, state, testCaller, "dealCards", big.NewInt(hand))
+		cards := out[0].([]*big.Int)
+		if len(cards) != hand {
+			t.Fatalf("dealt %d cards, want %d", len(cards), hand)
+		}
+		seen := make(map[uint64]bool)
+		for _, card := range cards {
+			c := card.Uint64()
+			if c >= DeckSize || seen[c] {
+				t.Fatalf("nonce %d: invalid or repeated card %d in %v", nonce, c, cards)
+			}
+			seen[c] = true
+			counts[c]++
+		}
+	}
+	// Each card is expected 250 times with a standard deviation of about 15.
+	for card, count := range counts {
+		if count < 175 || count > 325 {
+			t.Errorf("card %d dealt %d times, expected about 250", card, count)
+		}
+	}
+}
+
+func TestDealCardsTooMany(t *testing.T) {
+	state := newMockAccessibleState()
+	if out := mustRunMethod(t, state, testCaller, "dealCards", big.NewInt(DeckSize)); len(out[0].([]*big.Int)) != DeckSize {
+		t.Fatalf("full deck not dealt")
+	}
+	if _, _, err := runMethod(state, testCaller, "dealCards", big.NewInt(DeckSize+1)); err != errTooManyCards {
+		t.Fatalf("got %v, want %v", err, errTooManyCards)
+	}
+}
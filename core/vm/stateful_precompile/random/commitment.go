@@ -0,0 +1,57 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// RollingCommitmentGasCost is charged to randomNCSPRNG calls that update the commitment.
+	RollingCommitmentGasCost = contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot
+	GetCommitmentGasCost     = contract.ReadGasCostPerSlot
+)
+
+// rollingCommitmentKey is the slot holding the rolling commitment to every value returned by
+// randomNCSPRNG outside of read-only calls.
+var rollingCommitmentKey = stateKey("commitment.rolling")
+
+// RollingCommitment returns the commitment following [commitment] once [values] were drawn,
+// keccak(commitment || values) with every value encoded as a 32 byte big-endian word. Replaying
+// it over every draw in order, starting from the zero hash, yields the stored commitment.
+func RollingCommitment(commitment common.Hash, values []*big.Int) common.Hash {
+	words := make([]byte, 0, len(values)*common.HashLength)
+	for _, v := range values {
+		words = append(words, common.BigToHash(v).Bytes()...)
+	}
+	return crypto.Keccak256Hash(commitment.Bytes(), words)
+}
+
+// updateRollingCommitment folds [words], the concatenated 32 byte values of a draw, into the
+// rolling commitment of the precompile at [precompileAddr].
+func updateRollingCommitment(state contract.StateDB, precompileAddr common.Address, words []byte) {
+	commitment := state.GetState(precompileAddr, rollingCommitmentKey)
+	state.SetState(precompileAddr, rollingCommitmentKey, crypto.Keccak256Hash(commitment.Bytes(), words))
+}
+
+func PackGetCommitmentOutput(commitment common.Hash) ([]byte, error) {
+	return randomABI.Methods["getCommitment"].Outputs.Pack([32]byte(commitment))
+}
+
+func GetCommitmentFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, GetCommitmentGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	ret, err = PackGetCommitmentOutput(accessibleState.GetStateDB().GetState(addr, rollingCommitmentKey))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
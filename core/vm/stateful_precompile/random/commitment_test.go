@@ -0,0 +1,56 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRollingCommitmentReplay(t *testing.T) {
+	state := newMockAccessibleState()
+	other := common.HexToAddress("0x00000000000000000000000000000000000beef0")
+
+	var replayed common.Hash
+	for i, caller := range []common.Address{testCaller, other, testCaller} {
+		state.state.SetNonce(caller, uint64(i))
+		values := mustRunMethod(t, state, caller, "randomNCSPRNG", big.NewInt(int64(i+1)))[0].([]*big.Int)
+		replayed = RollingCommitment(replayed, values)
+	}
+
+	// Read-only draws are not committed.
+	input, err := PackRandomNCSPRNGInput(big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := CreateRandomNCSPRNGPrecompile(DefaultConfig()).Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, true); err != nil {
+		t.Fatal(err)
+	}
+
+	commitment := common.Hash(mustRunMethod(t, state, testCaller, "getCommitment")[0].([32]byte))
+	if commitment == (common.Hash{}) || commitment != replayed {
+		t.Fatalf("got commitment %x, want %x", commitment, replayed)
+	}
+}
+
+func TestRollingCommitmentFromSolidity(t *testing.T) {
+	// Solidity calls view methods with STATICCALL, which would never update the commitment.
+	for _, name := range []string{"randomNCSPRNG", "randomNCSPRNG0"} {
+		if randomABI.Methods[name].IsConstant() {
+			t.Fatalf("%s is declared %s", name, randomABI.Methods[name].StateMutability)
+		}
+	}
+
+	state := newMockAccessibleState()
+	out, err := runMethodAsSolidity(state, testCaller, nil, "randomNCSPRNG", big.NewInt(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := RollingCommitment(common.Hash{}, out[0].([]*big.Int))
+	if commitment := common.Hash(mustRunMethod(t, state, testCaller, "getCommitment")[0].([32]byte)); commitment != want {
+		t.Fatalf("got commitment %x, want %x", commitment, want)
+	}
+}
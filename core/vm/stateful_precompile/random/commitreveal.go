@@ -0,0 +1,195 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	CommitRandomnessGasCost = 1024 + 2*contract.ReadGasCostPerSlot + 2*contract.WriteGasCostPerSlot
+	RevealRandomnessGasCost = 1024 + 2*contract.ReadGasCostPerSlot + 2*contract.WriteGasCostPerSlot
+)
+
+// Commit-reveal randomness keeps a caller from being front-run on a high-value draw:
+//
+//  1. commitRandomness records keccak(secret) and the block N it was committed in.
+//  2. revealRandomness publishes secret in block N+2 or later. It must hash to the commitment,
+//     and the returned value mixes it with the hash of block N+1, which was unknown when the
+//     commitment was made. The value is fixed by then: revealing in a later block does not
+//     change it, so the caller cannot wait for a favourable block.
+//  3. Once block N+1 leaves the 256 block hashes the EVM keeps, the commitment expires. It
+//     can no longer be revealed, and commitRandomness may replace it, so a lost secret does
+//     not hold the caller's commitment slot forever.
+//
+// Neither the caller, who is bound to secret, nor the producer of block N+1, who does not
+// know secret, can choose the value alone. A caller can still refuse to reveal an outcome it
+// dislikes and let the commitment expire, so consumers should penalise missing reveals.
+const (
+	randomnessCommitmentLabel  = "commitreveal.commitment"
+	randomnessCommitBlockLabel = "commitreveal.block"
+)
+
+var (
+	errRandomnessCommitted      = errors.New("a randomness commitment is already pending for caller")
+	errNoRandomnessCommitment   = errors.New("no randomness commitment pending for caller")
+	errRevealTooSoon            = errors.New("randomness must be revealed at least two blocks after its commitment")
+	errCommitmentExpired        = errors.New("hash of the block after the commitment is no longer available")
+	errSecretCommitmentMismatch = errors.New("secret does not match the commitment")
+)
+
+// CommitRandomnessInput is the input of the commitRandomness method.
+type CommitRandomnessInput struct {
+	Commitment [32]byte
+}
+
+// RevealRandomnessInput is the input of the revealRandomness method.
+type RevealRandomnessInput struct {
+	Secret [32]byte
+}
+
+func PackCommitRandomnessInput(commitment common.Hash) ([]byte, error) {
+	return randomABI.Pack("commitRandomness", [32]byte(commitment))
+}
+
+func UnpackCommitRandomnessInput(input []byte) (common.Hash, error) {
+	var in CommitRandomnessInput
+	if err := unpackInput("commitRandomness", input, &in); err != nil {
+		return common.Hash{}, err
+	}
+	return in.Commitment, nil
+}
+
+func PackRevealRandomnessInput(secret common.Hash) ([]byte, error) {
+	return randomABI.Pack("revealRandomness", [32]byte(secret))
+}
+
+func UnpackRevealRandomnessInput(input []byte) (common.Hash, error) {
+	var in RevealRandomnessInput
+	if err := unpackInput("revealRandomness", input, &in); err != nil {
+		return common.Hash{}, err
+	}
+	return in.Secret, nil
+}
+
+func PackRevealRandomnessOutput(randomValue *big.Int) ([]byte, error) {
+	return randomABI.Methods["revealRandomness"].Outputs.Pack(randomValue)
+}
+
+// RevealedRandomness returns the value revealed by [caller] from [secret] for a commitment
+// made in the block before [entropyBlock], whose hash is [entropyBlockHash].
+func RevealedRandomness(caller common.Address, secret common.Hash, entropyBlock uint64, entropyBlockHash common.Hash) *big.Int {
+	return new(big.Int).SetBytes(crypto.Keccak256(caller.Bytes(), secret.Bytes(), common.BigToHash(new(big.Int).SetUint64(entropyBlock)).Bytes(), entropyBlockHash.Bytes()))
+}
+
+// commitmentEntropyHash returns the hash of [entropyBlock], the block after a commitment, as
+// seen from the block of [blockCtx]. It fails with errRevealTooSoon until that block is
+// sealed, and with errCommitmentExpired once its hash is no longer available.
+func commitmentEntropyHash(blockCtx *vm.BlockContext, entropyBlock *big.Int) (common.Hash, error) {
+	hash, err := delayedRevealBlockHash(blockCtx, entropyBlock)
+	switch err {
+	case errRevealTooEarly:
+		return common.Hash{}, errRevealTooSoon
+	case errRevealExpired:
+		return common.Hash{}, errCommitmentExpired
+	}
+	return hash, err
+}
+
+// CommitRandomnessFunc records the commitment keccak(secret) of the caller for a later
+// revealRandomness. A caller has at most one pending commitment, which an expired one does
+// not count as.
+func CommitRandomnessFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, CommitRandomnessGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+
+	commitment, err := UnpackCommitRandomnessInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	if commitment == (common.Hash{}) {
+		return nil, remainingGas, errZeroCommitment
+	}
+
+	state := accessibleState.GetStateDB()
+	blockCtx := accessibleState.GetBlockContext()
+	if state.GetState(addr, stateKey(randomnessCommitmentLabel, caller.Bytes())) != (common.Hash{}) {
+		commitBlock := state.GetState(addr, stateKey(randomnessCommitBlockLabel, caller.Bytes()))
+		if _, err := commitmentEntropyHash(blockCtx, new(big.Int).Add(commitBlock.Big(), common.Big1)); err != errCommitmentExpired {
+			return nil, remainingGas, errRandomnessCommitted
+		}
+	}
+	blockNumber := blockCtx.BlockNumber
+	state.SetState(addr, stateKey(randomnessCommitmentLabel, caller.Bytes()), commitment)
+	state.SetState(addr, stateKey(randomnessCommitBlockLabel, caller.Bytes()), common.BigToHash(blockNumber))
+
+	return []byte{}, remainingGas, nil
+}
+
+// RevealRandomnessFunc clears the pending commitment of the caller and returns the value of
+// [secret], see RevealedRandomness. The commitment is cleared under a snapshot, so a reveal
+// that fails leaves it pending.
+func RevealRandomnessFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, RevealRandomnessGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+
+	secret, err := UnpackRevealRandomnessInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	state := accessibleState.GetStateDB()
+	snapshot := state.Snapshot()
+	value, err := revealRandomness(state, accessibleState.GetBlockContext(), addr, caller, secret)
+	if err != nil {
+		state.RevertToSnapshot(snapshot)
+		return nil, remainingGas, err
+	}
+
+	ret, err = PackRevealRandomnessOutput(value)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
+
+// revealRandomness clears the commitment of [caller] and returns the value of [secret] if it
+// opens the commitment at least two blocks after it was made and before it expires. It may
+// clear the commitment before failing, which the caller reverts.
+func revealRandomness(state contract.StateDB, blockCtx *vm.BlockContext, addr common.Address, caller common.Address, secret common.Hash) (*big.Int, error) {
+	commitmentKey := stateKey(randomnessCommitmentLabel, caller.Bytes())
+	commitBlockKey := stateKey(randomnessCommitBlockLabel, caller.Bytes())
+	commitment := state.GetState(addr, commitmentKey)
+	commitBlock := state.GetState(addr, commitBlockKey)
+	state.SetState(addr, commitmentKey, common.Hash{})
+	state.SetState(addr, commitBlockKey, common.Hash{})
+
+	if commitment == (common.Hash{}) {
+		return nil, errNoRandomnessCommitment
+	}
+	entropyBlock := new(big.Int).Add(commitBlock.Big(), common.Big1)
+	entropyBlockHash, err := commitmentEntropyHash(blockCtx, entropyBlock)
+	if err != nil {
+		return nil, err
+	}
+	if crypto.Keccak256Hash(secret.Bytes()) != commitment {
+		return nil, errSecretCommitmentMismatch
+	}
+	return RevealedRandomness(caller, secret, entropyBlock.Uint64(), entropyBlockHash), nil
+}
@@ -0,0 +1,109 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestCommitRevealRandomness(t *testing.T) {
+	state := newMockAccessibleState()
+	state.blockCtx.BlockNumber = big.NewInt(50)
+	state.blockCtx.GetHash = blockHashes(state)
+	random := common.HexToHash("0x7a0d")
+	state.blockCtx.Random = &random
+
+	secret := common.HexToHash("0x5ec7e7")
+	commitment := crypto.Keccak256Hash(secret.Bytes())
+	mustRunMethod(t, state, testCaller, "commitRandomness", [32]byte(commitment))
+	if _, _, err := runMethod(state, testCaller, "commitRandomness", [32]byte(commitment)); err != errRandomnessCommitted {
+		t.Fatalf("second commit: got %v, want %v", err, errRandomnessCommitted)
+	}
+
+	state.blockCtx.BlockNumber = big.NewInt(52)
+	want := RevealedRandomness(testCaller, secret, 51, state.blockCtx.GetHash(51))
+
+	// The value is fixed by the block after the commitment, whichever block reveals it.
+	snapshot := state.state.Snapshot()
+	got := mustRunMethod(t, state, testCaller, "revealRandomness", [32]byte(secret))[0].(*big.Int)
+	if got.Cmp(want) != 0 {
+		t.Fatalf("got %x, want %x", got, want)
+	}
+	state.state.RevertToSnapshot(snapshot)
+	state.blockCtx.BlockNumber = big.NewInt(300)
+	random = common.HexToHash("0x7a0e")
+	got = mustRunMethod(t, state, testCaller, "revealRandomness", [32]byte(secret))[0].(*big.Int)
+	if got.Cmp(want) != 0 {
+		t.Fatalf("reveal in a later block: got %x, want %x", got, want)
+	}
+	if _, _, err := runMethod(state, testCaller, "revealRandomness", [32]byte(secret)); err != errNoRandomnessCommitment {
+		t.Fatalf("second reveal: got %v, want %v", err, errNoRandomnessCommitment)
+	}
+}
+
+func TestRevealRandomnessExpired(t *testing.T) {
+	state := newMockAccessibleState()
+	state.blockCtx.BlockNumber = big.NewInt(50)
+	state.blockCtx.GetHash = blockHashes(state)
+	secret := common.HexToHash("0x5ec7e7")
+	mustRunMethod(t, state, testCaller, "commitRandomness", [32]byte(crypto.Keccak256Hash(secret.Bytes())))
+	commitmentKey := stateKey(randomnessCommitmentLabel, testCaller.Bytes())
+
+	// Block 51 fixes the value, and its hash is available for 256 blocks.
+	state.blockCtx.BlockNumber = big.NewInt(51 + 256)
+	other := common.HexToHash("0x07e7")
+	if _, _, err := runMethod(state, testCaller, "commitRandomness", [32]byte(crypto.Keccak256Hash(other.Bytes()))); err != errRandomnessCommitted {
+		t.Fatalf("commit over a pending commitment: got %v, want %v", err, errRandomnessCommitted)
+	}
+
+	state.blockCtx.BlockNumber = big.NewInt(51 + 257)
+	if _, _, err := runMethod(state, testCaller, "revealRandomness", [32]byte(secret)); err != errCommitmentExpired {
+		t.Fatalf("expired reveal: got %v, want %v", err, errCommitmentExpired)
+	}
+	if state.state.GetState(randomNCSPRNGContractAddr, commitmentKey) == (common.Hash{}) {
+		t.Fatal("expired reveal cleared the commitment")
+	}
+
+	// An expired commitment no longer holds the slot.
+	mustRunMethod(t, state, testCaller, "commitRandomness", [32]byte(crypto.Keccak256Hash(other.Bytes())))
+	state.blockCtx.BlockNumber = big.NewInt(51 + 259)
+	mustRunMethod(t, state, testCaller, "revealRandomness", [32]byte(other))
+}
+
+func TestRevealRandomnessFailureKeepsCommitment(t *testing.T) {
+	state := newMockAccessibleState()
+	state.blockCtx.BlockNumber = big.NewInt(50)
+	state.blockCtx.GetHash = blockHashes(state)
+
+	secret := common.HexToHash("0x5ec7e7")
+	mustRunMethod(t, state, testCaller, "commitRandomness", [32]byte(crypto.Keccak256Hash(secret.Bytes())))
+	commitmentKey := stateKey(randomnessCommitmentLabel, testCaller.Bytes())
+	commitment := state.state.GetState(randomNCSPRNGContractAddr, commitmentKey)
+
+	// At least one block must separate the commitment from the reveal.
+	for _, block := range []int64{50, 51} {
+		state.blockCtx.BlockNumber = big.NewInt(block)
+		if _, _, err := runMethod(state, testCaller, "revealRandomness", [32]byte(secret)); err != errRevealTooSoon {
+			t.Fatalf("reveal in block %d: got %v, want %v", block, err, errRevealTooSoon)
+		}
+		if got := state.state.GetState(randomNCSPRNGContractAddr, commitmentKey); got != commitment {
+			t.Fatalf("reveal in block %d changed the commitment to %x", block, got)
+		}
+	}
+
+	state.blockCtx.BlockNumber = big.NewInt(52)
+	if _, _, err := runMethod(state, testCaller, "revealRandomness", [32]byte(common.HexToHash("0xbad"))); err != errSecretCommitmentMismatch {
+		t.Fatalf("mismatched reveal: got %v, want %v", err, errSecretCommitmentMismatch)
+	}
+	if got := state.state.GetState(randomNCSPRNGContractAddr, commitmentKey); got != commitment {
+		t.Fatalf("mismatched reveal changed the commitment to %x", got)
+	}
+
+	// The commitment is still open after the failed reveals.
+	mustRunMethod(t, state, testCaller, "revealRandomness", [32]byte(secret))
+}
@@ -0,0 +1,78 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+// Config holds the deployment parameters of the precompile, which may differ from one chain
+// to another. Unlike an Option, it does not change the values drawn for a given address.
+type Config struct {
+	// Address is the address the precompile must be registered at. The handlers key their
+	// state and streams with it, and the precompile fails when called at any other address.
+	Address common.Address
+
+	// BaseGas and PerValueGas price a draw of n values by randomNCSPRNG, randomRaw and
+	// randomBytes as BaseGas + n*PerValueGas.
+	BaseGas     uint64
+	PerValueGas uint64
+
+	// MaxValues bounds the number of values randomNCSPRNG, randomRaw and randomBytes return.
+	// The other methods returning caller-sized arrays are bounded by MaxRandomValues, which
+	// is part of their gas schedule and cannot be configured.
+	MaxValues uint64
+
+	// MinDrawGap is the least number of blocks rateLimitedRandom enforces between two draws of
+	// a caller, whatever minimum block gap the caller asks for.
+	MinDrawGap uint64
+}
+
+// DefaultConfig returns the configuration of the precompile on chains that do not override it.
+func DefaultConfig() Config {
+	return Config{
+		Address:     randomNCSPRNGContractAddr,
+		BaseGas:     RandomNCSPRNGBaseGas,
+		PerValueGas: RandomNCSPRNGPerValueGas,
+		MaxValues:   MaxRandomNCSPRNGValues,
+		MinDrawGap:  DefaultMinDrawGap,
+	}
+}
+
+var errWrongAddress = errors.New("precompile called at an address other than its configured one")
+
+// addressBoundPrecompile runs [contract] only when it is called at [address].
+type addressBoundPrecompile struct {
+	address  common.Address
+	contract contract.StatefulPrecompiledContract
+}
+
+func (p *addressBoundPrecompile) Run(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if addr != p.address {
+		return nil, suppliedGas, errWrongAddress
+	}
+	return p.contract.Run(accessibleState, caller, addr, input, suppliedGas, readOnly)
+}
+
+// drawGas returns the gas charged for drawing [n] values. It reports false if the cost does
+// not fit in a uint64, which no supplied gas can cover.
+func (c Config) drawGas(n uint64) (uint64, bool) {
+	perValue, overflow := math.SafeMul(n, c.PerValueGas)
+	if overflow {
+		return 0, false
+	}
+	gas, overflow := math.SafeAdd(c.BaseGas, perValue)
+	return gas, !overflow
+}
+
+// isRandomnessActivated reports whether the chain has activated the randomness precompiles at
+// the current block. Before that, every method fails as if its selector were unknown and
+// consumes no gas.
+func isRandomnessActivated(accessibleState contract.AccessibleState) bool {
+	return accessibleState.GetChainConfig().IsRandomness(accessibleState.GetBlockContext().BlockNumber)
+}
@@ -0,0 +1,124 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Address != common.HexToAddress("0x6942000000000000000000000000000000000000") {
+		t.Errorf("got address %v", cfg.Address)
+	}
+	if cfg.BaseGas != RandomNCSPRNGBaseGas || cfg.PerValueGas != RandomNCSPRNGPerValueGas || cfg.MaxValues != MaxRandomNCSPRNGValues {
+		t.Errorf("got %+v, want the package constants", cfg)
+	}
+	if _, ok := (Config{PerValueGas: 2}).drawGas(1 << 63); ok {
+		t.Errorf("overflowing cost was accepted")
+	}
+}
+
+func TestCustomConfig(t *testing.T) {
+	cfg := Config{
+		Address:     common.HexToAddress("0x0300000000000000000000000000000000000001"),
+		BaseGas:     100,
+		PerValueGas: 10,
+		MaxValues:   8,
+	}
+	precompile := CreateRandomNCSPRNGPrecompile(cfg)
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 6)
+
+	for _, method := range []string{"randomNCSPRNG", "randomBytes"} {
+		input, err := randomABI.Pack(method, big.NewInt(8))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret, remainingGas, err := precompile.Run(state, testCaller, cfg.Address, input, testGas, true)
+		if err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+		if used := testGas - remainingGas; used != 100+8*10 {
+			t.Errorf("%s: used %d gas, want 180", method, used)
+		}
+
+		// The draw is keyed by the custom address and unpacks like the default one.
+		want := VerifyRandomNCSPRNG(cfg.Address, testCaller, 6, 8)
+		other := VerifyRandomNCSPRNG(randomNCSPRNGContractAddr, testCaller, 6, 8)
+		out, err := randomABI.Methods[method].Outputs.Unpack(ret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range want {
+			var got *big.Int
+			if method == "randomBytes" {
+				word := out[0].([][32]byte)[i]
+				got = new(big.Int).SetBytes(word[:])
+			} else {
+				got = out[0].([]*big.Int)[i]
+			}
+			if got.Cmp(want[i]) != 0 || got.Cmp(other[i]) == 0 {
+				t.Errorf("%s, value %d: got %x, want %x", method, i, got, want[i])
+			}
+		}
+
+		input, err = randomABI.Pack(method, big.NewInt(9))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := precompile.Run(state, testCaller, cfg.Address, input, testGas, true); err != errTooManyValues {
+			t.Errorf("%s: got %v, want %v", method, err, errTooManyValues)
+		}
+	}
+
+	input, err := PackRandomRawInput(big.NewInt(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := precompile.Run(state, testCaller, cfg.Address, input, 179, true); err != vm.ErrOutOfGas {
+		t.Errorf("randomRaw: got %v, want %v", err, vm.ErrOutOfGas)
+	}
+}
+
+func TestRandomnessActivation(t *testing.T) {
+	state := newMockAccessibleState()
+	state.chainConfig = randomnessChainConfig(big.NewInt(100))
+
+	state.blockCtx.BlockNumber = big.NewInt(99)
+	if _, remainingGas, err := runMethod(state, testCaller, "randomNCSPRNG", big.NewInt(1)); err == nil || remainingGas != testGas {
+		t.Fatalf("before activation: got err %v with %d gas left, want an error with %d", err, remainingGas, testGas)
+	}
+	state.blockCtx.BlockNumber = big.NewInt(101)
+	if out := mustRunMethod(t, state, testCaller, "randomNCSPRNG", big.NewInt(1)); len(out[0].([]*big.Int)) != 1 {
+		t.Fatalf("after activation: got %v, want one value", out[0])
+	}
+
+	// A chain config without an activation block never activates the precompile.
+	state.chainConfig = randomnessChainConfig(nil)
+	if _, _, err := runMethod(state, testCaller, "randomNCSPRNG", big.NewInt(1)); err == nil {
+		t.Fatal("precompile is active without an activation block")
+	}
+}
+
+func TestConfigAddressEnforced(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Address = common.HexToAddress("0x0300000000000000000000000000000000000001")
+	input, err := PackRandomNCSPRNGInput(big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	precompile := CreateRandomNCSPRNGPrecompile(cfg)
+	state := newMockAccessibleState()
+	if _, remainingGas, err := precompile.Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, true); err != errWrongAddress || remainingGas != testGas {
+		t.Fatalf("call at another address: got %v with %d gas left, want %v with %d", err, remainingGas, errWrongAddress, testGas)
+	}
+	if _, _, err := precompile.Run(state, testCaller, cfg.Address, input, testGas, true); err != nil {
+		t.Fatalf("call at the configured address: %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	PickWithCooldownBaseGas      = 1024 + contract.WriteGasCostPerSlot
+	PickWithCooldownPerOptionGas = contract.ReadGasCostPerSlot
+
+	// MaxCooldownOptions bounds the number of options whose cooldown is checked per pick.
+	MaxCooldownOptions = 256
+)
+
+var (
+	errInvalidOptionCount = errors.New("number of options must be between 1 and MaxCooldownOptions")
+	errNoEligibleOption   = errors.New("every option is still in cooldown")
+)
+
+// PickWithCooldownInput is the input of the pickWithCooldown method.
+type PickWithCooldownInput struct {
+	NumOptions     *big.Int
+	CooldownBlocks *big.Int
+}
+
+func PackPickWithCooldownInput(numOptions *big.Int, cooldownBlocks *big.Int) ([]byte, error) {
+	return randomABI.Pack("pickWithCooldown", numOptions, cooldownBlocks)
+}
+
+func UnpackPickWithCooldownInput(input []byte) (PickWithCooldownInput, error) {
+	var in PickWithCooldownInput
+	if err := unpackInput("pickWithCooldown", input, &in); err != nil {
+		return PickWithCooldownInput{}, err
+	}
+	if in.NumOptions.Sign() == 0 || !in.NumOptions.IsUint64() || in.NumOptions.Uint64() > MaxCooldownOptions {
+		return PickWithCooldownInput{}, errInvalidOptionCount
+	}
+	return in, nil
+}
+
+func PackPickWithCooldownOutput(option *big.Int) ([]byte, error) {
+	return randomABI.Methods["pickWithCooldown"].Outputs.Pack(option)
+}
+
+// cooldownKey returns the slot holding the block after the one in which [option] was last
+// picked by [caller], or zero if it never was.
+func cooldownKey(caller common.Address, option uint64) common.Hash {
+	return stateKey("cooldown.last", caller.Bytes(), common.BigToHash(new(big.Int).SetUint64(option)).Bytes())
+}
+
+// pickWithCooldown picks uniformly among the options of [caller] whose cooldown elapsed, i.e.
+// that were never picked or were last picked at least [cooldown] blocks before [blockNumber],
+// and records the pick.
+func pickWithCooldown(stream *randomStream, state contract.StateDB, addr common.Address, caller common.Address, numOptions uint64, cooldown *big.Int, blockNumber *big.Int) (uint64, error) {
+	var eligible []uint64
+	for option := uint64(0); option < numOptions; option++ {
+		last := state.GetState(addr, cooldownKey(caller, option)).Big()
+		if last.Sign() == 0 {
+			eligible = append(eligible, option)
+			continue
+		}
+		// The slot holds the pick block plus one.
+		ready := last.Sub(last, common.Big1).Add(last, cooldown)
+		if blockNumber.Cmp(ready) >= 0 {
+			eligible = append(eligible, option)
+		}
+	}
+	if len(eligible) == 0 {
+		return 0, errNoEligibleOption
+	}
+	option := eligible[stream.uniformUint64(uint64(len(eligible)))]
+	state.SetState(addr, cooldownKey(caller, option), common.BigToHash(new(big.Int).Add(blockNumber, common.Big1)))
+	return option, nil
+}
+
+func PickWithCooldownFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackPickWithCooldownInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	numOptions := in.NumOptions.Uint64()
+	if remainingGas, err = contract.DeductGas(suppliedGas, PickWithCooldownBaseGas+numOptions*PickWithCooldownPerOptionGas); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+
+	state := accessibleState.GetStateDB()
+	stream := newCallerStream(addr, caller, state)
+	option, err := pickWithCooldown(stream, state, addr, caller, numOptions, in.CooldownBlocks, accessibleState.GetBlockContext().BlockNumber)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	ret, err = PackPickWithCooldownOutput(new(big.Int).SetUint64(option))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,45 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestPickWithCooldown(t *testing.T) {
+	const (
+		options  = 10
+		cooldown = 4
+	)
+	state := newMockAccessibleState()
+	lastPicked := make(map[uint64]int64)
+
+	for block := int64(10); block < 60; block++ {
+		state.blockCtx.BlockNumber = big.NewInt(block)
+		state.state.SetNonce(testCaller, uint64(block))
+		option := mustRunMethod(t, state, testCaller, "pickWithCooldown", big.NewInt(options), big.NewInt(cooldown))[0].(*big.Int).Uint64()
+		if option >= options {
+			t.Fatalf("block %d: option %d out of range", block, option)
+		}
+		if last, ok := lastPicked[option]; ok && block-last < cooldown {
+			t.Fatalf("block %d: option %d reselected %d blocks after its last pick", block, option, block-last)
+		}
+		lastPicked[option] = block
+	}
+}
+
+func TestPickWithCooldownExhausted(t *testing.T) {
+	state := newMockAccessibleState()
+	state.blockCtx.BlockNumber = big.NewInt(0)
+	if option := mustRunMethod(t, state, testCaller, "pickWithCooldown", big.NewInt(1), big.NewInt(5))[0].(*big.Int); option.Sign() != 0 {
+		t.Fatalf("picked option %v of a single one", option)
+	}
+	state.blockCtx.BlockNumber = big.NewInt(4)
+	if _, _, err := runMethod(state, testCaller, "pickWithCooldown", big.NewInt(1), big.NewInt(5)); err != errNoEligibleOption {
+		t.Fatalf("got %v, want %v", err, errNoEligibleOption)
+	}
+	state.blockCtx.BlockNumber = big.NewInt(5)
+	mustRunMethod(t, state, testCaller, "pickWithCooldown", big.NewInt(1), big.NewInt(5))
+}
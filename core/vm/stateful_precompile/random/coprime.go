@@ -0,0 +1,86 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomCoprimeBaseGas = 1024
+	// RandomCoprimePerValueGas covers the candidates and gcd computations per value. The
+	// share of values coprime to a 256 bit modulus is above 1/16, and far higher for most.
+	RandomCoprimePerValueGas = 512
+)
+
+var errInvalidModulus = errors.New("modulus must be greater than 1")
+
+// RandomCoprimeInput is the input of the randomCoprime method.
+type RandomCoprimeInput struct {
+	Modulus *big.Int
+	Count   *big.Int
+}
+
+func PackRandomCoprimeInput(modulus *big.Int, count *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomCoprime", modulus, count)
+}
+
+func UnpackRandomCoprimeInput(input []byte) (RandomCoprimeInput, error) {
+	var in RandomCoprimeInput
+	if err := unpackInput("randomCoprime", input, &in); err != nil {
+		return RandomCoprimeInput{}, err
+	}
+	if in.Modulus.Cmp(common.Big1) <= 0 {
+		return RandomCoprimeInput{}, errInvalidModulus
+	}
+	if !in.Count.IsUint64() || in.Count.Uint64() > MaxRandomValues {
+		return RandomCoprimeInput{}, errTooManyValues
+	}
+	return in, nil
+}
+
+func PackRandomCoprimeOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomCoprime"].Outputs.Pack(randomValues)
+}
+
+// randomCoprime draws values uniformly from [1, modulus) until one sharing no factor with
+// [modulus], so the result is uniform among the units modulo [modulus].
+func randomCoprime(stream *randomStream, modulus *big.Int) *big.Int {
+	bound := new(big.Int).Sub(modulus, common.Big1)
+	gcd := new(big.Int)
+	for {
+		v := stream.uniform(bound)
+		v.Add(v, common.Big1)
+		if gcd.GCD(nil, nil, v, modulus).Cmp(common.Big1) == 0 {
+			return v
+		}
+	}
+}
+
+func RandomCoprimeFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackRandomCoprimeInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	count := in.Count.Uint64()
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomCoprimeBaseGas+count*RandomCoprimePerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	values := make([]*big.Int, count)
+	for i := range values {
+		values[i] = randomCoprime(stream, in.Modulus)
+	}
+	ret, err = PackRandomCoprimeOutput(values)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,42 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRandomCoprime(t *testing.T) {
+	state := newMockAccessibleState()
+	// 2*3*5*7*11*13 = 30030 has few units, 2 has a single one.
+	primorial := big.NewInt(30030)
+	// An RSA-style modulus, the product of the Mersenne primes 2^127-1 and 2^89-1.
+	p := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+	q := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 89), big.NewInt(1))
+	rsa := new(big.Int).Mul(p, q)
+	for _, modulus := range []*big.Int{big.NewInt(2), primorial, rsa} {
+		values := mustRunMethod(t, state, testCaller, "randomCoprime", modulus, big.NewInt(100))[0].([]*big.Int)
+		if len(values) != 100 {
+			t.Fatalf("got %d values, want 100", len(values))
+		}
+		for _, v := range values {
+			if v.Sign() <= 0 || v.Cmp(modulus) >= 0 {
+				t.Fatalf("modulus %v: value %v out of range [1, modulus)", modulus, v)
+			}
+			if gcd := new(big.Int).GCD(nil, nil, v, modulus); gcd.Cmp(big.NewInt(1)) != 0 {
+				t.Fatalf("modulus %v: value %v shares factor %v", modulus, v, gcd)
+			}
+		}
+	}
+}
+
+func TestRandomCoprimeInvalidModulus(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, m := range []int64{0, 1} {
+		if _, _, err := runMethod(state, testCaller, "randomCoprime", big.NewInt(m), big.NewInt(1)); err != errInvalidModulus {
+			t.Errorf("modulus %d: got %v, want %v", m, err, errInvalidModulus)
+		}
+	}
+}
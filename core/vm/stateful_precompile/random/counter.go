@@ -0,0 +1,87 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomFromCounterBaseGas = 1024
+)
+
+var errCounterOverflow = errors.New("counter range overflows the stream")
+
+// RandomFromCounterInput is the input of the randomFromCounter method.
+type RandomFromCounterInput struct {
+	StartCounter *big.Int
+	N            *big.Int
+}
+
+func PackRandomFromCounterInput(startCounter *big.Int, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomFromCounter", startCounter, n)
+}
+
+func UnpackRandomFromCounterInput(input []byte) (uint64, uint64, error) {
+	var in RandomFromCounterInput
+	if err := unpackInput("randomFromCounter", input, &in); err != nil {
+		return 0, 0, err
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return 0, 0, errTooManyValues
+	}
+	if !in.StartCounter.IsUint64() || in.StartCounter.Uint64() > math.MaxUint64-in.N.Uint64() {
+		return 0, 0, errCounterOverflow
+	}
+	return in.StartCounter.Uint64(), in.N.Uint64(), nil
+}
+
+func PackRandomFromCounterOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomFromCounter"].Outputs.Pack(randomValues)
+}
+
+// newRandomFromCounterFunc returns the randomFromCounter handler of a precompile built with
+// [o]. It returns the values at positions [startCounter, startCounter+n) of the sequence
+// randomNCSPRNG would return to the caller, so disjoint counter ranges can be generated
+// independently, e.g. by different shards, and concatenated into one contiguous draw.
+func newRandomFromCounterFunc(o options) contract.RunStatefulPrecompileFunc {
+	return func(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		start, n, err := UnpackRandomFromCounterInput(input)
+		if err != nil {
+			return nil, suppliedGas, err
+		}
+		if remainingGas, err = contract.DeductGas(suppliedGas, RandomFromCounterBaseGas+n*RandomPerValueGas); err != nil {
+			return nil, 0, err
+		}
+		if start+n > math.MaxUint64-uint64(o.warmupDiscard) {
+			return nil, remainingGas, errCounterOverflow
+		}
+
+		state := accessibleState.GetStateDB()
+		blockCtx := accessibleState.GetBlockContext()
+		blockNumber := blockCtx.BlockNumber.Uint64()
+		stream, sources, err := newRandomNCSPRNGStream(addr, caller, blockCtx, o, state)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		if sources&EntropySourceFallback != 0 {
+			if remainingGas, err = reportEntropyFallback(state, addr, caller, blockNumber, readOnly, remainingGas); err != nil {
+				return nil, 0, err
+			}
+		}
+		stream.skip(start)
+
+		ret, err = PackRandomFromCounterOutput(stream.values(n))
+		if err != nil {
+			return nil, remainingGas, err
+		}
+
+		return ret, remainingGas, nil
+	}
+}
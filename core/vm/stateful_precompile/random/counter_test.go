@@ -0,0 +1,42 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestRandomFromCounterShards(t *testing.T) {
+	const (
+		chunk  = 5
+		shards = 4
+	)
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 4)
+
+	contiguous := runRandomNCSPRNG(t, state, chunk*shards, true)
+	var sharded []*big.Int
+	for k := int64(shards - 1); k >= 0; k-- {
+		values := mustRunMethod(t, state, testCaller, "randomFromCounter", big.NewInt(k*chunk), big.NewInt(chunk))[0].([]*big.Int)
+		sharded = append(values, sharded...)
+	}
+	if len(sharded) != len(contiguous) {
+		t.Fatalf("got %d sharded values, want %d", len(sharded), len(contiguous))
+	}
+	for i := range contiguous {
+		if sharded[i].Cmp(contiguous[i]) != 0 {
+			t.Errorf("value %d: got %x, want %x", i, sharded[i], contiguous[i])
+		}
+	}
+}
+
+func TestRandomFromCounterOverflow(t *testing.T) {
+	state := newMockAccessibleState()
+	start := new(big.Int).SetUint64(math.MaxUint64 - 1)
+	if _, _, err := runMethod(state, testCaller, "randomFromCounter", start, big.NewInt(2)); err != errCounterOverflow {
+		t.Fatalf("got %v, want %v", err, errCounterOverflow)
+	}
+}
@@ -0,0 +1,52 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestDelayRandom(t *testing.T) {
+	state := newMockAccessibleState()
+	input := common.HexToHash("0x1234")
+
+	out := mustRunMethod(t, state, testCaller, "delayRandom", [32]byte(input), big.NewInt(3))
+	output, value := common.Hash(out[0].([32]byte)), out[1].(*big.Int)
+	if want := crypto.Keccak256Hash(crypto.Keccak256(crypto.Keccak256(input[:]))); output != want {
+		t.Fatalf("got output %x, want %x", output, want)
+	}
+
+	state.state.SetNonce(testCaller, 8)
+	again := mustRunMethod(t, state, testCaller, "delayRandom", [32]byte(input), big.NewInt(3))
+	if common.Hash(again[0].([32]byte)) != output || again[1].(*big.Int).Cmp(value) != 0 {
+		t.Fatalf("output not deterministic for a fixed input")
+	}
+
+	other := mustRunMethod(t, state, testCaller, "delayRandom", [32]byte(common.HexToHash("0x1235")), big.NewInt(3))
+	if common.Hash(other[0].([32]byte)) == output || other[1].(*big.Int).Cmp(value) == 0 {
+		t.Fatalf("output unchanged for a different input")
+	}
+	longer := mustRunMethod(t, state, testCaller, "delayRandom", [32]byte(input), big.NewInt(4))
+	if common.Hash(longer[0].([32]byte)) == output {
+		t.Fatalf("output unchanged for a different number of iterations")
+	}
+}
+
+func TestDelayRandomGas(t *testing.T) {
+	state := newMockAccessibleState()
+	_, remaining, err := runMethod(state, testCaller, "delayRandom", [32]byte{}, big.NewInt(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if used, want := uint64(testGas)-remaining, uint64(DelayRandomBaseGas+1000*DelayRandomPerIterationGas); used != want {
+		t.Fatalf("used %d gas, want %d", used, want)
+	}
+	if _, _, err := runMethod(state, testCaller, "delayRandom", [32]byte{}, big.NewInt(MaxDelayIterations+1)); err != errInvalidDelayIterations {
+		t.Fatalf("got %v, want %v", err, errInvalidDelayIterations)
+	}
+}
@@ -0,0 +1,193 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	RequestDelayedRandomGasCost = 1024 + 3*contract.WriteGasCostPerSlot
+	RevealDelayedRandomGasCost  = 1024 + 3*contract.ReadGasCostPerSlot + 3*contract.WriteGasCostPerSlot
+
+	// MaxRevealDelay bounds the number of blocks a delayed draw can be withheld for.
+	MaxRevealDelay = 1 << 20
+)
+
+var (
+	errInvalidRevealDelay = errors.New("reveal delay must be between 1 and MaxRevealDelay blocks")
+	errRevealPending      = errors.New("a delayed draw is already pending for caller")
+	errNoPendingReveal    = errors.New("no delayed draw pending for caller")
+	errRevealTooEarly     = errors.New("delayed draw cannot be revealed yet")
+	errRevealExpired      = errors.New("hash of the reveal block is no longer available")
+)
+
+// A delayed draw moves through the following states, all kept in the precompile storage of
+// the requesting caller:
+//
+//   - none: no slots set, requestDelayedRandom may be called.
+//   - pending: requestDelayedRandom recorded the caller nonce, the request block and the
+//     reveal block, and returned keccak(seed || requestBlock), where seed is the first word of
+//     the caller stream at the nonce. Further requests fail.
+//   - revealable: the chain is past the reveal block. revealDelayedRandom returns
+//     keccak(seed || hash of the reveal block) together with seed, and clears the slots,
+//     moving the caller back to none. The seed opens the commitment, so consumers can check
+//     the value against it, see VerifyDelayedRandom. Nobody knows the hash of the reveal block when the draw is requested, so the delay
+//     keeps the value secret until then; only the producer of the reveal block can influence
+//     it, by withholding the block.
+//   - expired: the reveal block is older than the 256 block hashes the EVM keeps. The draw can
+//     no longer be revealed, and requestDelayedRandom may replace it.
+const (
+	delayedNonceLabel   = "delayed.nonce"
+	delayedRequestLabel = "delayed.request"
+	delayedRevealLabel  = "delayed.reveal"
+)
+
+// RequestDelayedRandomInput is the input of the requestDelayedRandom method.
+type RequestDelayedRandomInput struct {
+	Delay *big.Int
+}
+
+func PackRequestDelayedRandomInput(delay *big.Int) ([]byte, error) {
+	return randomABI.Pack("requestDelayedRandom", delay)
+}
+
+func UnpackRequestDelayedRandomInput(input []byte) (uint64, error) {
+	var in RequestDelayedRandomInput
+	if err := unpackInput("requestDelayedRandom", input, &in); err != nil {
+		return 0, err
+	}
+	if in.Delay.Sign() == 0 || !in.Delay.IsUint64() || in.Delay.Uint64() > MaxRevealDelay {
+		return 0, errInvalidRevealDelay
+	}
+	return in.Delay.Uint64(), nil
+}
+
+func PackRequestDelayedRandomOutput(commitment common.Hash) ([]byte, error) {
+	return randomABI.Methods["requestDelayedRandom"].Outputs.Pack([32]byte(commitment))
+}
+
+func PackRevealDelayedRandomInput() ([]byte, error) {
+	return randomABI.Pack("revealDelayedRandom")
+}
+
+func PackRevealDelayedRandomOutput(randomValue *big.Int, seed *big.Int) ([]byte, error) {
+	return randomABI.Methods["revealDelayedRandom"].Outputs.Pack(randomValue, seed)
+}
+
+// DelayedRandomCommitment returns the commitment handed out for a delayed draw with [seed]
+// requested at [blockNumber].
+func DelayedRandomCommitment(seed *big.Int, blockNumber uint64) common.Hash {
+	return crypto.Keccak256Hash(common.BigToHash(seed).Bytes(), common.BigToHash(new(big.Int).SetUint64(blockNumber)).Bytes())
+}
+
+// DelayedRandomValue returns the value revealed for a delayed draw with [seed] whose reveal
+// block has hash [revealBlockHash].
+func DelayedRandomValue(seed *big.Int, revealBlockHash common.Hash) *big.Int {
+	return new(big.Int).SetBytes(crypto.Keccak256(common.BigToHash(seed).Bytes(), revealBlockHash.Bytes()))
+}
+
+// VerifyDelayedRandom reports whether [randomValue] and [seed], as returned by
+// revealDelayedRandom, open [commitment], as returned by requestDelayedRandom at
+// [requestBlock], for a reveal block with hash [revealBlockHash].
+func VerifyDelayedRandom(commitment common.Hash, requestBlock uint64, revealBlockHash common.Hash, randomValue *big.Int, seed *big.Int) bool {
+	return DelayedRandomCommitment(seed, requestBlock) == commitment && DelayedRandomValue(seed, revealBlockHash).Cmp(randomValue) == 0
+}
+
+// delayedRevealBlockHash returns the hash of [revealBlock] as seen from the block of
+// [blockCtx]. It fails with errRevealTooEarly until the reveal block is sealed, and with
+// errRevealExpired once its hash is no longer available.
+func delayedRevealBlockHash(blockCtx *vm.BlockContext, revealBlock *big.Int) (common.Hash, error) {
+	if blockCtx.BlockNumber.Cmp(revealBlock) <= 0 {
+		return common.Hash{}, errRevealTooEarly
+	}
+	var hash common.Hash
+	if blockCtx.GetHash != nil {
+		hash = blockCtx.GetHash(revealBlock.Uint64())
+	}
+	if hash == (common.Hash{}) {
+		return common.Hash{}, errRevealExpired
+	}
+	return hash, nil
+}
+
+func RequestDelayedRandomFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, RequestDelayedRandomGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+
+	delay, err := UnpackRequestDelayedRandomInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	state := accessibleState.GetStateDB()
+	blockCtx := accessibleState.GetBlockContext()
+	if revealBlock := state.GetState(addr, stateKey(delayedRevealLabel, caller.Bytes())); revealBlock != (common.Hash{}) {
+		if _, err := delayedRevealBlockHash(blockCtx, revealBlock.Big()); err != errRevealExpired {
+			return nil, remainingGas, errRevealPending
+		}
+	}
+
+	nonce := state.GetNonce(caller)
+	blockNumber := blockCtx.BlockNumber.Uint64()
+	seed := newCallerStreamAt(addr, caller, nonce).next()
+
+	state.SetState(addr, stateKey(delayedNonceLabel, caller.Bytes()), common.BigToHash(new(big.Int).SetUint64(nonce)))
+	state.SetState(addr, stateKey(delayedRequestLabel, caller.Bytes()), common.BigToHash(new(big.Int).SetUint64(blockNumber)))
+	state.SetState(addr, stateKey(delayedRevealLabel, caller.Bytes()), common.BigToHash(new(big.Int).SetUint64(blockNumber+delay)))
+
+	ret, err = PackRequestDelayedRandomOutput(DelayedRandomCommitment(seed, blockNumber))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
+
+func RevealDelayedRandomFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, RevealDelayedRandomGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+	if len(input) != 0 {
+		return nil, remainingGas, errInvalidInputLength
+	}
+
+	state := accessibleState.GetStateDB()
+	revealBlock := state.GetState(addr, stateKey(delayedRevealLabel, caller.Bytes()))
+	if revealBlock == (common.Hash{}) {
+		return nil, remainingGas, errNoPendingReveal
+	}
+	revealBlockHash, err := delayedRevealBlockHash(accessibleState.GetBlockContext(), revealBlock.Big())
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	nonce := state.GetState(addr, stateKey(delayedNonceLabel, caller.Bytes())).Big().Uint64()
+	seed := newCallerStreamAt(addr, caller, nonce).next()
+	value := DelayedRandomValue(seed, revealBlockHash)
+
+	for _, label := range []string{delayedNonceLabel, delayedRequestLabel, delayedRevealLabel} {
+		state.SetState(addr, stateKey(label, caller.Bytes()), common.Hash{})
+	}
+
+	ret, err = PackRevealDelayedRandomOutput(value, seed)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,90 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestDelayedRandom(t *testing.T) {
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 9)
+	state.blockCtx.BlockNumber = big.NewInt(100)
+	state.blockCtx.GetHash = blockHashes(state)
+
+	if _, _, err := runMethod(state, testCaller, "revealDelayedRandom"); err != errNoPendingReveal {
+		t.Fatalf("reveal without request: got %v, want %v", err, errNoPendingReveal)
+	}
+	out := mustRunMethod(t, state, testCaller, "requestDelayedRandom", big.NewInt(5))
+	commitment := common.Hash(out[0].([32]byte))
+	if _, _, err := runMethod(state, testCaller, "requestDelayedRandom", big.NewInt(5)); err != errRevealPending {
+		t.Fatalf("second request: got %v, want %v", err, errRevealPending)
+	}
+
+	// The nonce moving on must not change the value that is eventually revealed. The hash of
+	// the reveal block is only known once the next block is built.
+	state.state.SetNonce(testCaller, 10)
+	for _, block := range []int64{104, 105} {
+		state.blockCtx.BlockNumber = big.NewInt(block)
+		if _, _, err := runMethod(state, testCaller, "revealDelayedRandom"); err != errRevealTooEarly {
+			t.Fatalf("reveal in block %d: got %v, want %v", block, err, errRevealTooEarly)
+		}
+	}
+
+	state.blockCtx.BlockNumber = big.NewInt(110)
+	out = mustRunMethod(t, state, testCaller, "revealDelayedRandom")
+	value, seed := out[0].(*big.Int), out[1].(*big.Int)
+	if want := newCallerStreamAt(randomNCSPRNGContractAddr, testCaller, 9).next(); seed.Cmp(want) != 0 {
+		t.Fatalf("revealed seed %x, want %x", seed, want)
+	}
+	// A consumer holding the commitment can check the reveal against it.
+	revealBlockHash := state.blockCtx.GetHash(105)
+	if !VerifyDelayedRandom(commitment, 100, revealBlockHash, value, seed) {
+		t.Fatal("reveal does not open its commitment")
+	}
+	if VerifyDelayedRandom(commitment, 100, revealBlockHash, new(big.Int).Add(value, common.Big1), seed) {
+		t.Fatal("tampered value opens the commitment")
+	}
+	if VerifyDelayedRandom(commitment, 101, revealBlockHash, value, seed) {
+		t.Fatal("reveal opens a commitment made in another block")
+	}
+	// The value is not the one the caller could compute when requesting it.
+	if value.Cmp(seed) == 0 {
+		t.Fatal("revealed value is the seed known at request time")
+	}
+
+	if _, _, err := runMethod(state, testCaller, "revealDelayedRandom"); err != errNoPendingReveal {
+		t.Fatalf("second reveal: got %v, want %v", err, errNoPendingReveal)
+	}
+	mustRunMethod(t, state, testCaller, "requestDelayedRandom", big.NewInt(1))
+}
+
+func TestDelayedRandomExpired(t *testing.T) {
+	state := newMockAccessibleState()
+	state.blockCtx.BlockNumber = big.NewInt(100)
+	state.blockCtx.GetHash = blockHashes(state)
+	mustRunMethod(t, state, testCaller, "requestDelayedRandom", big.NewInt(1))
+
+	// Past the 256 available block hashes the draw cannot be revealed, but can be replaced.
+	state.blockCtx.BlockNumber = big.NewInt(101 + 257)
+	if _, _, err := runMethod(state, testCaller, "revealDelayedRandom"); err != errRevealExpired {
+		t.Fatalf("expired reveal: got %v, want %v", err, errRevealExpired)
+	}
+	mustRunMethod(t, state, testCaller, "requestDelayedRandom", big.NewInt(1))
+	if _, _, err := runMethod(state, testCaller, "requestDelayedRandom", big.NewInt(1)); err != errRevealPending {
+		t.Fatalf("request over a pending draw: got %v, want %v", err, errRevealPending)
+	}
+}
+
+func TestDelayedRandomInvalidDelay(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, delay := range []int64{0, MaxRevealDelay + 1} {
+		if _, _, err := runMethod(state, testCaller, "requestDelayedRandom", big.NewInt(delay)); err != errInvalidRevealDelay {
+			t.Errorf("delay %d: got %v, want %v", delay, err, errInvalidRevealDelay)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	StockItemsBaseGas        = 1024 + contract.WriteGasCostPerSlot
+	StockItemsPerItemGas     = contract.WriteGasCostPerSlot
+	DrawAndDepleteBaseGas    = 1024 + contract.WriteGasCostPerSlot
+	DrawAndDepletePerItemGas = contract.ReadGasCostPerSlot
+
+	// MaxDepletionItems bounds the number of items of a depletion pool.
+	MaxDepletionItems = 256
+)
+
+var (
+	errInvalidItemCount = errors.New("pool must hold between 1 and MaxDepletionItems items")
+	errPoolDepleted     = errors.New("every item of the pool is depleted")
+)
+
+// StockItemsInput is the input of the stockItems method.
+type StockItemsInput struct {
+	PoolId  *big.Int
+	Weights []*big.Int
+}
+
+func PackStockItemsInput(poolId *big.Int, weights []*big.Int) ([]byte, error) {
+	return randomABI.Pack("stockItems", poolId, weights)
+}
+
+func UnpackStockItemsInput(input []byte) (StockItemsInput, error) {
+	var in StockItemsInput
+	if err := unpackInput("stockItems", input, &in); err != nil {
+		return StockItemsInput{}, err
+	}
+	if len(in.Weights) == 0 || len(in.Weights) > MaxDepletionItems {
+		return StockItemsInput{}, errInvalidItemCount
+	}
+	// Weights only decrease once stocked, so a total that fits in a uint256 now always will,
+	// which drawing from the pool relies on.
+	if _, err := cumulativeWeights(in.Weights); err != nil {
+		return StockItemsInput{}, err
+	}
+	return in, nil
+}
+
+func PackDrawAndDepleteInput(poolId *big.Int) ([]byte, error) {
+	return randomABI.Pack("drawAndDeplete", poolId)
+}
+
+func UnpackDrawAndDepleteInput(input []byte) (*big.Int, error) {
+	var poolId *big.Int
+	if err := unpackInput("drawAndDeplete", input, &poolId); err != nil {
+		return nil, err
+	}
+	return poolId, nil
+}
+
+func PackDrawAndDepleteOutput(itemId *big.Int) ([]byte, error) {
+	return randomABI.Methods["drawAndDeplete"].Outputs.Pack(itemId)
+}
+
+// depletionCountKey returns the slot holding the number of items of pool [poolId] of [caller].
+func depletionCountKey(caller common.Address, poolId *big.Int) common.Hash {
+	return stateKey("deplete.count", caller.Bytes(), common.BigToHash(poolId).Bytes())
+}
+
+// depletionWeightKey returns the slot holding the remaining weight of item [itemId] of pool
+// [poolId] of [caller].
+func depletionWeightKey(caller common.Address, poolId *big.Int, itemId uint64) common.Hash {
+	return stateKey("deplete.weight", caller.Bytes(), common.BigToHash(poolId).Bytes(), common.BigToHash(new(big.Int).SetUint64(itemId)).Bytes())
+}
+
+// drawAndDeplete draws an item of pool [poolId] of [caller] with probability proportional to
+// its remaining weight and decrements that weight by one, so an item of weight w can be drawn
+// exactly w times before it is no longer selectable.
+func drawAndDeplete(stream *randomStream, state contract.StateDB, addr common.Address, caller common.Address, poolId *big.Int, count uint64) (uint64, error) {
+	weights := make([]*big.Int, count)
+	total := new(big.Int)
+	for itemId := range weights {
+		weights[itemId] = state.GetState(addr, depletionWeightKey(caller, poolId, uint64(itemId))).Big()
+		total.Add(total, weights[itemId])
+	}
+	if total.Sign() == 0 {
+		return 0, errPoolDepleted
+	}
+
+	v := stream.uniform(total)
+	itemId := uint64(0)
+	for ; v.Cmp(weights[itemId]) >= 0; itemId++ {
+		v.Sub(v, weights[itemId])
+	}
+	remaining := weights[itemId].Sub(weights[itemId], common.Big1)
+	state.SetState(addr, depletionWeightKey(caller, poolId, itemId), common.BigToHash(remaining))
+	return itemId, nil
+}
+
+// StockItemsFunc (re)stocks pool [poolId] of the caller with one item per weight. Items are
+// identified by their index in the weights.
+func StockItemsFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackStockItemsInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, StockItemsBaseGas+uint64(len(in.Weights))*StockItemsPerItemGas); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+
+	state := accessibleState.GetStateDB()
+	state.SetState(addr, depletionCountKey(caller, in.PoolId), common.BigToHash(big.NewInt(int64(len(in.Weights)))))
+	for itemId, weight := range in.Weights {
+		state.SetState(addr, depletionWeightKey(caller, in.PoolId, uint64(itemId)), common.BigToHash(weight))
+	}
+
+	return []byte{}, remainingGas, nil
+}
+
+func DrawAndDepleteFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	poolId, err := UnpackDrawAndDepleteInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+
+	state := accessibleState.GetStateDB()
+	count := state.GetState(addr, depletionCountKey(caller, poolId)).Big().Uint64()
+	if remainingGas, err = contract.DeductGas(suppliedGas, DrawAndDepleteBaseGas+count*DrawAndDepletePerItemGas); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+
+	stream := newCallerStream(addr, caller, state)
+	itemId, err := drawAndDeplete(stream, state, addr, caller, poolId, count)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	ret, err = PackDrawAndDepleteOutput(new(big.Int).SetUint64(itemId))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,81 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+func TestDrawAndDeplete(t *testing.T) {
+	state := newMockAccessibleState()
+	poolId := big.NewInt(7)
+	weights := bigs(3, 0, 5)
+	mustRunMethod(t, state, testCaller, "stockItems", poolId, weights)
+
+	drawn := make([]int64, len(weights))
+	for nonce := uint64(0); nonce < 8; nonce++ {
+		state.state.SetNonce(testCaller, nonce)
+		itemId := mustRunMethod(t, state, testCaller, "drawAndDeplete", poolId)[0].(*big.Int).Int64()
+		drawn[itemId]++
+		if drawn[itemId] > weights[itemId].Int64() {
+			t.Fatalf("item %d drawn %d times with weight %v", itemId, drawn[itemId], weights[itemId])
+		}
+	}
+	for itemId, weight := range weights {
+		if drawn[itemId] != weight.Int64() {
+			t.Errorf("item %d: drawn %d times, want %v", itemId, drawn[itemId], weight)
+		}
+	}
+
+	if _, _, err := runMethod(state, testCaller, "drawAndDeplete", poolId); err != errPoolDepleted {
+		t.Fatalf("got %v, want %v", err, errPoolDepleted)
+	}
+	// Pools are scoped to the caller that stocked them.
+	if _, _, err := runMethod(state, testCaller, "drawAndDeplete", big.NewInt(8)); err != errPoolDepleted {
+		t.Fatalf("unstocked pool: got %v, want %v", err, errPoolDepleted)
+	}
+}
+
+func TestDrawAndDepleteProportional(t *testing.T) {
+	state := newMockAccessibleState()
+	poolId := big.NewInt(1)
+
+	first := 0
+	for nonce := uint64(0); nonce < 1000; nonce++ {
+		state.state.SetNonce(testCaller, nonce)
+		mustRunMethod(t, state, testCaller, "stockItems", poolId, bigs(1000, 3000))
+		if mustRunMethod(t, state, testCaller, "drawAndDeplete", poolId)[0].(*big.Int).Sign() == 0 {
+			first++
+		}
+	}
+	// The first item is drawn with probability 1/4, a standard deviation of about 14.
+	if first < 180 || first > 320 {
+		t.Errorf("first item drawn %d times out of 1000, expected about 250", first)
+	}
+}
+
+func TestStockItemsInvalidCount(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "stockItems", big.NewInt(1), bigs()); err != errInvalidItemCount {
+		t.Fatalf("got %v, want %v", err, errInvalidItemCount)
+	}
+}
+
+func TestStockItemsWeightOverflow(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "stockItems", big.NewInt(1), []*big.Int{math.MaxBig256, math.MaxBig256}); err != errWeightSumOverflow {
+		t.Fatalf("got %v, want %v", err, errWeightSumOverflow)
+	}
+	// Nothing was stocked, so the pool cannot be drawn from.
+	if _, _, err := runMethod(state, testCaller, "drawAndDeplete", big.NewInt(1)); err != errPoolDepleted {
+		t.Fatalf("got %v, want %v", err, errPoolDepleted)
+	}
+	// A total of exactly the largest uint256 can be stocked and drawn from.
+	mustRunMethod(t, state, testCaller, "stockItems", big.NewInt(1), []*big.Int{new(big.Int).Sub(math.MaxBig256, common.Big1), common.Big1})
+	mustRunMethod(t, state, testCaller, "drawAndDeplete", big.NewInt(1))
+}
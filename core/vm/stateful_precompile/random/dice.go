@@ -0,0 +1,126 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RollNotationBaseGas = 1024
+)
+
+var errInvalidDiceNotation = errors.New("dice notation must have the form NdM, NdM+K or NdM-K with N and M non-zero")
+
+// DiceRoll is a parsed dice expression NdM+K: Count dice of Sides faces each, plus Modifier.
+type DiceRoll struct {
+	Count    uint64
+	Sides    uint64
+	Modifier int64
+}
+
+// ParseDiceNotation parses standard dice notation such as "3d6", "d20" or "2d8-1". The count
+// defaults to one when omitted, the 'd' may be upper case and surrounding whitespace is
+// ignored. The count must not exceed MaxRandomValues.
+func ParseDiceNotation(expr string) (DiceRoll, error) {
+	expr = strings.ToLower(strings.TrimSpace(expr))
+	count, rest, ok := strings.Cut(expr, "d")
+	if !ok {
+		return DiceRoll{}, errInvalidDiceNotation
+	}
+
+	var roll DiceRoll
+	if count == "" {
+		roll.Count = 1
+	} else if roll.Count, ok = parseDiceNumber(count); !ok {
+		return DiceRoll{}, errInvalidDiceNotation
+	}
+
+	sides, modifier, hasModifier := rest, "", false
+	negative := false
+	if i := strings.IndexAny(rest, "+-"); i >= 0 {
+		sides, modifier, hasModifier, negative = rest[:i], rest[i+1:], true, rest[i] == '-'
+	}
+	if roll.Sides, ok = parseDiceNumber(sides); !ok {
+		return DiceRoll{}, errInvalidDiceNotation
+	}
+	if hasModifier {
+		k, ok := parseDiceNumber(modifier)
+		if !ok || k > math.MaxInt64 {
+			return DiceRoll{}, errInvalidDiceNotation
+		}
+		roll.Modifier = int64(k)
+		if negative {
+			roll.Modifier = -roll.Modifier
+		}
+	}
+
+	if roll.Count == 0 || roll.Sides == 0 {
+		return DiceRoll{}, errInvalidDiceNotation
+	}
+	if roll.Count > MaxRandomValues {
+		return DiceRoll{}, errTooManyValues
+	}
+	return roll, nil
+}
+
+// parseDiceNumber parses a decimal number of a dice expression.
+func parseDiceNumber(s string) (uint64, bool) {
+	v, err := strconv.ParseUint(s, 10, 64)
+	return v, err == nil
+}
+
+func PackRollNotationInput(expr string) ([]byte, error) {
+	return randomABI.Pack("rollNotation", []byte(expr))
+}
+
+func UnpackRollNotationInput(input []byte) (DiceRoll, error) {
+	var expr []byte
+	if err := unpackInput("rollNotation", input, &expr); err != nil {
+		return DiceRoll{}, err
+	}
+	return ParseDiceNotation(string(expr))
+}
+
+func PackRollNotationOutput(total *big.Int, rolls []*big.Int) ([]byte, error) {
+	return randomABI.Methods["rollNotation"].Outputs.Pack(total, rolls)
+}
+
+// rollDice rolls every die of [roll] uniformly in [1, Sides] and returns the total including
+// the modifier together with the individual results.
+func rollDice(stream *randomStream, roll DiceRoll) (*big.Int, []*big.Int) {
+	total := big.NewInt(roll.Modifier)
+	rolls := make([]*big.Int, roll.Count)
+	for i := range rolls {
+		rolls[i] = new(big.Int).SetUint64(stream.uniformUint64(roll.Sides) + 1)
+		total.Add(total, rolls[i])
+	}
+	return total, rolls
+}
+
+func RollNotationFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	roll, err := UnpackRollNotationInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, RollNotationBaseGas+roll.Count*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	total, rolls := rollDice(stream, roll)
+	ret, err = PackRollNotationOutput(total, rolls)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
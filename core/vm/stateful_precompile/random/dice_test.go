@@ -0,0 +1,54 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRollNotation(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, tt := range []struct {
+		expr     string
+		count    int
+		sides    int64
+		modifier int64
+	}{
+		{"3d6", 3, 6, 0},
+		{"1d20+5", 1, 20, 5},
+		{"D8-2", 1, 8, -2},
+	} {
+		for nonce := uint64(0); nonce < 50; nonce++ {
+			state.state.SetNonce(testCaller, nonce)
+			out := mustRunMethod(t, state, testCaller, "rollNotation", []byte(tt.expr))
+			total, rolls := out[0].(*big.Int), out[1].([]*big.Int)
+			if len(rolls) != tt.count {
+				t.Fatalf("%s: got %d rolls, want %d", tt.expr, len(rolls), tt.count)
+			}
+			sum := big.NewInt(tt.modifier)
+			for _, r := range rolls {
+				if r.Sign() <= 0 || r.Cmp(big.NewInt(tt.sides)) > 0 {
+					t.Fatalf("%s: roll %v out of range [1, %d]", tt.expr, r, tt.sides)
+				}
+				sum.Add(sum, r)
+			}
+			if total.Cmp(sum) != 0 {
+				t.Fatalf("%s: got total %v, want %v", tt.expr, total, sum)
+			}
+		}
+	}
+}
+
+func TestRollNotationMalformed(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, expr := range []string{"", "3x6", "3d", "d", "0d6", "3d0", "3d6+", "3d6+-2", "-3d6", "3d6d2", "2d6+1+1"} {
+		if _, _, err := runMethod(state, testCaller, "rollNotation", []byte(expr)); err != errInvalidDiceNotation {
+			t.Errorf("%q: got %v, want %v", expr, err, errInvalidDiceNotation)
+		}
+	}
+	if _, _, err := runMethod(state, testCaller, "rollNotation", []byte("1025d6")); err != errTooManyValues {
+		t.Errorf("too many dice: got %v, want %v", err, errTooManyValues)
+	}
+}
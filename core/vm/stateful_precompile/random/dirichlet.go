@@ -0,0 +1,163 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomDirichletBaseGas      = 1024
+	RandomDirichletPerWeightGas = 2048
+
+	// MaxDirichletDimensions bounds the number of weights of every sampled vector.
+	MaxDirichletDimensions = 64
+)
+
+// MaxDirichletAlpha bounds every concentration parameter, 1e6 in 1e18 fixed point.
+var MaxDirichletAlpha = new(big.Int).Mul(big.NewInt(1e6), wad)
+
+var (
+	errInvalidDirichletDimensions = errors.New("number of alphas must be between 1 and MaxDirichletDimensions")
+	errInvalidDirichletAlpha      = errors.New("every alpha must be non-zero and at most MaxDirichletAlpha")
+)
+
+// RandomDirichletInput is the input of the randomDirichlet method.
+type RandomDirichletInput struct {
+	AlphasScaled []*big.Int
+	N            *big.Int
+}
+
+func PackRandomDirichletInput(alphasScaled []*big.Int, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomDirichlet", alphasScaled, n)
+}
+
+func UnpackRandomDirichletInput(input []byte) ([]*big.Int, uint64, error) {
+	var in RandomDirichletInput
+	if err := unpackInput("randomDirichlet", input, &in); err != nil {
+		return nil, 0, err
+	}
+	if len(in.AlphasScaled) == 0 || len(in.AlphasScaled) > MaxDirichletDimensions {
+		return nil, 0, errInvalidDirichletDimensions
+	}
+	for _, alpha := range in.AlphasScaled {
+		if alpha.Sign() == 0 || alpha.Cmp(MaxDirichletAlpha) > 0 {
+			return nil, 0, errInvalidDirichletAlpha
+		}
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues || in.N.Uint64()*uint64(len(in.AlphasScaled)) > MaxRandomValues {
+		return nil, 0, errTooManyValues
+	}
+	return in.AlphasScaled, in.N.Uint64(), nil
+}
+
+func PackRandomDirichletOutput(weights [][]*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomDirichlet"].Outputs.Pack(weights)
+}
+
+// openUnitWad draws a value uniformly from the open interval (0, 1) in fixed point.
+func openUnitWad(stream *randomStream) *big.Int {
+	v := stream.uniform(new(big.Int).Sub(wad, common.Big1))
+	return v.Add(v, common.Big1)
+}
+
+// standardNormal draws a standard normal value in fixed point with the Marsaglia polar
+// method, which needs only a logarithm and a square root.
+func standardNormal(stream *randomStream) *big.Int {
+	for {
+		u, v := symmetric(stream, wad), symmetric(stream, wad)
+		s := new(big.Int).Add(wadMul(u, u), wadMul(v, v))
+		if s.Sign() == 0 || s.Cmp(wad) >= 0 {
+			continue
+		}
+		f := wadDiv(new(big.Int).Neg(new(big.Int).Lsh(wadLn(s), 1)), s)
+		return wadMul(u, wadSqrt(f))
+	}
+}
+
+// logGamma draws the logarithm of a Gamma(alpha, 1) value, with [alpha] in fixed point, using
+// the Marsaglia-Tsang squeeze-free method. Shapes below one are boosted to alpha+1 and scaled
+// back by U^(1/alpha). Working with logarithms keeps the tiny draws of small shapes from
+// underflowing before they are normalized.
+func logGamma(stream *randomStream, alpha *big.Int) *big.Int {
+	if alpha.Cmp(wad) < 0 {
+		boosted := logGamma(stream, new(big.Int).Add(alpha, wad))
+		return boosted.Add(boosted, wadDiv(wadLn(openUnitWad(stream)), alpha))
+	}
+
+	d := new(big.Int).Sub(alpha, new(big.Int).Quo(wad, big.NewInt(3)))
+	c := wadDiv(wad, wadSqrt(new(big.Int).Mul(d, big.NewInt(9))))
+	for {
+		x := standardNormal(stream)
+		v := new(big.Int).Add(wad, wadMul(c, x))
+		if v.Sign() <= 0 {
+			continue
+		}
+		v = wadMul(wadMul(v, v), v)
+		if v.Sign() == 0 {
+			continue
+		}
+		lnV := wadLn(v)
+		// Accept if ln(u) < x^2/2 + d - d*v + d*ln(v).
+		bound := new(big.Int).Rsh(wadMul(x, x), 1)
+		bound.Add(bound, d).Sub(bound, wadMul(d, v)).Add(bound, wadMul(d, lnV))
+		if wadLn(openUnitWad(stream)).Cmp(bound) < 0 {
+			return lnV.Add(lnV, wadLn(d))
+		}
+	}
+}
+
+// generateDirichlet draws a weight vector from Dirichlet([alphas]) in fixed point: one Gamma
+// draw per alpha, normalized to sum to one. The normalization runs on the logarithms shifted
+// by their maximum, so the largest weight is exp(0) and the sum never vanishes. Rounding dust
+// goes to the largest weight, making the vector sum to exactly 1e18.
+func generateDirichlet(stream *randomStream, alphas []*big.Int) []*big.Int {
+	logs := make([]*big.Int, len(alphas))
+	largest := 0
+	for i, alpha := range alphas {
+		logs[i] = logGamma(stream, alpha)
+		if logs[i].Cmp(logs[largest]) > 0 {
+			largest = i
+		}
+	}
+
+	sum := new(big.Int)
+	for i := range logs {
+		logs[i] = wadExp(logs[i].Sub(logs[i], logs[largest]))
+		sum.Add(sum, logs[i])
+	}
+	remainder := new(big.Int).Set(wad)
+	for i := range logs {
+		logs[i] = wadDiv(logs[i], sum)
+		remainder.Sub(remainder, logs[i])
+	}
+	logs[largest].Add(logs[largest], remainder)
+	return logs
+}
+
+func RandomDirichletFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	alphas, n, err := UnpackRandomDirichletInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomDirichletBaseGas+n*uint64(len(alphas))*RandomDirichletPerWeightGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	weights := make([][]*big.Int, n)
+	for i := range weights {
+		weights[i] = generateDirichlet(stream, alphas)
+	}
+	ret, err = PackRandomDirichletOutput(weights)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
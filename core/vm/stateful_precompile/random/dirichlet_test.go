@@ -0,0 +1,57 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestRandomDirichlet(t *testing.T) {
+	state := newMockAccessibleState()
+	alphas := []*big.Int{floatWad(0.5), floatWad(1), floatWad(3.5)}
+
+	means := make([]float64, len(alphas))
+	const draws, n = 20, 10
+	for nonce := uint64(0); nonce < draws; nonce++ {
+		state.state.SetNonce(testCaller, nonce)
+		weights := mustRunMethod(t, state, testCaller, "randomDirichlet", alphas, big.NewInt(n))[0].([][]*big.Int)
+		if len(weights) != n {
+			t.Fatalf("got %d vectors, want %d", len(weights), n)
+		}
+		for _, vector := range weights {
+			if len(vector) != len(alphas) {
+				t.Fatalf("got %d weights, want %d", len(vector), len(alphas))
+			}
+			sum := new(big.Int)
+			for i, w := range vector {
+				sum.Add(sum, w)
+				means[i] += wadFloat(w) / (draws * n)
+			}
+			if sum.Cmp(wad) != 0 {
+				t.Fatalf("nonce %d: weights %v sum to %v, want 1e18", nonce, vector, sum)
+			}
+		}
+	}
+	// The mean of weight i is alpha_i / sum(alpha).
+	for i, want := range []float64{0.1, 0.2, 0.7} {
+		if math.Abs(means[i]-want) > 0.05 {
+			t.Errorf("mean weight %d = %.3f, want about %.3f", i, means[i], want)
+		}
+	}
+}
+
+func TestRandomDirichletInvalidInput(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "randomDirichlet", []*big.Int{wad, new(big.Int)}, big.NewInt(1)); err != errInvalidDirichletAlpha {
+		t.Errorf("zero alpha: got %v, want %v", err, errInvalidDirichletAlpha)
+	}
+	if _, _, err := runMethod(state, testCaller, "randomDirichlet", []*big.Int{}, big.NewInt(1)); err != errInvalidDirichletDimensions {
+		t.Errorf("no alphas: got %v, want %v", err, errInvalidDirichletDimensions)
+	}
+	if _, _, err := runMethod(state, testCaller, "randomDirichlet", []*big.Int{wad, wad}, big.NewInt(MaxRandomValues)); err != errTooManyValues {
+		t.Errorf("too many weights: got %v, want %v", err, errTooManyValues)
+	}
+}
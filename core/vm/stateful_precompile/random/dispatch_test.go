@@ -0,0 +1,56 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+func TestRandomHandlersCoverABI(t *testing.T) {
+	handlers := randomHandlers(DefaultConfig(), options{})
+	for name := range randomABI.Methods {
+		if handlers[name] == nil {
+			t.Errorf("method %s has no handler", name)
+		}
+	}
+	for name := range handlers {
+		if _, ok := randomABI.Methods[name]; !ok {
+			t.Errorf("handler %s has no method", name)
+		}
+	}
+}
+
+func TestRandomPrecompileDispatch(t *testing.T) {
+	// Every stub returns the name it is registered under.
+	handlers := make(map[string]contract.RunStatefulPrecompileFunc, len(randomABI.Methods))
+	for name := range randomABI.Methods {
+		handlers[name] = func(_ contract.AccessibleState, _ common.Address, _ common.Address, _ []byte, suppliedGas uint64, _ bool) ([]byte, uint64, error) {
+			return []byte(name), suppliedGas, nil
+		}
+	}
+	precompile := newRandomPrecompile(handlers)
+	state := newMockAccessibleState()
+
+	for name, method := range randomABI.Methods {
+		ret, _, err := precompile.Run(state, testCaller, randomNCSPRNGContractAddr, method.ID, testGas, true)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if string(ret) != name {
+			t.Errorf("selector %#x of %s routed to %s", method.ID, name, ret)
+		}
+	}
+
+	_, remainingGas, err := precompile.Run(state, testCaller, randomNCSPRNGContractAddr, []byte{0xde, 0xad, 0xbe, 0xef}, testGas, true)
+	if err == nil || !strings.Contains(err.Error(), "invalid function selector 0xdeadbeef") {
+		t.Fatalf("unknown selector: got %v", err)
+	}
+	if remainingGas != testGas {
+		t.Errorf("unknown selector consumed %d gas", testGas-remainingGas)
+	}
+}
@@ -0,0 +1,68 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomWithDomainBaseGas = 1024
+)
+
+// RandomWithDomainInput is the input of the randomWithDomain method.
+type RandomWithDomainInput struct {
+	DomainTag [32]byte
+	N         *big.Int
+}
+
+func PackRandomWithDomainInput(domainTag common.Hash, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomWithDomain", [32]byte(domainTag), n)
+}
+
+func UnpackRandomWithDomainInput(input []byte) (common.Hash, uint64, error) {
+	var in RandomWithDomainInput
+	if err := unpackInput("randomWithDomain", input, &in); err != nil {
+		return common.Hash{}, 0, err
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return common.Hash{}, 0, errTooManyValues
+	}
+	return in.DomainTag, in.N.Uint64(), nil
+}
+
+func PackRandomWithDomainOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomWithDomain"].Outputs.Pack(randomValues)
+}
+
+// newDomainStream returns the stream of [caller] at its current nonce separated by
+// [domainTag]. The tag is appended to the user seed, so the i-th word is
+// HMAC(serverSeed, userSeed || domainTag || nonce || i): applications using different tags
+// draw from independent streams for the same caller and nonce.
+func newDomainStream(precompileAddr common.Address, caller common.Address, domainTag common.Hash, state contract.StateDB) *randomStream {
+	key := activeServerSeed(state, precompileAddr)
+	seed := append(userSeed(key, caller), domainTag.Bytes()...)
+	return newRandomStream(key, seed, state.GetNonce(caller))
+}
+
+func RandomWithDomainFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	domainTag, n, err := UnpackRandomWithDomainInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomWithDomainBaseGas+n*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newDomainStream(addr, caller, domainTag, accessibleState.GetStateDB())
+	ret, err = PackRandomWithDomainOutput(stream.values(n))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,41 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"math/bits"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRandomWithDomain(t *testing.T) {
+	const n = 64
+	state := newMockAccessibleState()
+	tagA, tagB := common.HexToHash("0xa"), common.HexToHash("0xb")
+
+	a := mustRunMethod(t, state, testCaller, "randomWithDomain", [32]byte(tagA), big.NewInt(n))[0].([]*big.Int)
+	again := mustRunMethod(t, state, testCaller, "randomWithDomain", [32]byte(tagA), big.NewInt(n))[0].([]*big.Int)
+	b := mustRunMethod(t, state, testCaller, "randomWithDomain", [32]byte(tagB), big.NewInt(n))[0].([]*big.Int)
+
+	// Independent streams agree on each bit with probability 1/2: 8192 expected matching bits
+	// out of 16384, with a standard deviation of 64.
+	matching := 0
+	for i := range a {
+		if a[i].Cmp(again[i]) != 0 {
+			t.Fatalf("value %d not reproduced for the same tag", i)
+		}
+		if a[i].Cmp(b[i]) == 0 {
+			t.Fatalf("value %d identical across tags", i)
+		}
+		wa, wb := common.BigToHash(a[i]), common.BigToHash(b[i])
+		for j := range wa {
+			matching += 8 - bits.OnesCount8(wa[j]^wb[j])
+		}
+	}
+	if matching < 7800 || matching > 8600 {
+		t.Errorf("got %d matching bits across tags, expected about 8192", matching)
+	}
+}
@@ -0,0 +1,88 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomWithDropoutBaseGas = 1024
+	// RandomWithDropoutPerValueGas covers drawing a value and deciding whether it is dropped.
+	RandomWithDropoutPerValueGas = 2 * RandomPerValueGas
+)
+
+// randomWithDropoutDomain separates the stream deciding which values of randomWithDropout are
+// dropped from the stream generating them.
+const randomWithDropoutDomain = "randomWithDropout.mask"
+
+// RandomWithDropoutInput is the input of the randomWithDropout method.
+type RandomWithDropoutInput struct {
+	N           *big.Int
+	DropProbBps *big.Int
+}
+
+func PackRandomWithDropoutInput(n *big.Int, dropProbBps *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomWithDropout", n, dropProbBps)
+}
+
+// UnpackRandomWithDropoutInput returns the number of values and the probability in basis
+// points of dropping each of them.
+func UnpackRandomWithDropoutInput(input []byte) (uint64, uint64, error) {
+	var in RandomWithDropoutInput
+	if err := unpackInput("randomWithDropout", input, &in); err != nil {
+		return 0, 0, err
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return 0, 0, errTooManyValues
+	}
+	if !in.DropProbBps.IsUint64() || in.DropProbBps.Uint64() > maxBasisPoints {
+		return 0, 0, errInvalidProbability
+	}
+	return in.N.Uint64(), in.DropProbBps.Uint64(), nil
+}
+
+func PackRandomWithDropoutOutput(randomValues []*big.Int, dropped []bool) ([]byte, error) {
+	return randomABI.Methods["randomWithDropout"].Outputs.Pack(randomValues, dropped)
+}
+
+// generateRandomWithDropout returns the next [n] values of [stream] and a parallel mask
+// dropping each of them with probability [dropProbBps] / maxBasisPoints. Dropped values are
+// zeroed. The mask is drawn from the stream of [stream] in the randomWithDropoutDomain, so
+// the values that are kept are the ones randomNCSPRNG would have returned.
+func generateRandomWithDropout(stream *randomStream, n uint64, dropProbBps uint64) ([]*big.Int, []bool) {
+	values := stream.values(n)
+	mask := stream.withDomain(randomWithDropoutDomain)
+	dropped := make([]bool, n)
+	for i := range dropped {
+		if mask.bernoulli(dropProbBps) {
+			dropped[i] = true
+			values[i] = new(big.Int)
+		}
+	}
+	return values, dropped
+}
+
+// RandomWithDropoutFunc returns [n] values of the caller, each dropped with probability
+// [dropProbBps] / 10000, for harnesses simulating partial failures reproducibly.
+func RandomWithDropoutFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	n, dropProbBps, err := UnpackRandomWithDropoutInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomWithDropoutBaseGas+n*RandomWithDropoutPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackRandomWithDropoutOutput(generateRandomWithDropout(stream, n, dropProbBps))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
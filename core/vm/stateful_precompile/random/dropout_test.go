@@ -0,0 +1,42 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRandomWithDropout(t *testing.T) {
+	state := newMockAccessibleState()
+	plain := newCallerStream(randomNCSPRNGContractAddr, testCaller, state.GetStateDB()).values(MaxRandomValues)
+	for _, bps := range []int64{0, 2500, 5000, maxBasisPoints} {
+		out := mustRunMethod(t, state, testCaller, "randomWithDropout", big.NewInt(MaxRandomValues), big.NewInt(bps))
+		values, dropped := out[0].([]*big.Int), out[1].([]bool)
+		if len(values) != MaxRandomValues || len(dropped) != MaxRandomValues {
+			t.Fatalf("bps=%d: got %d values and %d mask entries, want %d", bps, len(values), len(dropped), MaxRandomValues)
+		}
+
+		var drops int64
+		for i := range values {
+			if dropped[i] {
+				drops++
+				if values[i].Sign() != 0 {
+					t.Fatalf("bps=%d: dropped value %d is %x, want 0", bps, i, values[i])
+				}
+			} else if values[i].Cmp(plain[i]) != 0 {
+				t.Fatalf("bps=%d: kept value %d is %x, want the caller's %x", bps, i, values[i], plain[i])
+			}
+		}
+		// A binomial count over 1024 draws stays within 64 of its mean, four standard
+		// deviations at worst.
+		if mean := bps * MaxRandomValues / maxBasisPoints; drops < mean-64 || drops > mean+64 {
+			t.Errorf("bps=%d: dropped %d of %d, want about %d", bps, drops, MaxRandomValues, mean)
+		}
+	}
+
+	if _, _, err := runMethod(state, testCaller, "randomWithDropout", big.NewInt(1), big.NewInt(maxBasisPoints+1)); err != errInvalidProbability {
+		t.Errorf("got %v, want %v", err, errInvalidProbability)
+	}
+}
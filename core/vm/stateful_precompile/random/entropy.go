@@ -0,0 +1,74 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/bits"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var errInsufficientEntropy = errors.New("fewer independent entropy sources available than required")
+
+// independentEntropySources returns the number of inputs flagged in [sources] that callers
+// cannot compute on their own: a server seed other than the public default one, configured or
+// revealed, and the PREVRANDAO value of the block. The account nonce, the epoch and the call
+// counter only separate streams and are not counted.
+func independentEntropySources(sources uint64) uint {
+	return uint(bits.OnesCount64(sources & (EntropySourceConfiguredSeed | EntropySourceRevealedSeed | EntropySourcePrevRandao)))
+}
+
+// ncsprngBaseSeed returns the server seed randomNCSPRNG derives its keys from, together with the
+// EntropySource bit identifying it: the seed revealed through revealServerSeed if any, else the
+// seed configured with WithServerSeed, else the default keccak(precompileAddr).
+//
+// A configured seed of zero means the operator left the precompile without any entropy
+// source. Rather than keying every stream with zero, the precompile falls back to the default
+// seed, which keeps the output deterministic across nodes, and flags the fallback with
+// EntropySourceFallback so that it can be surfaced loudly.
+func ncsprngBaseSeed(state contract.StateDB, precompileAddr common.Address, o options) ([]byte, uint64) {
+	if seed := state.GetState(precompileAddr, serverSeedActiveKey); seed != (common.Hash{}) {
+		return seed.Bytes(), EntropySourceRevealedSeed
+	}
+	if o.serverSeed == nil {
+		return serverSeed(precompileAddr), EntropySourceDefaultSeed
+	}
+	if *o.serverSeed == (common.Hash{}) {
+		return serverSeed(precompileAddr), EntropySourceDefaultSeed | EntropySourceFallback
+	}
+	return o.serverSeed.Bytes(), EntropySourceConfiguredSeed
+}
+
+// EntropyFallbackGasCost is charged by draws that are not read-only and served from the
+// fallback seed, for the EntropyFallback event they emit.
+const EntropyFallbackGasCost = params.LogGas + 2*params.LogTopicGas
+
+// entropyFallbackWarning makes the node warn about the fallback seed once, however many calls
+// are served from it, so that callers cannot flood its log.
+var entropyFallbackWarning sync.Once
+
+// reportEntropyFallback warns the node operator, once, that the precompile serves draws from
+// the fallback seed and, outside of read-only calls, charges EntropyFallbackGasCost out of
+// [remainingGas] and emits an EntropyFallback event for [caller] so that contracts and
+// indexers can notice it as well. It returns the gas left.
+func reportEntropyFallback(state contract.StateDB, precompileAddr common.Address, caller common.Address, blockNumber uint64, readOnly bool, remainingGas uint64) (uint64, error) {
+	entropyFallbackWarning.Do(func() {
+		log.Warn("Random precompile has no entropy source configured, using fallback server seed", "precompile", precompileAddr)
+	})
+	if readOnly {
+		return remainingGas, nil
+	}
+	remainingGas, err := contract.DeductGas(remainingGas, EntropyFallbackGasCost)
+	if err != nil {
+		return 0, err
+	}
+	topics := []common.Hash{randomABI.Events["EntropyFallback"].ID, common.BytesToHash(caller.Bytes())}
+	state.AddLog(precompileAddr, topics, nil, blockNumber)
+	return remainingGas, nil
+}
@@ -0,0 +1,134 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRandomNCSPRNGEntropyFallback(t *testing.T) {
+	state := newMockAccessibleState()
+	defaults := runRandomNCSPRNG(t, state, 2, false)
+	if logs := eventLogs(state.state, "EntropyFallback"); len(logs) != 0 {
+		t.Fatalf("default configuration emitted %d logs", len(logs))
+	}
+
+	values := runRandomNCSPRNG(t, state, 2, false, WithServerSeed(common.Hash{}))
+	for i := range values {
+		if values[i].Sign() == 0 || values[i].Cmp(defaults[i]) != 0 {
+			t.Fatalf("value %d: got %x, want the default seed output %x", i, values[i], defaults[i])
+		}
+	}
+	logs := eventLogs(state.state, "EntropyFallback")
+	if len(logs) != 1 {
+		t.Fatalf("got %d logs, want 1", len(logs))
+	}
+	if topics := state.state.logTopics[logs[0]]; topics[1] != common.BytesToHash(testCaller.Bytes()) {
+		t.Fatalf("unexpected log topics %v", topics)
+	}
+
+	runRandomNCSPRNG(t, state, 2, true, WithServerSeed(common.Hash{}))
+	if len(eventLogs(state.state, "EntropyFallback")) != 1 {
+		t.Fatalf("read-only call emitted a log")
+	}
+
+	configured := runRandomNCSPRNG(t, state, 2, false, WithServerSeed(common.HexToHash("0x5eed")))
+	if configured[0].Cmp(defaults[0]) == 0 || len(eventLogs(state.state, "EntropyFallback")) != 1 {
+		t.Fatalf("configured seed was not used")
+	}
+}
+
+func TestRandomNCSPRNGEntropyFallbackGas(t *testing.T) {
+	input, err := PackRandomNCSPRNGInput(big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	used := func(readOnly bool, opts ...Option) uint64 {
+		t.Helper()
+		_, remaining, err := CreateRandomNCSPRNGPrecompile(DefaultConfig(), opts...).Run(newMockAccessibleState(), testCaller, randomNCSPRNGContractAddr, input, testGas, readOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return testGas - remaining
+	}
+
+	// The EntropyFallback event is paid for, and read-only calls emit none.
+	if got, want := used(false, WithServerSeed(common.Hash{})), used(false)+EntropyFallbackGasCost; got != want {
+		t.Errorf("fallback draw used %d gas, want %d", got, want)
+	}
+	if got, want := used(true, WithServerSeed(common.Hash{})), used(true); got != want {
+		t.Errorf("read-only fallback draw used %d gas, want %d", got, want)
+	}
+}
+
+func TestMinEntropySources(t *testing.T) {
+	seed := WithServerSeed(common.HexToHash("0x5eed"))
+	random := common.HexToHash("0xabcd")
+	tests := []struct {
+		name       string
+		opts       []Option
+		prevRandao bool
+		want       error
+	}{
+		{"default seed", []Option{WithMinEntropySources(1)}, false, errInsufficientEntropy},
+		{"fallback seed", []Option{WithServerSeed(common.Hash{}), WithMinEntropySources(1)}, false, errInsufficientEntropy},
+		{"configured seed", []Option{seed, WithMinEntropySources(1)}, false, nil},
+		{"prevrandao", []Option{WithMinEntropySources(1)}, true, nil},
+		{"no prevrandao", []Option{seed, WithMinEntropySources(2)}, false, errInsufficientEntropy},
+		{"both", []Option{seed, WithMinEntropySources(2)}, true, nil},
+		{"unreachable", []Option{seed, WithMinEntropySources(3)}, true, errInsufficientEntropy},
+	}
+	for _, tt := range tests {
+		state := newMockAccessibleState()
+		if tt.prevRandao {
+			state.blockCtx.Random = &random
+		}
+		precompile := CreateRandomNCSPRNGPrecompile(DefaultConfig(), tt.opts...)
+		for _, method := range []string{"randomNCSPRNG", "randomOne", "randomRaw"} {
+			var args []interface{}
+			if method != "randomOne" {
+				args = append(args, big.NewInt(2))
+			}
+			input, err := randomABI.Pack(method, args...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := precompile.Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, true); err != tt.want {
+				t.Errorf("%s, %s: got %v, want %v", tt.name, method, err, tt.want)
+			}
+		}
+	}
+}
+
+func TestEntropyFallbackFromSolidity(t *testing.T) {
+	// Solidity issues a STATICCALL for view methods, which could not emit the warning.
+	two := big.NewInt(2)
+	tests := []struct {
+		method string
+		args   []interface{}
+	}{
+		{"randomNCSPRNG", []interface{}{two}},
+		{"randomOne", nil},
+		{"randomBytes", []interface{}{two}},
+		{"randomRaw", []interface{}{two}},
+		{"randomFromCounter", []interface{}{big.NewInt(7), two}},
+		{"randomInRange", []interface{}{big.NewInt(10), big.NewInt(20), two}},
+		{"randomWithLineage", []interface{}{two}},
+		{"randomWithProvenance", []interface{}{two}},
+		{"randomWithSelfCheck", []interface{}{two}},
+		{"shuffledRandom", []interface{}{two}},
+	}
+	for _, tt := range tests {
+		state := newMockAccessibleState()
+		if _, err := runMethodAsSolidity(state, testCaller, []Option{WithServerSeed(common.Hash{})}, tt.method, tt.args...); err != nil {
+			t.Fatalf("%s: %v", tt.method, err)
+		}
+		if logs := eventLogs(state.state, "EntropyFallback"); len(logs) != 1 {
+			t.Errorf("%s: got %d EntropyFallback logs, want 1", tt.method, len(logs))
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// epochServerSeed returns the server seed of [epoch] derived from [baseSeed], as
+// keccak(baseSeed || epoch) with the epoch encoded as a 32 byte big-endian word. The base seed
+// cannot be recovered from an epoch seed, so leaking the seed of one epoch reveals nothing
+// about the draws of the others.
+func epochServerSeed(baseSeed []byte, epoch uint64) []byte {
+	return crypto.Keccak256(baseSeed, common.BigToHash(new(big.Int).SetUint64(epoch)).Bytes())
+}
+
+// ncsprngServerSeed returns the key of the randomNCSPRNG streams in the block of [blockCtx]: the
+// base seed selected by ncsprngBaseSeed, mixed with the stored block seed when [o] enables it,
+// or the seed of the epoch containing the block derived from it when [o] sets an epoch length,
+// mixed with the hash of the parent block and the PREVRANDAO value of the block when there are
+// ones. It also returns the EntropySource bits of the key.
+func ncsprngServerSeed(state contract.StateDB, precompileAddr common.Address, blockCtx *vm.BlockContext, o options) ([]byte, uint64) {
+	seed, sources := ncsprngBaseSeed(state, precompileAddr, o)
+	seed, blockSeedSources := blockSeedServerSeed(state, precompileAddr, seed, o)
+	sources |= blockSeedSources
+	if o.epochLength != 0 {
+		seed = epochServerSeed(seed, blockCtx.BlockNumber.Uint64()/o.epochLength)
+		sources |= EntropySourceEpoch
+	}
+	if parentHash := blockParentHash(blockCtx); parentHash != (common.Hash{}) {
+		seed = parentHashServerSeed(seed, parentHash)
+		sources |= EntropySourceParentHash
+	}
+	if blockCtx.Random != nil {
+		seed = prevRandaoServerSeed(seed, *blockCtx.Random)
+		sources |= EntropySourcePrevRandao
+	}
+	return seed, sources
+}
+
+// prevRandaoServerSeed returns keccak([seed] || [prevRandao]), the server seed mixed with the
+// PREVRANDAO value of the block. The beacon chain randomness is not known to callers before
+// the block is proposed, so values can no longer be computed ahead of time from the caller and
+// its nonce alone. The proposer of the block still knows it and may withhold the block.
+// Pre-merge chains have no PREVRANDAO and keep the unmixed seed.
+func prevRandaoServerSeed(seed []byte, prevRandao common.Hash) []byte {
+	return crypto.Keccak256(seed, prevRandao.Bytes())
+}
+
+// blockParentHash returns the hash of the parent of the block of [blockCtx], or the zero hash
+// at genesis and when the context cannot look up block hashes.
+func blockParentHash(blockCtx *vm.BlockContext) common.Hash {
+	if blockCtx.GetHash == nil || blockCtx.BlockNumber.Sign() == 0 {
+		return common.Hash{}
+	}
+	return blockCtx.GetHash(blockCtx.BlockNumber.Uint64() - 1)
+}
+
+// parentHashServerSeed returns keccak([seed] || [parentHash]), the server seed mixed with the
+// hash of the parent block, so values also depend on the chain history and cannot be
+// computed before the parent block is sealed. The parent hash is public by the time the block
+// is built, so unlike PREVRANDAO it adds no independent entropy.
+func parentHashServerSeed(seed []byte, parentHash common.Hash) []byte {
+	return crypto.Keccak256(seed, parentHash.Bytes())
+}
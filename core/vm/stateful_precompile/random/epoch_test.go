@@ -0,0 +1,65 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+// runEpochDraw draws [n] values through a precompile with an epoch length of [epochLength] at
+// [blockNumber].
+func runEpochDraw(t *testing.T, state *mockAccessibleState, epochLength uint64, blockNumber int64, n int64) []*big.Int {
+	t.Helper()
+	state.blockCtx.BlockNumber = big.NewInt(blockNumber)
+	input, err := PackRandomNCSPRNGInput(big.NewInt(n))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret, _, err := CreateRandomNCSPRNGPrecompile(DefaultConfig(), WithEpochLength(epochLength)).Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := randomABI.Methods["randomNCSPRNG"].Outputs.Unpack(ret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out[0].([]*big.Int)
+}
+
+func TestRandomNCSPRNGEpochSeeds(t *testing.T) {
+	const (
+		epochLength = 100
+		n           = 16
+	)
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 3)
+
+	first := runEpochDraw(t, state, epochLength, 150, n)
+	sameEpoch := runEpochDraw(t, state, epochLength, 199, n)
+	nextEpoch := runEpochDraw(t, state, epochLength, 200, n)
+
+	for i := range first {
+		if first[i].Cmp(sameEpoch[i]) != 0 {
+			t.Fatalf("value %d differs within an epoch: %x != %x", i, first[i], sameEpoch[i])
+		}
+	}
+	seen := make(map[string]bool)
+	for _, v := range first {
+		seen[v.String()] = true
+	}
+	for i, v := range nextEpoch {
+		if seen[v.String()] {
+			t.Fatalf("value %d of the next epoch repeats a value of the previous one", i)
+		}
+	}
+
+	key := epochServerSeed(serverSeed(randomNCSPRNGContractAddr), 2)
+	stream := newRandomStream(key, userSeed(key, testCaller), 3)
+	for i, v := range nextEpoch {
+		if want := stream.next(); v.Cmp(want) != 0 {
+			t.Errorf("value %d: got %x, want %x", i, v, want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// FairGameBaseGas covers reading the nonces of both players and deriving their seeds.
+	FairGameBaseGas = 1024 + 2*MultiPartyRandomPerContributor
+)
+
+var errSelfOpponent = errors.New("caller cannot be its own opponent")
+
+// FairGameInput is the input of the fairGame method.
+type FairGameInput struct {
+	Opponent common.Address
+	N        *big.Int
+}
+
+func PackFairGameInput(opponent common.Address, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("fairGame", opponent, n)
+}
+
+func UnpackFairGameInput(input []byte) (FairGameInput, error) {
+	var in FairGameInput
+	if err := unpackInput("fairGame", input, &in); err != nil {
+		return FairGameInput{}, err
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return FairGameInput{}, errTooManyValues
+	}
+	return in, nil
+}
+
+func PackFairGameOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["fairGame"].Outputs.Pack(randomValues)
+}
+
+// playerSeed returns the seed [player] contributes to a game under [serverSeed]: its user seed
+// at its current account nonce, which it cannot know ahead of the other player's moves.
+func playerSeed(state contract.StateDB, serverSeed []byte, player common.Address) []byte {
+	return crypto.Keccak256(userSeed(serverSeed, player), common.BigToHash(new(big.Int).SetUint64(state.GetNonce(player))).Bytes())
+}
+
+// fairGameSeed combines the seeds of [player] and [opponent] symmetrically: they are hashed in
+// the order of the sorted addresses, so the seed is the same whichever player asks for it, and
+// hashed rather than XORed, so that neither can cancel out the other.
+func fairGameSeed(state contract.StateDB, serverSeed []byte, player common.Address, opponent common.Address) []byte {
+	if bytes.Compare(player[:], opponent[:]) > 0 {
+		player, opponent = opponent, player
+	}
+	return crypto.Keccak256([]byte("fairGame"), playerSeed(state, serverSeed, player), playerSeed(state, serverSeed, opponent))
+}
+
+// FairGameFunc returns [n] values for a head-to-head game between the caller and [opponent].
+// Both players get the same values from the same state, so either can settle the game, and
+// the values depend on the seeds of both, so neither can predict them alone.
+func FairGameFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackFairGameInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	n := in.N.Uint64()
+	if remainingGas, err = contract.DeductGas(suppliedGas, FairGameBaseGas+n*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+	if in.Opponent == caller {
+		return nil, remainingGas, errSelfOpponent
+	}
+
+	key := serverSeed(addr)
+	stream := newRandomStream(key, fairGameSeed(accessibleState.GetStateDB(), key, caller, in.Opponent), 0)
+	ret, err = PackFairGameOutput(stream.values(n))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
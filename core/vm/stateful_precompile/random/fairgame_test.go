@@ -0,0 +1,48 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestFairGame(t *testing.T) {
+	state := newMockAccessibleState()
+	alice, bob, carol := common.HexToAddress("0xa11ce"), common.HexToAddress("0xb0b"), common.HexToAddress("0xca401")
+	state.state.SetNonce(alice, 3)
+	state.state.SetNonce(bob, 8)
+
+	fromAlice := mustRunMethod(t, state, alice, "fairGame", bob, big.NewInt(4))[0].([]*big.Int)
+	fromBob := mustRunMethod(t, state, bob, "fairGame", alice, big.NewInt(4))[0].([]*big.Int)
+	if len(fromAlice) != 4 {
+		t.Fatalf("got %d values, want 4", len(fromAlice))
+	}
+	for i := range fromAlice {
+		if fromAlice[i].Cmp(fromBob[i]) != 0 {
+			t.Fatalf("value %d: alice got %x, bob got %x", i, fromAlice[i], fromBob[i])
+		}
+	}
+
+	// Replacing either player, or moving the nonce of either, changes the game.
+	differs := func(name string, values []*big.Int) {
+		t.Helper()
+		if values[0].Cmp(fromAlice[0]) == 0 {
+			t.Errorf("%s: values unchanged", name)
+		}
+	}
+	differs("other opponent", mustRunMethod(t, state, alice, "fairGame", carol, big.NewInt(4))[0].([]*big.Int))
+	differs("other player", mustRunMethod(t, state, carol, "fairGame", bob, big.NewInt(4))[0].([]*big.Int))
+	state.state.SetNonce(bob, 9)
+	differs("opponent nonce", mustRunMethod(t, state, alice, "fairGame", bob, big.NewInt(4))[0].([]*big.Int))
+	state.state.SetNonce(bob, 8)
+	state.state.SetNonce(alice, 4)
+	differs("player nonce", mustRunMethod(t, state, alice, "fairGame", bob, big.NewInt(4))[0].([]*big.Int))
+
+	if _, _, err := runMethod(state, alice, "fairGame", alice, big.NewInt(1)); err != errSelfOpponent {
+		t.Errorf("got %v, want %v", err, errSelfOpponent)
+	}
+}
@@ -0,0 +1,85 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+)
+
+// The helpers below implement the real functions needed by continuous distributions in 1e18
+// fixed point on big.Int. Floating point is avoided on purpose: the results are part of the
+// execution output, and math.Log and math.Exp are not guaranteed to round identically on every
+// architecture validators run on.
+
+var (
+	// wad is 1 in 1e18 fixed point.
+	wad = big.NewInt(1e18)
+	// wadLn2 is ln(2) in 1e18 fixed point.
+	wadLn2 = big.NewInt(693_147_180_559_945_309)
+	// wadMinExp is the argument below which wadExp underflows to zero.
+	wadMinExp = new(big.Int).Mul(big.NewInt(-42), wad)
+)
+
+// wadMul returns a*b in fixed point, truncated toward zero.
+func wadMul(a *big.Int, b *big.Int) *big.Int {
+	v := new(big.Int).Mul(a, b)
+	return v.Quo(v, wad)
+}
+
+// wadDiv returns a/b in fixed point, truncated toward zero. [b] must be non-zero.
+func wadDiv(a *big.Int, b *big.Int) *big.Int {
+	v := new(big.Int).Mul(a, wad)
+	return v.Quo(v, b)
+}
+
+// wadSqrt returns the square root of the non-negative [x] in fixed point, truncated.
+func wadSqrt(x *big.Int) *big.Int {
+	v := new(big.Int).Mul(x, wad)
+	return v.Sqrt(v)
+}
+
+// wadLn returns the natural logarithm of the positive [x] in fixed point. [x] is scaled by a
+// power of two into m in [1, 2), so ln(x) = k*ln(2) + ln(m), and ln(m) is summed as
+// 2*atanh(z) = 2*(z + z^3/3 + z^5/5 + ...) with z = (m-1)/(m+1) <= 1/3.
+func wadLn(x *big.Int) *big.Int {
+	m, k := new(big.Int).Set(x), int64(0)
+	two := new(big.Int).Lsh(wad, 1)
+	for m.Cmp(two) >= 0 {
+		m.Rsh(m, 1)
+		k++
+	}
+	for m.Cmp(wad) < 0 {
+		m.Lsh(m, 1)
+		k--
+	}
+
+	z := wadDiv(new(big.Int).Sub(m, wad), new(big.Int).Add(m, wad))
+	z2 := wadMul(z, z)
+	sum := new(big.Int)
+	for i, term := int64(1), z; term.Sign() != 0; i += 2 {
+		sum.Add(sum, new(big.Int).Quo(term, big.NewInt(i)))
+		term = wadMul(term, z2)
+	}
+	sum.Lsh(sum, 1)
+	return sum.Add(sum, new(big.Int).Mul(big.NewInt(k), wadLn2))
+}
+
+// wadExp returns e^x in fixed point for [x] <= 0, which is all the callers need. [x] is split
+// into k*ln(2) + r with -ln(2) < r <= 0, so e^x = e^r / 2^-k, and e^r is summed as a Taylor
+// series.
+func wadExp(x *big.Int) *big.Int {
+	if x.Cmp(wadMinExp) < 0 {
+		return new(big.Int)
+	}
+	k := new(big.Int).Quo(x, wadLn2)
+	r := new(big.Int).Sub(x, new(big.Int).Mul(k, wadLn2))
+
+	sum, term := new(big.Int).Set(wad), new(big.Int).Set(wad)
+	for i := int64(1); term.Sign() != 0; i++ {
+		term = wadMul(term, r)
+		term.Quo(term, big.NewInt(i))
+		sum.Add(sum, term)
+	}
+	return sum.Rsh(sum, uint(-k.Int64()))
+}
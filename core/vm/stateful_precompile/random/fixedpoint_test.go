@@ -0,0 +1,37 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+// wadFloat converts a fixed point value to a float64 for comparisons in tests.
+func wadFloat(x *big.Int) float64 {
+	f, _ := new(big.Float).Quo(new(big.Float).SetInt(x), new(big.Float).SetInt(wad)).Float64()
+	return f
+}
+
+func floatWad(f float64) *big.Int {
+	v, _ := new(big.Float).Mul(big.NewFloat(f), new(big.Float).SetInt(wad)).Int(nil)
+	return v
+}
+
+func TestWadLnExp(t *testing.T) {
+	for _, x := range []float64{1e-18, 1e-9, 0.001, 0.5, 1, 1.5, 2, math.E, 10, 12345.678, 1e12} {
+		if got, want := wadFloat(wadLn(floatWad(x))), math.Log(x); math.Abs(got-want) > 1e-12 {
+			t.Errorf("ln(%v) = %v, want %v", x, got, want)
+		}
+	}
+	for _, x := range []float64{0, -1e-9, -0.3, -1, -2.5, -10, -41} {
+		if got, want := wadFloat(wadExp(floatWad(x))), math.Exp(x); math.Abs(got-want) > 1e-12*math.Max(want, 1e-6) {
+			t.Errorf("exp(%v) = %v, want %v", x, got, want)
+		}
+	}
+	if got := wadExp(floatWad(-50)); got.Sign() != 0 {
+		t.Errorf("exp(-50) = %v, want underflow to 0", got)
+	}
+}
@@ -0,0 +1,103 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	GachaPullGasCost = 1024 + contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot
+)
+
+var errZeroHardPity = errors.New("hard pity must be non-zero")
+
+// GachaPullInput is the input of the gachaPull method.
+type GachaPullInput struct {
+	BaseRateBps      *big.Int
+	PityIncrementBps *big.Int
+	HardPity         *big.Int
+}
+
+func PackGachaPullInput(baseRateBps *big.Int, pityIncrementBps *big.Int, hardPity *big.Int) ([]byte, error) {
+	return randomABI.Pack("gachaPull", baseRateBps, pityIncrementBps, hardPity)
+}
+
+func UnpackGachaPullInput(input []byte) (GachaPullInput, error) {
+	var in GachaPullInput
+	if err := unpackInput("gachaPull", input, &in); err != nil {
+		return GachaPullInput{}, err
+	}
+	if !in.BaseRateBps.IsUint64() || in.BaseRateBps.Uint64() > maxBasisPoints {
+		return GachaPullInput{}, errInvalidProbability
+	}
+	if in.HardPity.Sign() == 0 {
+		return GachaPullInput{}, errZeroHardPity
+	}
+	return in, nil
+}
+
+func PackGachaPullOutput(hit bool, missStreak *big.Int) ([]byte, error) {
+	return randomABI.Methods["gachaPull"].Outputs.Pack(hit, missStreak)
+}
+
+// gachaStreakKey returns the slot holding the number of consecutive misses of [caller].
+func gachaStreakKey(caller common.Address) common.Hash {
+	return stateKey("gacha.streak", caller.Bytes())
+}
+
+// gachaRate returns the hit probability in basis points of a pull after [streak] misses:
+// the base rate raised by the pity increment for every miss, capped at certainty.
+func gachaRate(in GachaPullInput, streak *big.Int) uint64 {
+	rate := new(big.Int).Mul(in.PityIncrementBps, streak)
+	rate.Add(rate, in.BaseRateBps)
+	if !rate.IsUint64() || rate.Uint64() > maxBasisPoints {
+		return maxBasisPoints
+	}
+	return rate.Uint64()
+}
+
+// gachaPull performs a pull for [caller] and returns whether it hit and the miss streak after
+// it. The pull completing a streak of hardPity misses always hits, and a hit resets the streak.
+func gachaPull(stream *randomStream, state contract.StateDB, addr common.Address, caller common.Address, in GachaPullInput) (bool, *big.Int) {
+	key := gachaStreakKey(caller)
+	streak := state.GetState(addr, key).Big()
+
+	hit := new(big.Int).Add(streak, common.Big1).Cmp(in.HardPity) >= 0 || stream.bernoulli(gachaRate(in, streak))
+	if hit {
+		streak.SetUint64(0)
+	} else {
+		streak.Add(streak, common.Big1)
+	}
+	state.SetState(addr, key, common.BigToHash(streak))
+	return hit, streak
+}
+
+func GachaPullFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, GachaPullGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+
+	in, err := UnpackGachaPullInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	state := accessibleState.GetStateDB()
+	hit, streak := gachaPull(newCallerStream(addr, caller, state), state, addr, caller, in)
+	ret, err = PackGachaPullOutput(hit, streak)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
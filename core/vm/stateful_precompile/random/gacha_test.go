@@ -0,0 +1,51 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestGachaPull(t *testing.T) {
+	const hardPity = 10
+	state := newMockAccessibleState()
+	baseRate, increment := big.NewInt(100), big.NewInt(50)
+
+	var streak, hits, pityHits uint64
+	for nonce := uint64(0); nonce < 1000; nonce++ {
+		state.state.SetNonce(testCaller, nonce)
+		out := mustRunMethod(t, state, testCaller, "gachaPull", baseRate, increment, big.NewInt(hardPity))
+		hit, got := out[0].(bool), out[1].(*big.Int).Uint64()
+
+		if streak == hardPity-1 {
+			if !hit {
+				t.Fatalf("nonce %d: missed after %d misses despite hard pity %d", nonce, streak, hardPity)
+			}
+			pityHits++
+		}
+		if hit {
+			hits++
+			streak = 0
+		} else {
+			streak++
+		}
+		if got != streak {
+			t.Fatalf("nonce %d: got miss streak %d, want %d", nonce, got, streak)
+		}
+	}
+	if pityHits == 0 || pityHits == hits {
+		t.Fatalf("got %d hits of which %d from hard pity, want both pity and natural hits", hits, pityHits)
+	}
+}
+
+func TestGachaPullInvalidInput(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "gachaPull", big.NewInt(10_001), big.NewInt(0), big.NewInt(1)); err != errInvalidProbability {
+		t.Errorf("base rate: got %v, want %v", err, errInvalidProbability)
+	}
+	if _, _, err := runMethod(state, testCaller, "gachaPull", big.NewInt(100), big.NewInt(0), big.NewInt(0)); err != errZeroHardPity {
+		t.Errorf("hard pity: got %v, want %v", err, errZeroHardPity)
+	}
+}
@@ -0,0 +1,182 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomGraphBaseGas    = 1024
+	RandomGraphPerPairGas = 64
+
+	// RandomCapacityGas is charged for every candidate edge of randomCapacitatedGraph on top
+	// of RandomGraphPerPairGas, to cover the capacity drawn for the edges that are kept.
+	RandomCapacityGas = 64
+
+	// MaxRandomGraphNodes bounds the number of candidate edges, n*(n-1)/2, sampled by randomGraph.
+	MaxRandomGraphNodes = 64
+)
+
+var (
+	errTooManyNodes       = errors.New("too many nodes")
+	errInvalidProbability = errors.New("probability exceeds 10000 basis points")
+	errZeroCapacity       = errors.New("max capacity must be non-zero")
+)
+
+// RandomGraphInput is the input of the randomGraph method.
+type RandomGraphInput struct {
+	Nodes       *big.Int
+	EdgeProbBps *big.Int
+}
+
+// RandomCapacitatedGraphInput is the input of the randomCapacitatedGraph method.
+type RandomCapacitatedGraphInput struct {
+	Nodes       *big.Int
+	EdgeProbBps *big.Int
+	MaxCapacity *big.Int
+}
+
+func PackRandomGraphInput(nodes *big.Int, edgeProbBps *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomGraph", nodes, edgeProbBps)
+}
+
+func UnpackRandomGraphInput(input []byte) (RandomGraphInput, error) {
+	var in RandomGraphInput
+	if err := unpackInput("randomGraph", input, &in); err != nil {
+		return RandomGraphInput{}, err
+	}
+	if !in.Nodes.IsUint64() || in.Nodes.Uint64() > MaxRandomGraphNodes {
+		return RandomGraphInput{}, errTooManyNodes
+	}
+	if !in.EdgeProbBps.IsUint64() || in.EdgeProbBps.Uint64() > maxBasisPoints {
+		return RandomGraphInput{}, errInvalidProbability
+	}
+	return in, nil
+}
+
+func PackRandomGraphOutput(sources []*big.Int, targets []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomGraph"].Outputs.Pack(sources, targets)
+}
+
+func PackRandomCapacitatedGraphInput(nodes *big.Int, edgeProbBps *big.Int, maxCapacity *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomCapacitatedGraph", nodes, edgeProbBps, maxCapacity)
+}
+
+func UnpackRandomCapacitatedGraphInput(input []byte) (RandomCapacitatedGraphInput, error) {
+	var in RandomCapacitatedGraphInput
+	if err := unpackInput("randomCapacitatedGraph", input, &in); err != nil {
+		return RandomCapacitatedGraphInput{}, err
+	}
+	if !in.Nodes.IsUint64() || in.Nodes.Uint64() > MaxRandomGraphNodes {
+		return RandomCapacitatedGraphInput{}, errTooManyNodes
+	}
+	if !in.EdgeProbBps.IsUint64() || in.EdgeProbBps.Uint64() > maxBasisPoints {
+		return RandomCapacitatedGraphInput{}, errInvalidProbability
+	}
+	if in.MaxCapacity.Sign() == 0 {
+		return RandomCapacitatedGraphInput{}, errZeroCapacity
+	}
+	return in, nil
+}
+
+func PackRandomCapacitatedGraphOutput(sources []*big.Int, targets []*big.Int, capacities []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomCapacitatedGraph"].Outputs.Pack(sources, targets, capacities)
+}
+
+// randomGraphGas returns the gas charged for sampling every candidate edge among [nodes] nodes.
+func randomGraphGas(nodes uint64) uint64 {
+	if nodes < 2 {
+		return RandomGraphBaseGas
+	}
+	return RandomGraphBaseGas + nodes*(nodes-1)/2*RandomGraphPerPairGas
+}
+
+// generateRandomGraph samples a G(n, p) Erdős–Rényi graph: every unordered pair {i, j} with
+// i < j is visited in lexicographic order and kept with probability edgeProbBps/10000. Edges
+// are returned as parallel source/target slices with source < target.
+func generateRandomGraph(stream *randomStream, nodes uint64, edgeProbBps uint64) ([]*big.Int, []*big.Int) {
+	sources, targets := []*big.Int{}, []*big.Int{}
+	for i := uint64(0); i < nodes; i++ {
+		for j := i + 1; j < nodes; j++ {
+			if stream.bernoulli(edgeProbBps) {
+				sources = append(sources, new(big.Int).SetUint64(i))
+				targets = append(targets, new(big.Int).SetUint64(j))
+			}
+		}
+	}
+	return sources, targets
+}
+
+// randomCapacitatedGraphGas returns the gas charged for sampling every ordered pair among
+// [nodes] nodes together with its capacity.
+func randomCapacitatedGraphGas(nodes uint64) uint64 {
+	if nodes < 2 {
+		return RandomGraphBaseGas
+	}
+	return RandomGraphBaseGas + nodes*(nodes-1)*(RandomGraphPerPairGas+RandomCapacityGas)
+}
+
+// generateRandomCapacitatedGraph samples a directed G(n, p) graph: every ordered pair (i, j)
+// with i != j is visited in lexicographic order and kept with probability edgeProbBps/10000.
+// Every kept edge is given a capacity drawn uniformly in [1, maxCapacity] right after it is
+// sampled.
+func generateRandomCapacitatedGraph(stream *randomStream, nodes uint64, edgeProbBps uint64, maxCapacity *big.Int) ([]*big.Int, []*big.Int, []*big.Int) {
+	sources, targets, capacities := []*big.Int{}, []*big.Int{}, []*big.Int{}
+	for i := uint64(0); i < nodes; i++ {
+		for j := uint64(0); j < nodes; j++ {
+			if i == j || !stream.bernoulli(edgeProbBps) {
+				continue
+			}
+			sources = append(sources, new(big.Int).SetUint64(i))
+			targets = append(targets, new(big.Int).SetUint64(j))
+			capacities = append(capacities, new(big.Int).Add(stream.uniform(maxCapacity), common.Big1))
+		}
+	}
+	return sources, targets, capacities
+}
+
+func RandomGraphFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackRandomGraphInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	nodes := in.Nodes.Uint64()
+	if remainingGas, err = contract.DeductGas(suppliedGas, randomGraphGas(nodes)); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	sources, targets := generateRandomGraph(stream, nodes, in.EdgeProbBps.Uint64())
+	ret, err = PackRandomGraphOutput(sources, targets)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
+
+func RandomCapacitatedGraphFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackRandomCapacitatedGraphInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	nodes := in.Nodes.Uint64()
+	if remainingGas, err = contract.DeductGas(suppliedGas, randomCapacitatedGraphGas(nodes)); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	sources, targets, capacities := generateRandomCapacitatedGraph(stream, nodes, in.EdgeProbBps.Uint64(), in.MaxCapacity)
+	ret, err = PackRandomCapacitatedGraphOutput(sources, targets, capacities)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,97 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRandomGraphEdgeCount(t *testing.T) {
+	const (
+		nodes  = 64
+		bps    = 3000
+		rounds = 10
+	)
+	state := newMockAccessibleState()
+	pairs := nodes * (nodes - 1) / 2
+
+	total := 0
+	for nonce := uint64(0); nonce < rounds; nonce++ {
+		state.state.SetNonce(testCaller, nonce)
+		out := mustRunMethod(t, state, testCaller, "randomGraph", big.NewInt(nodes), big.NewInt(bps))
+		sources, targets := out[0].([]*big.Int), out[1].([]*big.Int)
+		if len(sources) != len(targets) {
+			t.Fatalf("mismatched edge slices: %d sources, %d targets", len(sources), len(targets))
+		}
+		for i := range sources {
+			if sources[i].Cmp(targets[i]) >= 0 || targets[i].Int64() >= nodes {
+				t.Fatalf("invalid edge (%v, %v)", sources[i], targets[i])
+			}
+		}
+		total += len(sources)
+	}
+	// The expected count is 6048 with a standard deviation of about 65.
+	expected := rounds * pairs * bps / maxBasisPoints
+	if diff := total - expected; diff < -400 || diff > 400 {
+		t.Errorf("got %d edges over %d rounds, expected about %d", total, rounds, expected)
+	}
+}
+
+func TestRandomGraphLimits(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "randomGraph", big.NewInt(MaxRandomGraphNodes+1), big.NewInt(1)); err != errTooManyNodes {
+		t.Errorf("got %v, want %v", err, errTooManyNodes)
+	}
+	if _, _, err := runMethod(state, testCaller, "randomGraph", big.NewInt(4), big.NewInt(maxBasisPoints+1)); err != errInvalidProbability {
+		t.Errorf("got %v, want %v", err, errInvalidProbability)
+	}
+}
+
+func TestRandomCapacitatedGraph(t *testing.T) {
+	const (
+		nodes       = 32
+		bps         = 2500
+		maxCapacity = 7
+		rounds      = 10
+	)
+	state := newMockAccessibleState()
+	pairs := nodes * (nodes - 1)
+
+	total := 0
+	seen := make(map[int64]bool)
+	for nonce := uint64(0); nonce < rounds; nonce++ {
+		state.state.SetNonce(testCaller, nonce)
+		out := mustRunMethod(t, state, testCaller, "randomCapacitatedGraph", big.NewInt(nodes), big.NewInt(bps), big.NewInt(maxCapacity))
+		sources, targets, capacities := out[0].([]*big.Int), out[1].([]*big.Int), out[2].([]*big.Int)
+		if len(sources) != len(targets) || len(sources) != len(capacities) {
+			t.Fatalf("mismatched edge slices: %d sources, %d targets, %d capacities", len(sources), len(targets), len(capacities))
+		}
+		for i := range sources {
+			if sources[i].Cmp(targets[i]) == 0 || sources[i].Int64() >= nodes || targets[i].Int64() >= nodes {
+				t.Fatalf("invalid edge (%v, %v)", sources[i], targets[i])
+			}
+			if c := capacities[i].Int64(); c < 1 || c > maxCapacity {
+				t.Fatalf("capacity %d out of range [1, %d]", c, maxCapacity)
+			}
+			seen[capacities[i].Int64()] = true
+		}
+		total += len(sources)
+	}
+	// The expected count is 2480 with a standard deviation of about 43.
+	expected := rounds * pairs * bps / maxBasisPoints
+	if diff := total - expected; diff < -250 || diff > 250 {
+		t.Errorf("got %d edges over %d rounds, expected about %d", total, rounds, expected)
+	}
+	if len(seen) != maxCapacity {
+		t.Errorf("got %d distinct capacities, want %d", len(seen), maxCapacity)
+	}
+}
+
+func TestRandomCapacitatedGraphZeroCapacity(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "randomCapacitatedGraph", big.NewInt(4), big.NewInt(1), new(big.Int)); err != errZeroCapacity {
+		t.Errorf("got %v, want %v", err, errZeroCapacity)
+	}
+}
@@ -0,0 +1,98 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/holiman/uint256"
+)
+
+const (
+	RandomInRangeBaseGas = 1024
+	// RandomInRangePerValueGas covers the expected rejections, fewer than one per accepted draw.
+	RandomInRangePerValueGas = 2 * RandomPerValueGas
+)
+
+var errEmptyRange = errors.New("max must be greater than min")
+
+// RandomInRangeInput is the input of the randomInRange method.
+type RandomInRangeInput struct {
+	Min *big.Int
+	Max *big.Int
+	N   *big.Int
+}
+
+func PackRandomInRangeInput(min *big.Int, max *big.Int, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomInRange", min, max, n)
+}
+
+func UnpackRandomInRangeInput(input []byte) (RandomInRangeInput, error) {
+	var in RandomInRangeInput
+	if err := unpackInput("randomInRange", input, &in); err != nil {
+		return RandomInRangeInput{}, err
+	}
+	if in.Max.Cmp(in.Min) <= 0 {
+		return RandomInRangeInput{}, errEmptyRange
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return RandomInRangeInput{}, errTooManyValues
+	}
+	return in, nil
+}
+
+func PackRandomInRangeOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomInRange"].Outputs.Pack(randomValues)
+}
+
+// generateRandomInRange draws [n] values uniformly from [min, max). Every value is scaled
+// from the words of [stream] with randomStream.scaleTo, so no value of the range is favoured
+// and ranges up to 2^256 - 1 wide are scaled without overflow.
+func generateRandomInRange(stream *randomStream, min *big.Int, max *big.Int, n uint64) []*big.Int {
+	width, _ := uint256.FromBig(new(big.Int).Sub(max, min))
+	values := make([]*big.Int, n)
+	for i := range values {
+		values[i] = stream.scaleTo(width).ToBig()
+		values[i].Add(values[i], min)
+	}
+	return values
+}
+
+// newRandomInRangeFunc returns the randomInRange handler of a precompile built with [o]. It
+// draws from the same stream as randomNCSPRNG for the caller.
+func newRandomInRangeFunc(o options) contract.RunStatefulPrecompileFunc {
+	return func(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		in, err := UnpackRandomInRangeInput(input)
+		if err != nil {
+			return nil, suppliedGas, err
+		}
+		n := in.N.Uint64()
+		if remainingGas, err = contract.DeductGas(suppliedGas, RandomInRangeBaseGas+n*RandomInRangePerValueGas); err != nil {
+			return nil, 0, err
+		}
+
+		state := accessibleState.GetStateDB()
+		blockCtx := accessibleState.GetBlockContext()
+		blockNumber := blockCtx.BlockNumber.Uint64()
+		stream, sources, err := newRandomNCSPRNGStream(addr, caller, blockCtx, o, state)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		if sources&EntropySourceFallback != 0 {
+			if remainingGas, err = reportEntropyFallback(state, addr, caller, blockNumber, readOnly, remainingGas); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		ret, err = PackRandomInRangeOutput(generateRandomInRange(stream, in.Min, in.Max, n))
+		if err != nil {
+			return nil, remainingGas, err
+		}
+
+		return ret, remainingGas, nil
+	}
+}
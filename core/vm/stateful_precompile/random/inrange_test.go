@@ -0,0 +1,62 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+func TestRandomInRange(t *testing.T) {
+	state := newMockAccessibleState()
+	// Half of 2^256 plus one is the width with the most rejections, close to one per word.
+	worst := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(1))
+	for _, tt := range []struct{ min, max *big.Int }{
+		{big.NewInt(1), big.NewInt(7)},
+		{big.NewInt(100), big.NewInt(101)},
+		{big.NewInt(0), math.MaxBig256},
+		{big.NewInt(5), new(big.Int).Add(worst, big.NewInt(5))},
+	} {
+		values := mustRunMethod(t, state, testCaller, "randomInRange", tt.min, tt.max, big.NewInt(MaxRandomValues))[0].([]*big.Int)
+		if len(values) != MaxRandomValues {
+			t.Fatalf("[%v, %v): got %d values", tt.min, tt.max, len(values))
+		}
+		for _, v := range values {
+			if v.Cmp(tt.min) < 0 || v.Cmp(tt.max) >= 0 {
+				t.Fatalf("value %v out of range [%v, %v)", v, tt.min, tt.max)
+			}
+		}
+	}
+
+	// Every value of a small range is hit.
+	seen := make(map[int64]bool)
+	for _, v := range mustRunMethod(t, state, testCaller, "randomInRange", big.NewInt(1), big.NewInt(7), big.NewInt(100))[0].([]*big.Int) {
+		seen[v.Int64()] = true
+	}
+	if len(seen) != 6 {
+		t.Errorf("100 draws from [1, 7) hit %d distinct values, want 6", len(seen))
+	}
+
+	// The rejection loop terminates quickly even for the worst width.
+	stream := newCallerStream(randomNCSPRNGContractAddr, testCaller, state.state)
+	var rejections uint64
+	for i := 0; i < 1000; i++ {
+		_, r := stream.uniformWithRejections(worst)
+		rejections += r
+	}
+	if rejections > 1200 {
+		t.Errorf("1000 draws needed %d rejections, want about 1000", rejections)
+	}
+}
+
+func TestRandomInRangeEmpty(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, max := range []int64{5, 4} {
+		if _, _, err := runMethod(state, testCaller, "randomInRange", big.NewInt(5), big.NewInt(max), big.NewInt(1)); err != errEmptyRange {
+			t.Errorf("[5, %d): got %v, want %v", max, err, errEmptyRange)
+		}
+	}
+}
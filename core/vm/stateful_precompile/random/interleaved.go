@@ -0,0 +1,89 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	InterleavedStreamsBaseGas = 1024
+	// InterleavedStreamsPerStreamGas covers deriving the user seed of one sub-stream.
+	InterleavedStreamsPerStreamGas = 64
+)
+
+// InterleavedStreamsInput is the input of the interleavedStreams method.
+type InterleavedStreamsInput struct {
+	NumStreams *big.Int
+	PerStream  *big.Int
+}
+
+func PackInterleavedStreamsInput(numStreams *big.Int, perStream *big.Int) ([]byte, error) {
+	return randomABI.Pack("interleavedStreams", numStreams, perStream)
+}
+
+// UnpackInterleavedStreamsInput returns the number of streams and the number of values per
+// stream, whose product is at most MaxRandomValues.
+func UnpackInterleavedStreamsInput(input []byte) (uint64, uint64, error) {
+	var in InterleavedStreamsInput
+	if err := unpackInput("interleavedStreams", input, &in); err != nil {
+		return 0, 0, err
+	}
+	if !in.NumStreams.IsUint64() || !in.PerStream.IsUint64() {
+		return 0, 0, errTooManyValues
+	}
+	numStreams, perStream := in.NumStreams.Uint64(), in.PerStream.Uint64()
+	if numStreams > MaxRandomValues || perStream > MaxRandomValues || numStreams*perStream > MaxRandomValues {
+		return 0, 0, errTooManyValues
+	}
+	return numStreams, perStream, nil
+}
+
+func PackInterleavedStreamsOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["interleavedStreams"].Outputs.Pack(randomValues)
+}
+
+// interleavedStreamDomain returns the domain label of sub-stream [i] of interleavedStreams.
+func interleavedStreamDomain(i uint64) string {
+	return "interleavedStreams." + strconv.FormatUint(i, 10)
+}
+
+// generateInterleavedStreams draws [perStream] values from each of [numStreams] sub-streams of
+// [stream], sub-stream i being separated by interleavedStreamDomain(i), and interleaves them:
+// value j of sub-stream i is at index j*numStreams + i. Sub-streams are independent, so adding
+// streams never changes the values of the existing ones.
+func generateInterleavedStreams(stream *randomStream, numStreams uint64, perStream uint64) []*big.Int {
+	values := make([]*big.Int, numStreams*perStream)
+	for i := uint64(0); i < numStreams; i++ {
+		for j, v := range stream.withDomain(interleavedStreamDomain(i)).values(perStream) {
+			values[uint64(j)*numStreams+i] = v
+		}
+	}
+	return values
+}
+
+// InterleavedStreamsFunc returns [perStream] values of each of [numStreams] independent
+// sub-streams of the caller, interleaved stream by stream, e.g. the red, green and blue
+// channels of random pixels.
+func InterleavedStreamsFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	numStreams, perStream, err := UnpackInterleavedStreamsInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, InterleavedStreamsBaseGas+numStreams*InterleavedStreamsPerStreamGas+numStreams*perStream*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackInterleavedStreamsOutput(generateInterleavedStreams(stream, numStreams, perStream))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,54 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestInterleavedStreams(t *testing.T) {
+	state := newMockAccessibleState()
+	const numStreams, perStream = 3, 8
+	values := mustRunMethod(t, state, testCaller, "interleavedStreams", big.NewInt(numStreams), big.NewInt(perStream))[0].([]*big.Int)
+	if len(values) != numStreams*perStream {
+		t.Fatalf("got %d values, want %d", len(values), numStreams*perStream)
+	}
+
+	seen := make(map[string]bool)
+	for i := uint64(0); i < numStreams; i++ {
+		// Every de-interleaved stream is the one drawn on its own in its domain.
+		want := newCallerStream(randomNCSPRNGContractAddr, testCaller, state.GetStateDB()).withDomain(interleavedStreamDomain(i)).values(perStream)
+		for j := range want {
+			got := values[j*numStreams+int(i)]
+			if got.Cmp(want[j]) != 0 {
+				t.Fatalf("stream %d value %d: got %x, want %x", i, j, got, want[j])
+			}
+			if seen[got.String()] {
+				t.Fatalf("stream %d value %d repeats another value", i, j)
+			}
+			seen[got.String()] = true
+		}
+	}
+
+	// A single stream is the first stream of any interleaving.
+	single := mustRunMethod(t, state, testCaller, "interleavedStreams", big.NewInt(1), big.NewInt(perStream))[0].([]*big.Int)
+	for j := range single {
+		if single[j].Cmp(values[j*numStreams]) != 0 {
+			t.Fatalf("value %d of the single stream differs from stream 0", j)
+		}
+	}
+}
+
+func TestInterleavedStreamsInvalid(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, tt := range [][2]int64{{MaxRandomValues + 1, 0}, {0, MaxRandomValues + 1}, {2, MaxRandomValues/2 + 1}} {
+		if _, _, err := runMethod(state, testCaller, "interleavedStreams", big.NewInt(tt[0]), big.NewInt(tt[1])); err != errTooManyValues {
+			t.Errorf("%d streams of %d: got %v, want %v", tt[0], tt[1], err, errTooManyValues)
+		}
+	}
+	if values := mustRunMethod(t, state, testCaller, "interleavedStreams", big.NewInt(0), big.NewInt(5))[0].([]*big.Int); len(values) != 0 {
+		t.Errorf("got %d values for no streams", len(values))
+	}
+}
@@ -0,0 +1,80 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	FeeJitterGasCost = 1024
+)
+
+var errJitterOverflow = errors.New("baseFee plus the maximum jitter overflows uint256")
+
+// FeeJitterInput is the input of the feeJitter method.
+type FeeJitterInput struct {
+	BaseFee      *big.Int
+	MaxJitterBps *big.Int
+}
+
+func PackFeeJitterInput(baseFee *big.Int, maxJitterBps *big.Int) ([]byte, error) {
+	return randomABI.Pack("feeJitter", baseFee, maxJitterBps)
+}
+
+func UnpackFeeJitterInput(input []byte) (FeeJitterInput, error) {
+	var in FeeJitterInput
+	if err := unpackInput("feeJitter", input, &in); err != nil {
+		return FeeJitterInput{}, err
+	}
+	if _, high := feeJitterBand(in.BaseFee, in.MaxJitterBps); high.Cmp(math.MaxBig256) > 0 {
+		return FeeJitterInput{}, errJitterOverflow
+	}
+	return in, nil
+}
+
+func PackFeeJitterOutput(fee *big.Int) ([]byte, error) {
+	return randomABI.Methods["feeJitter"].Outputs.Pack(fee)
+}
+
+// feeJitterBand returns the band [low, high] a jittered [baseFee] is drawn from: baseFee
+// plus or minus baseFee * maxJitterBps / 10000. When the jitter exceeds the fee, which
+// happens once maxJitterBps is above 10000, the band is cut at zero instead of underflowing.
+func feeJitterBand(baseFee *big.Int, maxJitterBps *big.Int) (*big.Int, *big.Int) {
+	jitter := new(big.Int).Mul(baseFee, maxJitterBps)
+	jitter.Quo(jitter, big.NewInt(maxBasisPoints))
+
+	low := new(big.Int).Sub(baseFee, jitter)
+	if low.Sign() < 0 {
+		low.SetUint64(0)
+	}
+	return low, jitter.Add(jitter, baseFee)
+}
+
+func FeeJitterFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, FeeJitterGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	in, err := UnpackFeeJitterInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	// The jitter is drawn in wei rather than in whole basis points, so small fees still get
+	// every value of the band.
+	low, high := feeJitterBand(in.BaseFee, in.MaxJitterBps)
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackFeeJitterOutput(uniformInRange(stream, low, high))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,39 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+func TestFeeJitter(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, tt := range []struct {
+		baseFee, maxJitterBps int64
+		low, high             int64
+	}{
+		{1_000_000, 500, 950_000, 1_050_000},
+		{3, 2_500, 3, 3},
+		{10, 20_000, 0, 30},
+		{0, 1_000, 0, 0},
+	} {
+		for nonce := uint64(0); nonce < 100; nonce++ {
+			state.state.SetNonce(testCaller, nonce)
+			fee := mustRunMethod(t, state, testCaller, "feeJitter", big.NewInt(tt.baseFee), big.NewInt(tt.maxJitterBps))[0].(*big.Int)
+			if fee.Cmp(big.NewInt(tt.low)) < 0 || fee.Cmp(big.NewInt(tt.high)) > 0 {
+				t.Fatalf("feeJitter(%d, %d) = %v, want within [%d, %d]", tt.baseFee, tt.maxJitterBps, fee, tt.low, tt.high)
+			}
+		}
+	}
+}
+
+func TestFeeJitterOverflow(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "feeJitter", math.MaxBig256, big.NewInt(1)); err != errJitterOverflow {
+		t.Errorf("got %v, want %v", err, errJitterOverflow)
+	}
+}
@@ -0,0 +1,89 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	RandomWithLineageBaseGas = 1024
+)
+
+func PackRandomWithLineageInput(n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomWithLineage", n)
+}
+
+func UnpackRandomWithLineageInput(input []byte) (uint64, error) {
+	var n *big.Int
+	if err := unpackInput("randomWithLineage", input, &n); err != nil {
+		return 0, err
+	}
+	if !n.IsUint64() || n.Uint64() > MaxRandomValues {
+		return 0, errTooManyValues
+	}
+	return n.Uint64(), nil
+}
+
+func PackRandomWithLineageOutput(randomValues []*big.Int, seedLineage common.Hash) ([]byte, error) {
+	return randomABI.Methods["randomWithLineage"].Outputs.Pack(randomValues, [32]byte(seedLineage))
+}
+
+// SeedLineage returns the lineage of the draw described by [w] in a block with PREVRANDAO
+// [prevRandao], the zero hash on chains without one: the keccak hash of, in order, the
+// fingerprint keccak(ServerSeed) of its server seed, its user seed, its nonce as a 32 byte
+// big-endian word and [prevRandao]. The fingerprint keeps a secret server seed secret while
+// still telling draws keyed by different seeds apart, so auditors can group draws by lineage
+// and trace an incident back to the input that changed.
+func SeedLineage(w RandomnessWitness, prevRandao common.Hash) common.Hash {
+	key := w.ServerSeed.Bytes()
+	return crypto.Keccak256Hash(
+		crypto.Keccak256(key),
+		saltedUserSeed(userSeed(key, w.Caller), w.Salt),
+		common.BigToHash(new(big.Int).SetUint64(w.Nonce)).Bytes(),
+		prevRandao.Bytes(),
+	)
+}
+
+// newRandomWithLineageFunc returns the randomWithLineage handler of a precompile built with
+// [o]. It returns the same values as randomNCSPRNG along with their SeedLineage.
+func newRandomWithLineageFunc(o options) contract.RunStatefulPrecompileFunc {
+	return func(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		n, err := UnpackRandomWithLineageInput(input)
+		if err != nil {
+			return nil, suppliedGas, err
+		}
+		if remainingGas, err = contract.DeductGas(suppliedGas, RandomWithLineageBaseGas+n*RandomPerValueGas); err != nil {
+			return nil, 0, err
+		}
+
+		state := accessibleState.GetStateDB()
+		blockCtx := accessibleState.GetBlockContext()
+		stream, sources, err := newRandomNCSPRNGStream(addr, caller, blockCtx, o, state)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		if sources&EntropySourceFallback != 0 {
+			if remainingGas, err = reportEntropyFallback(state, addr, caller, blockCtx.BlockNumber.Uint64(), readOnly, remainingGas); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		var prevRandao common.Hash
+		if blockCtx.Random != nil {
+			prevRandao = *blockCtx.Random
+		}
+		lineage := SeedLineage(newRandomnessWitness(state, addr, caller, blockCtx, n, o), prevRandao)
+		ret, err = PackRandomWithLineageOutput(stream.values(n), lineage)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+
+		return ret, remainingGas, nil
+	}
+}
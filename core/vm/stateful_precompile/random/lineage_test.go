@@ -0,0 +1,66 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func runRandomWithLineage(t *testing.T, state *mockAccessibleState, caller common.Address, opts ...Option) ([]*big.Int, common.Hash) {
+	t.Helper()
+	input, err := PackRandomWithLineageInput(big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret, _, err := CreateRandomNCSPRNGPrecompile(DefaultConfig(), opts...).Run(state, caller, randomNCSPRNGContractAddr, input, testGas, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := randomABI.Methods["randomWithLineage"].Outputs.Unpack(ret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out[0].([]*big.Int), out[1].([32]byte)
+}
+
+func TestRandomWithLineage(t *testing.T) {
+	state := newMockAccessibleState()
+	values, lineage := runRandomWithLineage(t, state, testCaller)
+	if plain := runRandomNCSPRNG(t, state, 2, true); values[0].Cmp(plain[0]) != 0 {
+		t.Fatal("values differ from randomNCSPRNG")
+	}
+	if _, again := runRandomWithLineage(t, state, testCaller); again != lineage {
+		t.Fatal("lineage changed with unchanged inputs")
+	}
+	// The block number is not a seed input, so it leaves the lineage alone.
+	state.blockCtx.BlockNumber = big.NewInt(2)
+	if _, again := runRandomWithLineage(t, state, testCaller); again != lineage {
+		t.Fatal("lineage changed with the block number alone")
+	}
+
+	seen := map[common.Hash]string{lineage: "base"}
+	check := func(input string, got common.Hash) {
+		if prev, ok := seen[got]; ok {
+			t.Errorf("changing the %s left the lineage of the %s", input, prev)
+		}
+		seen[got] = input
+	}
+
+	_, got := runRandomWithLineage(t, state, testCaller, WithServerSeed(common.HexToHash("0x5eed")))
+	check("server seed", got)
+	_, got = runRandomWithLineage(t, state, common.HexToAddress("0x07e4"))
+	check("user seed", got)
+
+	state.state.SetNonce(testCaller, 1)
+	_, got = runRandomWithLineage(t, state, testCaller)
+	check("nonce", got)
+
+	random := common.HexToHash("0x7a0d")
+	state.blockCtx.Random = &random
+	_, got = runRandomWithLineage(t, state, testCaller)
+	check("randao", got)
+}
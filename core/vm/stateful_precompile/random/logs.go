@@ -0,0 +1,90 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	RandomFromLogsBaseGas = 1024
+	// RandomFromLogsPerWordGas is charged per 32 byte word of log material hashed into the
+	// seed, matching the KECCAK256 opcode.
+	RandomFromLogsPerWordGas = 6
+)
+
+// RandomFromLogsInput is the input of the randomFromLogs method.
+type RandomFromLogsInput struct {
+	N *big.Int
+}
+
+func PackRandomFromLogsInput(n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomFromLogs", n)
+}
+
+func UnpackRandomFromLogsInput(input []byte) (uint64, error) {
+	var in RandomFromLogsInput
+	if err := unpackInput("randomFromLogs", input, &in); err != nil {
+		return 0, err
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return 0, errTooManyValues
+	}
+	return in.N.Uint64(), nil
+}
+
+func PackRandomFromLogsOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomFromLogs"].Outputs.Pack(randomValues)
+}
+
+// logsDigest hashes every log accumulated so far, in emission order. Each log contributes its
+// topics followed by the 32 byte length of its data and the data itself, so logs cannot be
+// re-split into a different sequence hashing to the same digest. It also returns the number
+// of 32 byte words hashed.
+//
+// The digest depends on every log emitted earlier in the transaction and on their exact
+// order, so any change to an earlier call, including in another contract, changes the draw.
+// All nodes see the same logs for the same transaction, so it is still consensus-safe, but
+// contracts emitting logs before the call can steer the output by choosing what to emit.
+func logsDigest(topics [][]common.Hash, data [][]byte) (common.Hash, uint64) {
+	hasher := crypto.NewKeccakState()
+	words := uint64(0)
+	for i := range topics {
+		for _, topic := range topics[i] {
+			hasher.Write(topic.Bytes())
+		}
+		hasher.Write(common.BigToHash(big.NewInt(int64(len(data[i])))).Bytes())
+		hasher.Write(data[i])
+		words += uint64(len(topics[i])) + 1 + (uint64(len(data[i]))+31)/32
+	}
+	var digest common.Hash
+	hasher.Read(digest[:])
+	return digest, words
+}
+
+func RandomFromLogsFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	n, err := UnpackRandomFromLogsInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+
+	state := accessibleState.GetStateDB()
+	digest, words := logsDigest(state.GetLogData())
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomFromLogsBaseGas+n*RandomPerValueGas+words*RandomFromLogsPerWordGas); err != nil {
+		return nil, 0, err
+	}
+
+	key := serverSeed(addr)
+	stream := newRandomStream(key, crypto.Keccak256(userSeed(key, caller), digest.Bytes()), state.GetNonce(caller))
+	ret, err = PackRandomFromLogsOutput(stream.values(n))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
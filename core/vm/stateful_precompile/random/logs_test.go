@@ -0,0 +1,50 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRandomFromLogs(t *testing.T) {
+	state := newMockAccessibleState()
+	draw := func() *big.Int {
+		return mustRunMethod(t, state, testCaller, "randomFromLogs", big.NewInt(1))[0].([]*big.Int)[0]
+	}
+
+	empty := draw()
+	if again := draw(); again.Cmp(empty) != 0 {
+		t.Fatalf("same logs produced different output")
+	}
+
+	topicA, topicB := common.HexToHash("0xa"), common.HexToHash("0xb")
+	state.state.AddLog(randomNCSPRNGContractAddr, []common.Hash{topicA}, []byte{1, 2, 3}, 1)
+	oneLog := draw()
+	if oneLog.Cmp(empty) == 0 {
+		t.Fatalf("adding a log did not change the output")
+	}
+
+	state.state.AddLog(randomNCSPRNGContractAddr, []common.Hash{topicB}, nil, 1)
+	twoLogs := draw()
+	if twoLogs.Cmp(oneLog) == 0 {
+		t.Fatalf("adding a second log did not change the output")
+	}
+
+	// The same logs emitted in the opposite order give a different draw.
+	state.state.logTopics = [][]common.Hash{{topicB}, {topicA}}
+	state.state.logData = [][]byte{nil, {1, 2, 3}}
+	if swapped := draw(); swapped.Cmp(twoLogs) == 0 {
+		t.Fatalf("log order did not change the output")
+	}
+
+	// Moving bytes between the data of adjacent logs changes the draw too.
+	state.state.logTopics = [][]common.Hash{{topicA}, {topicB}}
+	state.state.logData = [][]byte{{1, 2}, {3}}
+	if resplit := draw(); resplit.Cmp(twoLogs) == 0 {
+		t.Fatalf("re-split log data did not change the output")
+	}
+}
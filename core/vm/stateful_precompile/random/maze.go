@@ -0,0 +1,144 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomMazeBaseGas    = 1024
+	RandomMazePerCellGas = 64
+
+	// MaxMazeCells bounds the number of cells, width * height, of a maze.
+	MaxMazeCells = 1024
+)
+
+var errInvalidMazeSize = errors.New("maze must have non-zero dimensions and at most MaxMazeCells cells")
+
+// RandomMazeInput is the input of the randomMaze method.
+type RandomMazeInput struct {
+	Width  *big.Int
+	Height *big.Int
+}
+
+func PackRandomMazeInput(width *big.Int, height *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomMaze", width, height)
+}
+
+func UnpackRandomMazeInput(input []byte) (uint64, uint64, error) {
+	var in RandomMazeInput
+	if err := unpackInput("randomMaze", input, &in); err != nil {
+		return 0, 0, err
+	}
+	if in.Width.Sign() == 0 || in.Height.Sign() == 0 || !in.Width.IsUint64() || !in.Height.IsUint64() {
+		return 0, 0, errInvalidMazeSize
+	}
+	width, height := in.Width.Uint64(), in.Height.Uint64()
+	if width > MaxMazeCells || height > MaxMazeCells/width {
+		return 0, 0, errInvalidMazeSize
+	}
+	return width, height, nil
+}
+
+func PackRandomMazeOutput(walls []byte) ([]byte, error) {
+	return randomABI.Methods["randomMaze"].Outputs.Pack(walls)
+}
+
+// Cells are numbered row by row, cell i being at row i / width and column i % width. The wall
+// bitmap holds two bits per cell, least significant bit first within every byte: bit 2*i is
+// set when cell i is walled off from its east neighbour and bit 2*i+1 when it is walled off
+// from its south neighbour. The bits of walls on the outer boundary are always set.
+
+// MazeWall reports whether the maze [walls] separates [cell] from its
+// south neighbour, if [south] is set, or from its east neighbour otherwise.
+func MazeWall(walls []byte, cell uint64, south bool) bool {
+	bit := 2 * cell
+	if south {
+		bit++
+	}
+	return walls[bit/8]&(1<<(bit%8)) != 0
+}
+
+func clearMazeWall(walls []byte, cell uint64, south bool) {
+	bit := 2 * cell
+	if south {
+		bit++
+	}
+	walls[bit/8] &^= 1 << (bit % 8)
+}
+
+// mazePassage is a wall that can be opened from a cell: the one towards [neighbour], stored
+// with [wallCell], the cell to the north or west of it.
+type mazePassage struct {
+	neighbour uint64
+	wallCell  uint64
+	south     bool
+}
+
+// generateRandomMaze carves a perfect maze, in which every cell is reachable from every other
+// by exactly one path, with a randomized depth-first search from cell 0: from the cell on top
+// of the stack, a uniformly drawn unvisited neighbour is opened and pushed, and cells without
+// unvisited neighbours are popped. Neighbours are listed north, east, south, west.
+func generateRandomMaze(stream *randomStream, width uint64, height uint64) []byte {
+	cells := width * height
+	walls := make([]byte, (2*cells+7)/8)
+	for i := range walls {
+		walls[i] = 0xff
+	}
+
+	visited := make([]bool, cells)
+	visited[0] = true
+	stack := []uint64{0}
+	for len(stack) > 0 {
+		cell := stack[len(stack)-1]
+		row, col := cell/width, cell%width
+
+		var next []mazePassage
+		if row > 0 && !visited[cell-width] {
+			next = append(next, mazePassage{cell - width, cell - width, true})
+		}
+		if col+1 < width && !visited[cell+1] {
+			next = append(next, mazePassage{cell + 1, cell, false})
+		}
+		if row+1 < height && !visited[cell+width] {
+			next = append(next, mazePassage{cell + width, cell, true})
+		}
+		if col > 0 && !visited[cell-1] {
+			next = append(next, mazePassage{cell - 1, cell - 1, false})
+		}
+		if len(next) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		p := next[stream.uniformUint64(uint64(len(next)))]
+		clearMazeWall(walls, p.wallCell, p.south)
+		visited[p.neighbour] = true
+		stack = append(stack, p.neighbour)
+	}
+	return walls
+}
+
+func RandomMazeFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	width, height, err := UnpackRandomMazeInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomMazeBaseGas+width*height*RandomMazePerCellGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackRandomMazeOutput(generateRandomMaze(stream, width, height))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,65 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRandomMaze(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, size := range [][2]uint64{{1, 1}, {1, 7}, {8, 1}, {10, 10}, {32, 32}} {
+		width, height := size[0], size[1]
+		walls := mustRunMethod(t, state, testCaller, "randomMaze", new(big.Int).SetUint64(width), new(big.Int).SetUint64(height))[0].([]byte)
+
+		// Walk the maze from cell 0 through every open passage.
+		cells := width * height
+		reached := make([]bool, cells)
+		reached[0] = true
+		queue, passages := []uint64{0}, 0
+		for len(queue) > 0 {
+			cell := queue[0]
+			queue = queue[1:]
+			var open []uint64
+			if cell%width+1 < width && !MazeWall(walls, cell, false) {
+				open = append(open, cell+1)
+			}
+			if cell/width+1 < height && !MazeWall(walls, cell, true) {
+				open = append(open, cell+width)
+			}
+			if cell%width > 0 && !MazeWall(walls, cell-1, false) {
+				open = append(open, cell-1)
+			}
+			if cell >= width && !MazeWall(walls, cell-width, true) {
+				open = append(open, cell-width)
+			}
+			for _, next := range open {
+				if !reached[next] {
+					reached[next] = true
+					queue = append(queue, next)
+				}
+			}
+			passages += len(open)
+		}
+		for cell, ok := range reached {
+			if !ok {
+				t.Fatalf("%dx%d: cell %d unreachable", width, height, cell)
+			}
+		}
+		// Every passage was seen from both ends; a perfect maze has cells-1 of them.
+		if uint64(passages/2) != cells-1 {
+			t.Errorf("%dx%d: %d passages, want %d", width, height, passages/2, cells-1)
+		}
+	}
+}
+
+func TestRandomMazeInvalidSize(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, size := range [][2]int64{{0, 5}, {5, 0}, {33, 32}, {1025, 1}} {
+		if _, _, err := runMethod(state, testCaller, "randomMaze", big.NewInt(size[0]), big.NewInt(size[1])); err != errInvalidMazeSize {
+			t.Errorf("%dx%d: got %v, want %v", size[0], size[1], err, errInvalidMazeSize)
+		}
+	}
+}
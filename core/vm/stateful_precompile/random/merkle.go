@@ -0,0 +1,217 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	RandomMerkleBaseGas     = 2048
+	RandomMerklePerValueGas = 128
+	// RandomMerkleRootStoreGas covers the two slots randomMerkleRoot records its draw in.
+	RandomMerkleRootStoreGas = 2 * contract.WriteGasCostPerSlot
+
+	// MaxRandomMerkleValues bounds the size of a draw committed by randomMerkleRoot.
+	MaxRandomMerkleValues = 1 << 14
+)
+
+var (
+	errInvalidMerkleCount = errors.New("merkle draw size must be between 1 and MaxRandomMerkleValues")
+	errNoMerkleDraw       = errors.New("no merkle draw committed for caller")
+	errIndexOutOfRange    = errors.New("index out of range")
+)
+
+// RandomMerkleRootInput is the input of the randomMerkleRoot method.
+type RandomMerkleRootInput struct {
+	N *big.Int
+}
+
+// ProveValueInput is the input of the proveValue method.
+type ProveValueInput struct {
+	Index *big.Int
+}
+
+func PackRandomMerkleRootInput(n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomMerkleRoot", n)
+}
+
+func UnpackRandomMerkleRootInput(input []byte) (uint64, error) {
+	var in RandomMerkleRootInput
+	if err := unpackInput("randomMerkleRoot", input, &in); err != nil {
+		return 0, err
+	}
+	if in.N.Sign() == 0 || !in.N.IsUint64() || in.N.Uint64() > MaxRandomMerkleValues {
+		return 0, errInvalidMerkleCount
+	}
+	return in.N.Uint64(), nil
+}
+
+func PackRandomMerkleRootOutput(root common.Hash) ([]byte, error) {
+	return randomABI.Methods["randomMerkleRoot"].Outputs.Pack([32]byte(root))
+}
+
+func PackProveValueInput(index *big.Int) ([]byte, error) {
+	return randomABI.Pack("proveValue", index)
+}
+
+func UnpackProveValueInput(input []byte) (*big.Int, error) {
+	var in ProveValueInput
+	if err := unpackInput("proveValue", input, &in); err != nil {
+		return nil, err
+	}
+	return in.Index, nil
+}
+
+func PackProveValueOutput(value *big.Int, proof []common.Hash) ([]byte, error) {
+	return randomABI.Methods["proveValue"].Outputs.Pack(value, hashesToWords(proof))
+}
+
+// merkleLeaf returns the leaf committing to [value] at position [index] of a draw.
+func merkleLeaf(index uint64, value *big.Int) common.Hash {
+	return crypto.Keccak256Hash(common.BigToHash(new(big.Int).SetUint64(index)).Bytes(), common.BigToHash(value).Bytes())
+}
+
+// merkleTree returns every level of the binary tree over [leaves], from the leaves up to the
+// single root. Inner nodes are keccak(left || right); a node without a right sibling is paired
+// with itself, so every proof for a tree of n leaves has ceil(log2(n)) siblings.
+func merkleTree(leaves []common.Hash) [][]common.Hash {
+	levels := [][]common.Hash{leaves}
+	for level := leaves; len(level) > 1; {
+		next := make([]common.Hash, (len(level)+1)/2)
+		for i := range next {
+			left, right := level[2*i], level[2*i]
+			if 2*i+1 < len(level) {
+				right = level[2*i+1]
+			}
+			next[i] = crypto.Keccak256Hash(left[:], right[:])
+		}
+		levels = append(levels, next)
+		level = next
+	}
+	return levels
+}
+
+// merkleProof returns the siblings on the path from leaf [index] to the root of [levels].
+func merkleProof(levels [][]common.Hash, index uint64) []common.Hash {
+	proof := make([]common.Hash, 0, len(levels)-1)
+	for _, level := range levels[:len(levels)-1] {
+		sibling := index ^ 1
+		if sibling >= uint64(len(level)) {
+			sibling = index
+		}
+		proof = append(proof, level[sibling])
+		index /= 2
+	}
+	return proof
+}
+
+// merkleRootFromProof returns the root reached by hashing the leaf of [value] at position
+// [index] up along [proof]. It reports false if the proof is too short for the index.
+func merkleRootFromProof(index uint64, value *big.Int, proof []common.Hash) (common.Hash, bool) {
+	return merkleRootFromLeaf(index, merkleLeaf(index, value), proof)
+}
+
+// merkleRootFromLeaf is like merkleRootFromProof for an already hashed [leaf].
+func merkleRootFromLeaf(index uint64, leaf common.Hash, proof []common.Hash) (common.Hash, bool) {
+	node := leaf
+	for _, sibling := range proof {
+		if index%2 == 0 {
+			node = crypto.Keccak256Hash(node[:], sibling[:])
+		} else {
+			node = crypto.Keccak256Hash(sibling[:], node[:])
+		}
+		index /= 2
+	}
+	return node, index == 0
+}
+
+// VerifyMerkleProof reports whether [proof] shows that [value] is the draw at position [index]
+// of the set committed to by [root].
+func VerifyMerkleProof(root common.Hash, index uint64, value *big.Int, proof []common.Hash) bool {
+	node, ok := merkleRootFromProof(index, value, proof)
+	return ok && node == root
+}
+
+// generateMerkleDraw draws [n] values from [stream] and returns them with their merkle tree.
+func generateMerkleDraw(stream *randomStream, n uint64) ([]*big.Int, [][]common.Hash) {
+	values := make([]*big.Int, n)
+	leaves := make([]common.Hash, n)
+	for i := range values {
+		values[i] = stream.next()
+		leaves[i] = merkleLeaf(uint64(i), values[i])
+	}
+	return values, merkleTree(leaves)
+}
+
+// loadMerkleDraw returns the size of the draw last committed by [caller] and the nonce it was
+// drawn at. The size is zero if the caller never committed a draw.
+func loadMerkleDraw(state contract.StateDB, addr common.Address, caller common.Address) (uint64, uint64) {
+	n := state.GetState(addr, stateKey("merkle.count", caller.Bytes())).Big().Uint64()
+	nonce := state.GetState(addr, stateKey("merkle.nonce", caller.Bytes())).Big().Uint64()
+	return n, nonce
+}
+
+func RandomMerkleRootFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	n, err := UnpackRandomMerkleRootInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomMerkleBaseGas+RandomMerkleRootStoreGas+n*RandomMerklePerValueGas); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+
+	state := accessibleState.GetStateDB()
+	nonce := state.GetNonce(caller)
+	_, levels := generateMerkleDraw(newCallerStreamAt(addr, caller, nonce), n)
+	root := levels[len(levels)-1][0]
+
+	// Remember the draw so that proveValue can rebuild it after the caller's nonce moved on.
+	state.SetState(addr, stateKey("merkle.count", caller.Bytes()), common.BigToHash(new(big.Int).SetUint64(n)))
+	state.SetState(addr, stateKey("merkle.nonce", caller.Bytes()), common.BigToHash(new(big.Int).SetUint64(nonce)))
+
+	ret, err = PackRandomMerkleRootOutput(root)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
+
+func ProveValueFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	index, err := UnpackProveValueInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+
+	state := accessibleState.GetStateDB()
+	n, nonce := loadMerkleDraw(state, addr, caller)
+	if n == 0 {
+		return nil, suppliedGas, errNoMerkleDraw
+	}
+	if !index.IsUint64() || index.Uint64() >= n {
+		return nil, suppliedGas, errIndexOutOfRange
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomMerkleBaseGas+n*RandomMerklePerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	values, levels := generateMerkleDraw(newCallerStreamAt(addr, caller, nonce), n)
+
+	ret, err = PackProveValueOutput(values[index.Uint64()], merkleProof(levels, index.Uint64()))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,85 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+func TestMerkleRoot(t *testing.T) {
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 3)
+
+	for _, n := range []uint64{1, 2, 5, 13, 64} {
+		out := mustRunMethod(t, state, testCaller, "randomMerkleRoot", new(big.Int).SetUint64(n))
+		root := common.Hash(out[0].([32]byte))
+
+		// Recompute the root by hand from the caller's stream.
+		stream := newCallerStream(randomNCSPRNGContractAddr, testCaller, state.state)
+		level := make([]common.Hash, n)
+		for i := range level {
+			level[i] = merkleLeaf(uint64(i), stream.next())
+		}
+		levels := merkleTree(level)
+		if want := levels[len(levels)-1][0]; root != want {
+			t.Errorf("n=%d: root %x, want %x", n, root, want)
+		}
+	}
+}
+
+func TestMerkleRootGas(t *testing.T) {
+	state := newMockAccessibleState()
+	_, remaining, err := runMethod(state, testCaller, "randomMerkleRoot", big.NewInt(8))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The draw is recorded in two slots, which the caller pays to write.
+	if used, want := testGas-remaining, uint64(RandomMerkleBaseGas+2*contract.WriteGasCostPerSlot+8*RandomMerklePerValueGas); used != want {
+		t.Fatalf("used %d gas, want %d", used, want)
+	}
+}
+
+func TestProveValue(t *testing.T) {
+	const n = 13
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 3)
+
+	if _, _, err := runMethod(state, testCaller, "proveValue", big.NewInt(0)); err != errNoMerkleDraw {
+		t.Fatalf("got %v, want %v", err, errNoMerkleDraw)
+	}
+	out := mustRunMethod(t, state, testCaller, "randomMerkleRoot", big.NewInt(n))
+	root := common.Hash(out[0].([32]byte))
+	expected := newCallerStream(randomNCSPRNGContractAddr, testCaller, state.state)
+
+	// Proofs must still be served once the caller's nonce has moved on.
+	state.state.SetNonce(testCaller, 4)
+	for i := uint64(0); i < n; i++ {
+		out := mustRunMethod(t, state, testCaller, "proveValue", new(big.Int).SetUint64(i))
+		value := out[0].(*big.Int)
+		siblings := out[1].([][32]byte)
+		proof := make([]common.Hash, len(siblings))
+		for j, s := range siblings {
+			proof[j] = s
+		}
+		if want := expected.next(); value.Cmp(want) != 0 {
+			t.Fatalf("index %d: value %x, want %x", i, value, want)
+		}
+		if !VerifyMerkleProof(root, i, value, proof) {
+			t.Fatalf("index %d: valid proof rejected", i)
+		}
+		if VerifyMerkleProof(root, i, new(big.Int).Add(value, common.Big1), proof) {
+			t.Fatalf("index %d: proof accepted for wrong value", i)
+		}
+		if VerifyMerkleProof(root, i^1, value, proof) {
+			t.Fatalf("index %d: proof accepted for wrong index", i)
+		}
+	}
+	if _, _, err := runMethod(state, testCaller, "proveValue", big.NewInt(n)); err != errIndexOutOfRange {
+		t.Fatalf("got %v, want %v", err, errIndexOutOfRange)
+	}
+}
@@ -0,0 +1,101 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomFromMessageBaseGas = 1024 + contract.ReadGasCostPerSlot
+)
+
+// Inbound cross-chain messages reach a transaction as predicates, stored per source address
+// and exposed through GetPredicateStorageSlots. randomFromMessage expects a message to start
+// with its 32 byte nonce, which its sender cannot reuse, so the draw is bound to one delivery.
+var (
+	errNoMessage        = errors.New("no predicate message at index for source")
+	errMessageTooShort  = errors.New("predicate message is shorter than its nonce")
+	errMessageIndexSize = errors.New("message index does not fit in an int")
+)
+
+// RandomFromMessageInput is the input of the randomFromMessage method.
+type RandomFromMessageInput struct {
+	Source common.Address
+	Index  *big.Int
+	N      *big.Int
+}
+
+func PackRandomFromMessageInput(source common.Address, index *big.Int, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomFromMessage", source, index, n)
+}
+
+// UnpackRandomFromMessageInput returns the source, the index of the message and the number of
+// values to draw.
+func UnpackRandomFromMessageInput(input []byte) (common.Address, int, uint64, error) {
+	var in RandomFromMessageInput
+	if err := unpackInput("randomFromMessage", input, &in); err != nil {
+		return common.Address{}, 0, 0, err
+	}
+	if !in.Index.IsInt64() {
+		return common.Address{}, 0, 0, errMessageIndexSize
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return common.Address{}, 0, 0, errTooManyValues
+	}
+	return in.Source, int(in.Index.Int64()), in.N.Uint64(), nil
+}
+
+func PackRandomFromMessageOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomFromMessage"].Outputs.Pack(randomValues)
+}
+
+// messageNonce returns the nonce of the predicate message [message], its first 32 bytes.
+func messageNonce(message []byte) (common.Hash, error) {
+	if len(message) < common.HashLength {
+		return common.Hash{}, errMessageTooShort
+	}
+	return common.BytesToHash(message[:common.HashLength]), nil
+}
+
+// newMessageStream returns the stream of the message with nonce [nonce] from [source] for
+// [caller]. It depends on nothing else, so every call handling the same message draws the
+// same values.
+func newMessageStream(precompileAddr common.Address, caller common.Address, source common.Address, nonce common.Hash) *randomStream {
+	key := make([]byte, 0, 2*common.AddressLength+common.HashLength)
+	key = append(append(append(key, caller.Bytes()...), source.Bytes()...), nonce.Bytes()...)
+	return newKeyedStream(precompileAddr, "randomFromMessage", key)
+}
+
+// RandomFromMessageFunc returns [n] values anchored to the nonce of the predicate message at
+// [index] of [source] in the current transaction.
+func RandomFromMessageFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	source, index, n, err := UnpackRandomFromMessageInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomFromMessageBaseGas+n*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	message, ok := accessibleState.GetStateDB().GetPredicateStorageSlots(source, index)
+	if !ok {
+		return nil, remainingGas, errNoMessage
+	}
+	nonce, err := messageNonce(message)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	ret, err = PackRandomFromMessageOutput(newMessageStream(addr, caller, source, nonce).values(n))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,45 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRandomFromMessage(t *testing.T) {
+	state := newMockAccessibleState()
+	source := common.HexToAddress("0x0b71d9e")
+	nonce := common.HexToHash("0x4e0ce")
+	state.state.SetPredicateStorageSlots(source, [][]byte{
+		append(nonce.Bytes(), []byte("payload")...),
+		[]byte("short"),
+	})
+
+	values := mustRunMethod(t, state, testCaller, "randomFromMessage", source, big.NewInt(0), big.NewInt(3))[0].([]*big.Int)
+	stream := newMessageStream(randomNCSPRNGContractAddr, testCaller, source, nonce)
+	for i, v := range values {
+		if want := stream.next(); v.Cmp(want) != 0 {
+			t.Fatalf("value %d: got %x, want %x", i, v, want)
+		}
+	}
+	// The draw only depends on the message, not on the caller nonce or the block.
+	state.state.SetNonce(testCaller, 9)
+	state.blockCtx.BlockNumber = big.NewInt(77)
+	if again := mustRunMethod(t, state, testCaller, "randomFromMessage", source, big.NewInt(0), big.NewInt(3))[0].([]*big.Int); again[0].Cmp(values[0]) != 0 {
+		t.Fatal("same message yielded different values")
+	}
+
+	if _, _, err := runMethod(state, testCaller, "randomFromMessage", source, big.NewInt(1), big.NewInt(3)); err != errMessageTooShort {
+		t.Errorf("short message: got %v, want %v", err, errMessageTooShort)
+	}
+	if _, _, err := runMethod(state, testCaller, "randomFromMessage", source, big.NewInt(2), big.NewInt(3)); err != errNoMessage {
+		t.Errorf("absent message: got %v, want %v", err, errNoMessage)
+	}
+	if _, _, err := runMethod(state, testCaller, "randomFromMessage", common.HexToAddress("0x07e4"), big.NewInt(0), big.NewInt(3)); err != errNoMessage {
+		t.Errorf("other source: got %v, want %v", err, errNoMessage)
+	}
+}
@@ -0,0 +1,103 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/holiman/uint256"
+)
+
+const (
+	RandomMixtureBaseGas = 1024
+
+	// RandomMixturePerValueGas covers the component choice and the value drawn from it.
+	RandomMixturePerValueGas = 2 * RandomPerValueGas
+)
+
+var errInvalidMixtureRange = errors.New("mixture components require min <= max")
+
+// RandomMixtureInput is the input of the randomMixture method.
+type RandomMixtureInput struct {
+	MixProbBps *big.Int
+	MinA       *big.Int
+	MaxA       *big.Int
+	MinB       *big.Int
+	MaxB       *big.Int
+	N          *big.Int
+}
+
+func PackRandomMixtureInput(mixProbBps *big.Int, minA *big.Int, maxA *big.Int, minB *big.Int, maxB *big.Int, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomMixture", mixProbBps, minA, maxA, minB, maxB, n)
+}
+
+func UnpackRandomMixtureInput(input []byte) (RandomMixtureInput, error) {
+	var in RandomMixtureInput
+	if err := unpackInput("randomMixture", input, &in); err != nil {
+		return RandomMixtureInput{}, err
+	}
+	if !in.MixProbBps.IsUint64() || in.MixProbBps.Uint64() > maxBasisPoints {
+		return RandomMixtureInput{}, errInvalidProbability
+	}
+	if in.MinA.Cmp(in.MaxA) > 0 || in.MinB.Cmp(in.MaxB) > 0 {
+		return RandomMixtureInput{}, errInvalidMixtureRange
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return RandomMixtureInput{}, errTooManyValues
+	}
+	return in, nil
+}
+
+func PackRandomMixtureOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomMixture"].Outputs.Pack(randomValues)
+}
+
+// uniformInRange draws a value uniformly from [min, max] with randomStream.scaleTo. [min] must
+// not exceed [max], and both must fit in 256 bits. The full range [0, 2^256) is one word.
+func uniformInRange(stream *randomStream, min *big.Int, max *big.Int) *big.Int {
+	width, _ := uint256.FromBig(new(big.Int).Sub(max, min))
+	r, overflow := new(uint256.Int).AddOverflow(width, uint256.NewInt(1))
+	if overflow {
+		return stream.next()
+	}
+	v := stream.scaleTo(r).ToBig()
+	return v.Add(v, min)
+}
+
+// generateRandomMixture draws [n] values from a mixture of the uniform distributions over
+// [minA, maxA] and [minB, maxB]. For every value the first component is chosen with
+// probability mixProbBps/10000, then the value is drawn from the chosen component.
+func generateRandomMixture(stream *randomStream, in RandomMixtureInput, n uint64) []*big.Int {
+	values := make([]*big.Int, n)
+	for i := range values {
+		if stream.bernoulli(in.MixProbBps.Uint64()) {
+			values[i] = uniformInRange(stream, in.MinA, in.MaxA)
+		} else {
+			values[i] = uniformInRange(stream, in.MinB, in.MaxB)
+		}
+	}
+	return values
+}
+
+func RandomMixtureFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackRandomMixtureInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	n := in.N.Uint64()
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomMixtureBaseGas+n*RandomMixturePerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackRandomMixtureOutput(generateRandomMixture(stream, in, n))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
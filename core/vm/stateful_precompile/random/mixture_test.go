@@ -0,0 +1,54 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRandomMixtureProportion(t *testing.T) {
+	const (
+		bps    = 3000
+		n      = MaxRandomValues
+		rounds = 4
+	)
+	state := newMockAccessibleState()
+	minA, maxA := big.NewInt(0), big.NewInt(99)
+	minB, maxB := big.NewInt(1000), big.NewInt(1999)
+
+	fromA := 0
+	for nonce := uint64(0); nonce < rounds; nonce++ {
+		state.state.SetNonce(testCaller, nonce)
+		out := mustRunMethod(t, state, testCaller, "randomMixture", big.NewInt(bps), minA, maxA, minB, maxB, big.NewInt(n))
+		for _, v := range out[0].([]*big.Int) {
+			switch {
+			case v.Cmp(minA) >= 0 && v.Cmp(maxA) <= 0:
+				fromA++
+			case v.Cmp(minB) >= 0 && v.Cmp(maxB) <= 0:
+			default:
+				t.Fatalf("value %v outside both components", v)
+			}
+		}
+	}
+	// The expected count is 1228.8 with a standard deviation of about 29.
+	expected := rounds * n * bps / maxBasisPoints
+	if diff := fromA - expected; diff < -150 || diff > 150 {
+		t.Errorf("got %d values from the first component, expected about %d", fromA, expected)
+	}
+}
+
+func TestRandomMixtureInvalidInput(t *testing.T) {
+	state := newMockAccessibleState()
+	one, two := big.NewInt(1), big.NewInt(2)
+	if _, _, err := runMethod(state, testCaller, "randomMixture", big.NewInt(maxBasisPoints+1), one, two, one, two, one); err != errInvalidProbability {
+		t.Errorf("probability: got %v, want %v", err, errInvalidProbability)
+	}
+	if _, _, err := runMethod(state, testCaller, "randomMixture", one, two, one, one, two, one); err != errInvalidMixtureRange {
+		t.Errorf("range: got %v, want %v", err, errInvalidMixtureRange)
+	}
+	if _, _, err := runMethod(state, testCaller, "randomMixture", one, one, two, one, two, big.NewInt(MaxRandomValues+1)); err != errTooManyValues {
+		t.Errorf("count: got %v, want %v", err, errTooManyValues)
+	}
+}
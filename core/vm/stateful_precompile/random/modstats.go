@@ -0,0 +1,85 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/holiman/uint256"
+)
+
+const (
+	RandomModWithStatsBaseGas = 1024
+	// RandomModWithStatsPerValueGas covers the expected two words drawn per value in the
+	// worst case of a modulus just above 2^255.
+	RandomModWithStatsPerValueGas = 2 * RandomPerValueGas
+)
+
+var errZeroModulus = errors.New("modulus must be non-zero")
+
+// RandomModWithStatsInput is the input of the randomModWithStats method.
+type RandomModWithStatsInput struct {
+	Modulus *big.Int
+	N       *big.Int
+}
+
+func PackRandomModWithStatsInput(modulus *big.Int, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomModWithStats", modulus, n)
+}
+
+func UnpackRandomModWithStatsInput(input []byte) (RandomModWithStatsInput, error) {
+	var in RandomModWithStatsInput
+	if err := unpackInput("randomModWithStats", input, &in); err != nil {
+		return RandomModWithStatsInput{}, err
+	}
+	if in.Modulus.Sign() == 0 {
+		return RandomModWithStatsInput{}, errZeroModulus
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return RandomModWithStatsInput{}, errTooManyValues
+	}
+	return in, nil
+}
+
+func PackRandomModWithStatsOutput(randomValues []*big.Int, rejections []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomModWithStats"].Outputs.Pack(randomValues, rejections)
+}
+
+// generateRandomModWithStats draws [n] unbiased values in [0, modulus) and, for each, the
+// number of stream words rejected before it. Values are scaled with
+// randomStream.scaleToWithRejections. A verifier replaying the stream can check that exactly
+// those words x had a low half of x*modulus below (2^256 - modulus) mod modulus, and that the
+// high half of the accepted one is the returned value.
+func generateRandomModWithStats(stream *randomStream, modulus *big.Int, n uint64) ([]*big.Int, []*big.Int) {
+	bound, _ := uint256.FromBig(modulus)
+	values := make([]*big.Int, n)
+	rejections := make([]*big.Int, n)
+	for i := range values {
+		v, r := stream.scaleToWithRejections(bound)
+		values[i], rejections[i] = v.ToBig(), new(big.Int).SetUint64(r)
+	}
+	return values, rejections
+}
+
+func RandomModWithStatsFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackRandomModWithStatsInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	n := in.N.Uint64()
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomModWithStatsBaseGas+n*RandomModWithStatsPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackRandomModWithStatsOutput(generateRandomModWithStats(stream, in.Modulus, n))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
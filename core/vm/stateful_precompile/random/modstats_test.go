@@ -0,0 +1,57 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+)
+
+func TestRandomModWithStatsConsistency(t *testing.T) {
+	state := newMockAccessibleState()
+	// A modulus just above 2^255 rejects almost half of all words.
+	modulus := new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(12345))
+	bound := uint256.MustFromBig(modulus)
+	threshold := new(uint256.Int).Neg(bound)
+	threshold.Mod(threshold, bound)
+	scale := func(w *big.Int) (hi uint256.Int, rejected bool) {
+		hi, lo := mul512(uint256.MustFromBig(w), bound)
+		return hi, lo.Lt(threshold)
+	}
+
+	out := mustRunMethod(t, state, testCaller, "randomModWithStats", modulus, big.NewInt(200))
+	values, rejections := out[0].([]*big.Int), out[1].([]*big.Int)
+
+	// Replay the raw stream and check the reported rejections word by word.
+	raw := newCallerStream(randomNCSPRNGContractAddr, testCaller, state.state)
+	total := uint64(0)
+	for i := range values {
+		for r := uint64(0); r < rejections[i].Uint64(); r++ {
+			if _, rejected := scale(raw.next()); !rejected {
+				t.Fatalf("value %d: word %d reported rejected but was acceptable", i, r)
+			}
+		}
+		v, rejected := scale(raw.next())
+		if rejected {
+			t.Fatalf("value %d: accepted word should have been rejected", i)
+		}
+		if v.ToBig().Cmp(values[i]) != 0 {
+			t.Fatalf("value %d: accepted word does not scale to the returned value", i)
+		}
+		total += rejections[i].Uint64()
+	}
+	if total == 0 {
+		t.Fatalf("no rejections reported for a modulus rejecting about half the words")
+	}
+
+	// A power of two modulus never rejects.
+	out = mustRunMethod(t, state, testCaller, "randomModWithStats", big.NewInt(1024), big.NewInt(50))
+	for i, r := range out[1].([]*big.Int) {
+		if r.Sign() != 0 {
+			t.Fatalf("value %d: %v rejections for a power of two modulus", i, r)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	MultiPartyRandomBaseGas        = 1024
+	MultiPartyRandomPerContributor = 64
+
+	// MaxContributors bounds the number of contributors mixed by multiPartyRandom.
+	MaxContributors = 256
+)
+
+var (
+	errNoContributors        = errors.New("at least one contributor is required")
+	errTooManyContributors   = errors.New("too many contributors")
+	errDuplicateContributors = errors.New("duplicate contributor")
+)
+
+// MultiPartyRandomInput is the input of the multiPartyRandom method.
+type MultiPartyRandomInput struct {
+	Contributors []common.Address
+	N            *big.Int
+}
+
+func PackMultiPartyRandomInput(contributors []common.Address, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("multiPartyRandom", contributors, n)
+}
+
+func UnpackMultiPartyRandomInput(input []byte) (MultiPartyRandomInput, error) {
+	var in MultiPartyRandomInput
+	if err := unpackInput("multiPartyRandom", input, &in); err != nil {
+		return MultiPartyRandomInput{}, err
+	}
+	if len(in.Contributors) == 0 {
+		return MultiPartyRandomInput{}, errNoContributors
+	}
+	if len(in.Contributors) > MaxContributors {
+		return MultiPartyRandomInput{}, errTooManyContributors
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return MultiPartyRandomInput{}, errTooManyValues
+	}
+	return in, nil
+}
+
+func PackMultiPartyRandomOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["multiPartyRandom"].Outputs.Pack(randomValues)
+}
+
+// multiPartySeed combines the seeds of all [contributors] into one. The contributors are
+// sorted first so the order they are listed in does not matter, and the seeds are hashed
+// together rather than XORed so that every contributor affects the result and none can
+// cancel out another.
+func multiPartySeed(serverSeed []byte, contributors []common.Address) ([]byte, error) {
+	sorted := make([]common.Address, len(contributors))
+	copy(sorted, contributors)
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i][:], sorted[j][:]) < 0 })
+
+	seeds := make([][]byte, len(sorted))
+	for i, contributor := range sorted {
+		if i > 0 && contributor == sorted[i-1] {
+			return nil, errDuplicateContributors
+		}
+		seeds[i] = userSeed(serverSeed, contributor)
+	}
+	return crypto.Keccak256(seeds...), nil
+}
+
+func MultiPartyRandomFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackMultiPartyRandomInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	n := in.N.Uint64()
+	requiredGas := MultiPartyRandomBaseGas + uint64(len(in.Contributors))*MultiPartyRandomPerContributor + n*RandomPerValueGas
+	if remainingGas, err = contract.DeductGas(suppliedGas, requiredGas); err != nil {
+		return nil, 0, err
+	}
+
+	key := serverSeed(addr)
+	seed, err := multiPartySeed(key, in.Contributors)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	stream := newRandomStream(key, seed, accessibleState.GetStateDB().GetNonce(caller))
+	ret, err = PackMultiPartyRandomOutput(stream.values(n))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,50 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestMultiPartyRandom(t *testing.T) {
+	state := newMockAccessibleState()
+	a := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	b := common.HexToAddress("0x000000000000000000000000000000000000000b")
+	c := common.HexToAddress("0x000000000000000000000000000000000000000c")
+	d := common.HexToAddress("0x000000000000000000000000000000000000000d")
+
+	draw := func(contributors ...common.Address) *big.Int {
+		return mustRunMethod(t, state, testCaller, "multiPartyRandom", contributors, big.NewInt(1))[0].([]*big.Int)[0]
+	}
+	base := draw(a, b, c)
+	if reordered := draw(c, a, b); reordered.Cmp(base) != 0 {
+		t.Errorf("contributor order changed the output")
+	}
+	for name, contributors := range map[string][]common.Address{
+		"removed a": {b, c},
+		"removed b": {a, c},
+		"removed c": {a, b},
+		"changed c": {a, b, d},
+		"added d":   {a, b, c, d},
+		"changed a": {d, b, c},
+	} {
+		if draw(contributors...).Cmp(base) == 0 {
+			t.Errorf("%s: output unchanged", name)
+		}
+	}
+}
+
+func TestMultiPartyRandomInvalid(t *testing.T) {
+	state := newMockAccessibleState()
+	a := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	if _, _, err := runMethod(state, testCaller, "multiPartyRandom", []common.Address{}, big.NewInt(1)); err != errNoContributors {
+		t.Errorf("empty: got %v, want %v", err, errNoContributors)
+	}
+	if _, _, err := runMethod(state, testCaller, "multiPartyRandom", []common.Address{a, a}, big.NewInt(1)); err != errDuplicateContributors {
+		t.Errorf("duplicate: got %v, want %v", err, errDuplicateContributors)
+	}
+}
@@ -0,0 +1,76 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomMultipleGasCost = 1024
+)
+
+var (
+	errZeroTick         = errors.New("tick must be non-zero")
+	errMultipleOverflow = errors.New("maxMultiples * tick overflows uint256")
+)
+
+// RandomMultipleInput is the input of the randomMultiple method.
+type RandomMultipleInput struct {
+	MaxMultiples *big.Int
+	Tick         *big.Int
+}
+
+func PackRandomMultipleInput(maxMultiples *big.Int, tick *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomMultiple", maxMultiples, tick)
+}
+
+func UnpackRandomMultipleInput(input []byte) (RandomMultipleInput, error) {
+	var in RandomMultipleInput
+	if err := unpackInput("randomMultiple", input, &in); err != nil {
+		return RandomMultipleInput{}, err
+	}
+	if in.Tick.Sign() == 0 {
+		return RandomMultipleInput{}, errZeroTick
+	}
+	if new(big.Int).Mul(in.MaxMultiples, in.Tick).Cmp(math.MaxBig256) > 0 {
+		return RandomMultipleInput{}, errMultipleOverflow
+	}
+	return in, nil
+}
+
+func PackRandomMultipleOutput(randomValue *big.Int) ([]byte, error) {
+	return randomABI.Methods["randomMultiple"].Outputs.Pack(randomValue)
+}
+
+// generateRandomMultiple draws a multiple count uniformly from [0, maxMultiples] and scales it
+// by tick, so every value in {0, tick, ..., maxMultiples*tick} is equally likely.
+func generateRandomMultiple(stream *randomStream, maxMultiples *big.Int, tick *big.Int) *big.Int {
+	multiples := stream.uniform(new(big.Int).Add(maxMultiples, common.Big1))
+	return multiples.Mul(multiples, tick)
+}
+
+func RandomMultipleFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomMultipleGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	in, err := UnpackRandomMultipleInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackRandomMultipleOutput(generateRandomMultiple(stream, in.MaxMultiples, in.Tick))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
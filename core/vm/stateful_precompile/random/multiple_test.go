@@ -0,0 +1,39 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+func TestRandomMultiple(t *testing.T) {
+	state := newMockAccessibleState()
+	maxMultiples, tick := big.NewInt(20), big.NewInt(250)
+	limit := new(big.Int).Mul(maxMultiples, tick)
+
+	for nonce := uint64(0); nonce < 200; nonce++ {
+		state.state.SetNonce(testCaller, nonce)
+		out := mustRunMethod(t, state, testCaller, "randomMultiple", maxMultiples, tick)
+		v := out[0].(*big.Int)
+		if new(big.Int).Mod(v, tick).Sign() != 0 {
+			t.Fatalf("nonce %d: %v is not a multiple of %v", nonce, v, tick)
+		}
+		if v.Sign() < 0 || v.Cmp(limit) > 0 {
+			t.Fatalf("nonce %d: %v out of range [0, %v]", nonce, v, limit)
+		}
+	}
+}
+
+func TestRandomMultipleInvalidInput(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "randomMultiple", big.NewInt(10), new(big.Int)); err != errZeroTick {
+		t.Errorf("zero tick: got %v, want %v", err, errZeroTick)
+	}
+	if _, _, err := runMethod(state, testCaller, "randomMultiple", math.MaxBig256, big.NewInt(2)); err != errMultipleOverflow {
+		t.Errorf("overflow: got %v, want %v", err, errMultipleOverflow)
+	}
+}
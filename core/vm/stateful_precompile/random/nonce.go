@@ -0,0 +1,40 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+// NonceReuseGasCost is charged by randomNCSPRNG for checking and recording the caller nonce
+// when nonce reuse detection is enabled.
+const NonceReuseGasCost = contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot
+
+var errNonceReused = errors.New("random values already drawn at this nonce")
+
+// usedNonceKey returns the slot of the bitmap recording which nonces of [caller] were used.
+// Every slot covers 256 consecutive nonces, one bit each, so a caller drawing once per
+// transaction dirties a new slot only every 256 transactions.
+func usedNonceKey(caller common.Address, nonce uint64) common.Hash {
+	return stateKey("nonce.used", caller.Bytes(), common.BigToHash(new(big.Int).SetUint64(nonce/256)).Bytes())
+}
+
+// checkNonceReuse returns errNonceReused if [caller] already drew at [nonce] and otherwise
+// records the nonce as used, unless [readOnly] is set, in which case it only checks.
+func checkNonceReuse(state contract.StateDB, addr common.Address, caller common.Address, nonce uint64, readOnly bool) error {
+	key := usedNonceKey(caller, nonce)
+	used := state.GetState(addr, key).Big()
+	bit := uint(nonce % 256)
+	if used.Bit(int(bit)) == 1 {
+		return errNonceReused
+	}
+	if !readOnly {
+		state.SetState(addr, key, common.BigToHash(used.SetBit(used, int(bit), 1)))
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestNonceReuseDetection(t *testing.T) {
+	state := newMockAccessibleState()
+	precompile := CreateRandomNCSPRNGPrecompile(DefaultConfig(), WithNonceReuseDetection())
+	input, err := PackRandomNCSPRNGInput(big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func(readOnly bool) error {
+		_, _, err := precompile.Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, readOnly)
+		return err
+	}
+
+	for _, nonce := range []uint64{0, 1, 255, 256, 1000} {
+		state.state.SetNonce(testCaller, nonce)
+		if err := run(true); err != nil {
+			t.Fatalf("nonce %d: read-only draw of a fresh nonce: %v", nonce, err)
+		}
+		if err := run(false); err != nil {
+			t.Fatalf("nonce %d: fresh nonce: %v", nonce, err)
+		}
+		if err := run(false); err != errNonceReused {
+			t.Fatalf("nonce %d: reused nonce: got %v, want %v", nonce, err, errNonceReused)
+		}
+		if err := run(true); err != errNonceReused {
+			t.Fatalf("nonce %d: read-only reused nonce: got %v, want %v", nonce, err, errNonceReused)
+		}
+	}
+	// Nonces 0, 1 and 255 share the first slot of the bitmap.
+	want := new(big.Int).SetBit(big.NewInt(0b11), 255, 1)
+	if got := state.state.GetState(randomNCSPRNGContractAddr, usedNonceKey(testCaller, 0)).Big(); got.Cmp(want) != 0 {
+		t.Errorf("first bitmap slot: got %x, want %x", got, want)
+	}
+
+	// Without the option the same nonce can be drawn at repeatedly.
+	runRandomNCSPRNG(t, state, 2, false)
+	runRandomNCSPRNG(t, state, 2, false)
+}
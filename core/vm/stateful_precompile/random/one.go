@@ -0,0 +1,65 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+// RandomOneGasCost is the cost of randomNCSPRNG for a single value.
+const RandomOneGasCost = RandomNCSPRNGBaseGas + RandomNCSPRNGPerValueGas
+
+func PackRandomOneInput() ([]byte, error) {
+	return randomABI.Pack("randomOne")
+}
+
+func PackRandomOneOutput(randomValue *big.Int) ([]byte, error) {
+	return randomABI.Methods["randomOne"].Outputs.Pack(randomValue)
+}
+
+func UnpackRandomOneOutput(data []byte) (*big.Int, error) {
+	var randomValue *big.Int
+	outputs := randomABI.Methods["randomOne"].Outputs
+	values, err := outputs.Unpack(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := outputs.Copy(&randomValue, values); err != nil {
+		return nil, err
+	}
+	return randomValue, nil
+}
+
+// newRandomOneFunc returns the randomOne handler of a precompile built with [o]. It returns
+// the first value randomNCSPRNG would return as a scalar, sparing callers the decoding of a
+// one element array.
+func newRandomOneFunc(o options) contract.RunStatefulPrecompileFunc {
+	return func(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		if remainingGas, err = contract.DeductGas(suppliedGas, RandomOneGasCost); err != nil {
+			return nil, 0, err
+		}
+
+		state := accessibleState.GetStateDB()
+		blockCtx := accessibleState.GetBlockContext()
+		stream, sources, err := newRandomNCSPRNGStream(addr, caller, blockCtx, o, state)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		if sources&EntropySourceFallback != 0 {
+			if remainingGas, err = reportEntropyFallback(state, addr, caller, blockCtx.BlockNumber.Uint64(), readOnly, remainingGas); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		ret, err = PackRandomOneOutput(stream.next())
+		if err != nil {
+			return nil, remainingGas, err
+		}
+
+		return ret, remainingGas, nil
+	}
+}
@@ -0,0 +1,44 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"testing"
+)
+
+func TestRandomOne(t *testing.T) {
+	state := newMockAccessibleState()
+	input, err := PackRandomOneInput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for nonce := uint64(0); nonce < 5; nonce++ {
+		state.state.SetNonce(testCaller, nonce)
+		ret, remainingGas, err := CreateRandomNCSPRNGPrecompile(DefaultConfig()).Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if used := testGas - remainingGas; used != RandomOneGasCost {
+			t.Errorf("used %d gas, want %d", used, RandomOneGasCost)
+		}
+		value, err := UnpackRandomOneOutput(ret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := runRandomNCSPRNG(t, state, 1, true)[0]; value.Cmp(want) != 0 {
+			t.Fatalf("nonce %d: got %x, want the first randomNCSPRNG value %x", nonce, value, want)
+		}
+
+		packed, err := PackRandomOneOutput(value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if unpacked, err := UnpackRandomOneOutput(packed); err != nil || unpacked.Cmp(value) != 0 {
+			t.Fatalf("round trip of %x yielded %v, %v", value, unpacked, err)
+		}
+	}
+	if _, err := UnpackRandomOneOutput(make([]byte, 31)); err == nil {
+		t.Error("unpacked a truncated output")
+	}
+}
@@ -0,0 +1,212 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"crypto/ecdsa"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Option customizes the precompile built by CreateRandomNCSPRNGPrecompile.
+type Option func(*options)
+
+// options holds the operator configuration of the precompile. The zero value reproduces the
+// behaviour of a precompile built without options.
+type options struct {
+	// signingKey signs the values returned by signedRandom. The method fails when it is nil.
+	signingKey *ecdsa.PrivateKey
+
+	// warmupDiscard is the number of leading words of every stream skipped by randomNCSPRNG.
+	warmupDiscard uint
+
+	// epochLength is the number of blocks after which randomNCSPRNG derives a new server
+	// seed. Per-epoch seeds are disabled when it is 0.
+	epochLength uint64
+
+	// serverSeed replaces the default base server seed of randomNCSPRNG when it is set.
+	serverSeed *common.Hash
+
+	// counterByteOrder is the byte order of the counter of the randomNCSPRNG streams.
+	counterByteOrder ByteOrder
+
+	// hmacHash is the hash function of the HMAC of the randomNCSPRNG streams.
+	hmacHash HMACHash
+
+	// detectNonceReuse makes randomNCSPRNG record the caller nonces it served and revert when
+	// one is served twice, see checkNonceReuse.
+	detectNonceReuse bool
+
+	// blockAuditLog makes randomNCSPRNG emit a BlockEntropyAudit event on the first draw of
+	// every block, see auditBlockEntropy.
+	blockAuditLog bool
+
+	// callCounter makes randomNCSPRNG mix a per-caller counter into its streams and advance it
+	// after every draw, see callCounterServerSeed.
+	callCounter bool
+
+	// blockSeed makes randomNCSPRNG mix a block seed rotated on the first draw of every block
+	// into its streams, see blockSeedServerSeed.
+	blockSeed bool
+
+	// whitening makes randomNCSPRNG hash every word of its streams once more, see
+	// randomStream.whiten.
+	whitening bool
+
+	// minEntropySources is the number of independent entropy sources the streams of
+	// randomNCSPRNG must be derived from, see independentEntropySources.
+	minEntropySources uint
+
+	// beaconPublicKey verifies the beacon signatures of verifyBeaconAndDraw. The method fails
+	// when it is nil.
+	beaconPublicKey *bls12381.G1Affine
+
+	// beaconSchedule binds the rounds accepted by verifyBeaconAndDraw to the block time. The
+	// method fails when it is nil.
+	beaconSchedule *BeaconSchedule
+}
+
+// newOptions returns the configuration resulting from applying [opts] in order.
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithSigningKey makes signedRandom sign its values with [key]. Signatures are deterministic
+// (RFC 6979), but they are part of the execution result, so every validator of the chain must
+// be configured with the same key or they will disagree on the output and fork.
+func WithSigningKey(key *ecdsa.PrivateKey) Option {
+	return func(o *options) {
+		o.signingKey = key
+	}
+}
+
+// WithWarmupDiscard makes randomNCSPRNG skip the first [n] words of every stream before
+// returning values, so the outputs of a fresh stream start at its n-th word. The default of 0
+// keeps the values returned by a precompile built without options.
+func WithWarmupDiscard(n uint) Option {
+	return func(o *options) {
+		o.warmupDiscard = n
+	}
+}
+
+// WithEpochLength makes randomNCSPRNG key its streams with a server seed derived for every
+// epoch of [blocks] blocks, see epochServerSeed. The default of 0 keeps a single server seed.
+func WithEpochLength(blocks uint64) Option {
+	return func(o *options) {
+		o.epochLength = blocks
+	}
+}
+
+// WithServerSeed makes randomNCSPRNG use [seed] as its base server seed instead of the default
+// keccak(precompileAddr). A seed revealed through revealServerSeed still takes precedence. A
+// zero seed carries no entropy and is replaced by the default one, see ncsprngBaseSeed.
+func WithServerSeed(seed common.Hash) Option {
+	return func(o *options) {
+		o.serverSeed = &seed
+	}
+}
+
+// WithCounterByteOrder makes randomNCSPRNG encode the counter of its streams in [order], for
+// compatibility with off-chain tooling that assumes little-endian counters. The default is
+// BigEndian.
+func WithCounterByteOrder(order ByteOrder) Option {
+	return func(o *options) {
+		o.counterByteOrder = order
+	}
+}
+
+// WithHMACHash makes randomNCSPRNG build the HMAC of its streams over [h], e.g. Keccak256 for
+// contracts recomputing values with keccak256. The default is SHA256, which keeps the values
+// of existing deployments. Off-chain verifiers must use the same hash, recorded in the
+// RandomnessWitness of every draw.
+func WithHMACHash(h HMACHash) Option {
+	return func(o *options) {
+		o.hmacHash = h
+	}
+}
+
+// WithNonceReuseDetection makes randomNCSPRNG revert with errNonceReused when a caller draws
+// twice at the same account nonce, which would return the same values again. Contracts calling
+// the method more than once per transaction must not enable it. Every call then also pays for
+// reading and writing the slot recording the used nonces.
+func WithNonceReuseDetection() Option {
+	return func(o *options) {
+		o.detectNonceReuse = true
+	}
+}
+
+// WithBlockAuditLog makes the first randomNCSPRNG draw of every block that is not read-only
+// emit a BlockEntropyAudit event recording the entropy anchors of the block, for operators
+// monitoring the fairness of the precompile. Every such draw pays for checking and recording
+// the last audited block.
+func WithBlockAuditLog() Option {
+	return func(o *options) {
+		o.blockAuditLog = true
+	}
+}
+
+// WithCallCounter makes randomNCSPRNG advance a counter of the caller after every draw and mix it
+// into the streams of the caller, so that repeated draws at the same account nonce return fresh
+// values. Advancing the counter writes state, so randomNCSPRNG then fails with the standard
+// write protection error in a static call. Every draw also pays for reading and writing the
+// counter.
+func WithCallCounter() Option {
+	return func(o *options) {
+		o.callCounter = true
+	}
+}
+
+// WithBlockSeed makes randomNCSPRNG key its streams with a seed kept in the precompile storage
+// and rotated by the first draw of every block, so the streams advance per block on top of the
+// account nonce. Rotating the seed writes state, so it only happens outside of read-only calls;
+// static calls are keyed with the seed as last rotated. The methods sharing the stream of
+// randomNCSPRNG read the seed but never rotate it.
+func WithBlockSeed() Option {
+	return func(o *options) {
+		o.blockSeed = true
+	}
+}
+
+// WithWhitening makes randomNCSPRNG and the methods sharing its stream whiten every value: each
+// word is replaced by the keccak hash of its HMAC-SHA256 digest. This guards against a subtle
+// bias of HMAC-SHA256 at the cost of one extra hash per value, which is not charged separately.
+// It changes every value drawn, so it must be set on all validators of a chain alike. The
+// default leaves the values of a precompile built without options.
+func WithWhitening() Option {
+	return func(o *options) {
+		o.whitening = true
+	}
+}
+
+// WithMinEntropySources makes randomNCSPRNG and the methods sharing its stream fail with
+// errInsufficientEntropy unless their streams are derived from at least [n] independent entropy
+// sources, see independentEntropySources. With a minimum of 2, for instance, a draw requires
+// both a secret server seed and PREVRANDAO, so it is rejected on a chain without the latter.
+// The default of 0 accepts any stream.
+func WithMinEntropySources(n uint) Option {
+	return func(o *options) {
+		o.minEntropySources = n
+	}
+}
+
+// WithBeaconPublicKey makes verifyBeaconAndDraw accept the rounds of the randomness beacon
+// signing with the BLS12-381 public key [key]. Without it, the method always fails.
+func WithBeaconPublicKey(key *bls12381.G1Affine) Option {
+	return func(o *options) {
+		o.beaconPublicKey = key
+	}
+}
+
+// WithBeaconSchedule makes verifyBeaconAndDraw accept only the rounds that [schedule] expects
+// around the time of the block. Without it, the method always fails.
+func WithBeaconSchedule(schedule BeaconSchedule) Option {
+	return func(o *options) {
+		o.beaconSchedule = &schedule
+	}
+}
@@ -0,0 +1,96 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	PackedSmallGasCost = 1024
+)
+
+var errInvalidPacking = errors.New("packing requires bitsEach > 0 and count * bitsEach <= 256")
+
+// PackedSmallInput is the input of the packedSmall method.
+type PackedSmallInput struct {
+	Count    *big.Int
+	BitsEach *big.Int
+}
+
+func PackPackedSmallInput(count *big.Int, bitsEach *big.Int) ([]byte, error) {
+	return randomABI.Pack("packedSmall", count, bitsEach)
+}
+
+func UnpackPackedSmallInput(input []byte) (uint, uint, error) {
+	var in PackedSmallInput
+	if err := unpackInput("packedSmall", input, &in); err != nil {
+		return 0, 0, err
+	}
+	if !in.Count.IsUint64() || !in.BitsEach.IsUint64() {
+		return 0, 0, errInvalidPacking
+	}
+	count, bitsEach := uint(in.Count.Uint64()), uint(in.BitsEach.Uint64())
+	if !validPacking(count, bitsEach) {
+		return 0, 0, errInvalidPacking
+	}
+	return count, bitsEach, nil
+}
+
+func PackPackedSmallOutput(packed *big.Int) ([]byte, error) {
+	return randomABI.Methods["packedSmall"].Outputs.Pack(packed)
+}
+
+// validPacking reports whether [count] values of [bitsEach] bits fit into a single word.
+func validPacking(count uint, bitsEach uint) bool {
+	return bitsEach > 0 && bitsEach <= 256 && count <= 256/bitsEach
+}
+
+// UnpackPackedSmall splits a word returned by packedSmall into its [count] values of
+// [bitsEach] bits. Value i occupies bits [i*bitsEach, (i+1)*bitsEach), counted from the
+// least significant bit.
+func UnpackPackedSmall(packed *big.Int, count uint, bitsEach uint) ([]*big.Int, error) {
+	if !validPacking(count, bitsEach) {
+		return nil, errInvalidPacking
+	}
+	mask := new(big.Int).Sub(new(big.Int).Lsh(common.Big1, bitsEach), common.Big1)
+	values := make([]*big.Int, count)
+	for i := range values {
+		values[i] = new(big.Int).Rsh(packed, uint(i)*bitsEach)
+		values[i].And(values[i], mask)
+	}
+	return values, nil
+}
+
+// generatePackedSmall returns [count] values uniform in [0, 2^bitsEach) packed into one word.
+// Every bit of a stream word is uniform and independent, so the packing is the low
+// count*bitsEach bits of a single word.
+func generatePackedSmall(stream *randomStream, count uint, bitsEach uint) *big.Int {
+	v := stream.next()
+	mask := new(big.Int).Sub(new(big.Int).Lsh(common.Big1, count*bitsEach), common.Big1)
+	return v.And(v, mask)
+}
+
+func PackedSmallFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, PackedSmallGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	count, bitsEach, err := UnpackPackedSmallInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackPackedSmallOutput(generatePackedSmall(stream, count, bitsEach))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,45 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestPackedSmallRoundTrip(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, tt := range []struct{ count, bitsEach uint }{{8, 32}, {256, 1}, {1, 256}, {5, 50}, {0, 8}} {
+		packed := mustRunMethod(t, state, testCaller, "packedSmall", new(big.Int).SetUint64(uint64(tt.count)), new(big.Int).SetUint64(uint64(tt.bitsEach)))[0].(*big.Int)
+		if packed.BitLen() > int(tt.count*tt.bitsEach) {
+			t.Fatalf("%d x %d bits: packed word has %d bits", tt.count, tt.bitsEach, packed.BitLen())
+		}
+		values, err := UnpackPackedSmall(packed, tt.count, tt.bitsEach)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if uint(len(values)) != tt.count {
+			t.Fatalf("%d x %d bits: got %d values", tt.count, tt.bitsEach, len(values))
+		}
+		repacked := new(big.Int)
+		for i := len(values) - 1; i >= 0; i-- {
+			if values[i].BitLen() > int(tt.bitsEach) {
+				t.Fatalf("%d x %d bits: value %d has %d bits", tt.count, tt.bitsEach, i, values[i].BitLen())
+			}
+			repacked.Lsh(repacked, tt.bitsEach).Or(repacked, values[i])
+		}
+		if repacked.Cmp(packed) != 0 {
+			t.Fatalf("%d x %d bits: repacked %x, want %x", tt.count, tt.bitsEach, repacked, packed)
+		}
+	}
+}
+
+func TestPackedSmallInvalidInput(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, tt := range []struct{ count, bitsEach int64 }{{9, 32}, {1, 0}, {1, 257}, {257, 1}} {
+		if _, _, err := runMethod(state, testCaller, "packedSmall", big.NewInt(tt.count), big.NewInt(tt.bitsEach)); err != errInvalidPacking {
+			t.Errorf("%d x %d bits: got %v, want %v", tt.count, tt.bitsEach, err, errInvalidPacking)
+		}
+	}
+}
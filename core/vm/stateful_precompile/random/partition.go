@@ -0,0 +1,102 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomPartitionBaseGas = 1024
+)
+
+var errInvalidPartition = errors.New("partition requires total > 0 and maxParts > 0")
+
+// RandomPartitionInput is the input of the randomPartition method.
+type RandomPartitionInput struct {
+	Total    *big.Int
+	MaxParts *big.Int
+}
+
+func PackRandomPartitionInput(total *big.Int, maxParts *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomPartition", total, maxParts)
+}
+
+func UnpackRandomPartitionInput(input []byte) (RandomPartitionInput, error) {
+	var in RandomPartitionInput
+	if err := unpackInput("randomPartition", input, &in); err != nil {
+		return RandomPartitionInput{}, err
+	}
+	if in.Total.Sign() == 0 || in.MaxParts.Sign() == 0 {
+		return RandomPartitionInput{}, errInvalidPartition
+	}
+	if !in.MaxParts.IsUint64() || in.MaxParts.Uint64() > MaxRandomValues {
+		return RandomPartitionInput{}, errTooManyValues
+	}
+	return in, nil
+}
+
+func PackRandomPartitionOutput(parts []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomPartition"].Outputs.Pack(parts)
+}
+
+// generateRandomPartition splits [total] into at most [maxParts] positive parts by cutting
+// the interval [0, total) at k-1 random points:
+//
+//  1. The number of parts k is drawn uniformly from [1, min(maxParts, total)].
+//  2. k-1 distinct cut points are drawn uniformly from [1, total), resampling repeats.
+//  3. The cut points are sorted and the parts are the gaps between consecutive cuts.
+//
+// Every composition of total into exactly k positive parts is equally likely for a given k.
+func generateRandomPartition(stream *randomStream, total *big.Int, maxParts uint64) []*big.Int {
+	limit := new(big.Int).SetUint64(maxParts)
+	if total.Cmp(limit) < 0 {
+		limit.Set(total)
+	}
+	k := stream.uniformUint64(limit.Uint64()) + 1
+
+	span := new(big.Int).Sub(total, common.Big1)
+	cuts := make([]*big.Int, 0, k+1)
+	seen := make(map[string]bool)
+	for uint64(len(cuts)) < k-1 {
+		cut := stream.uniform(span)
+		cut.Add(cut, common.Big1)
+		if seen[string(cut.Bytes())] {
+			continue
+		}
+		seen[string(cut.Bytes())] = true
+		cuts = append(cuts, cut)
+	}
+	cuts = append(cuts, new(big.Int), total)
+	sort.Slice(cuts, func(i, j int) bool { return cuts[i].Cmp(cuts[j]) < 0 })
+
+	parts := make([]*big.Int, k)
+	for i := range parts {
+		parts[i] = new(big.Int).Sub(cuts[i+1], cuts[i])
+	}
+	return parts
+}
+
+func RandomPartitionFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackRandomPartitionInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomPartitionBaseGas+in.MaxParts.Uint64()*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackRandomPartitionOutput(generateRandomPartition(stream, in.Total, in.MaxParts.Uint64()))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
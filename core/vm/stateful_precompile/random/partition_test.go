@@ -0,0 +1,47 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRandomPartition(t *testing.T) {
+	state := newMockAccessibleState()
+	tests := []struct {
+		total, maxParts int64
+	}{
+		{1, 1}, {1, 10}, {5, 5}, {10, 3}, {100, 100}, {1_000_000, 16},
+	}
+	for _, tt := range tests {
+		for nonce := uint64(0); nonce < 20; nonce++ {
+			state.state.SetNonce(testCaller, nonce)
+			parts := mustRunMethod(t, state, testCaller, "randomPartition", big.NewInt(tt.total), big.NewInt(tt.maxParts))[0].([]*big.Int)
+			if len(parts) == 0 || int64(len(parts)) > tt.maxParts {
+				t.Fatalf("total %d maxParts %d: got %d parts", tt.total, tt.maxParts, len(parts))
+			}
+			sum := new(big.Int)
+			for _, p := range parts {
+				if p.Sign() <= 0 {
+					t.Fatalf("total %d maxParts %d: non-positive part in %v", tt.total, tt.maxParts, parts)
+				}
+				sum.Add(sum, p)
+			}
+			if sum.Int64() != tt.total {
+				t.Fatalf("total %d maxParts %d: parts %v sum to %v", tt.total, tt.maxParts, parts, sum)
+			}
+		}
+	}
+}
+
+func TestRandomPartitionInvalid(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "randomPartition", big.NewInt(0), big.NewInt(3)); err != errInvalidPartition {
+		t.Errorf("got %v, want %v", err, errInvalidPartition)
+	}
+	if _, _, err := runMethod(state, testCaller, "randomPartition", big.NewInt(3), big.NewInt(0)); err != errInvalidPartition {
+		t.Errorf("got %v, want %v", err, errInvalidPartition)
+	}
+}
@@ -0,0 +1,98 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomPiecewiseBaseGas       = 1024
+	RandomPiecewisePerSegmentGas = 16
+
+	// MaxPiecewiseSegments bounds the number of segments of a randomPiecewise curve.
+	MaxPiecewiseSegments = 1024
+)
+
+var (
+	errInvalidSegmentCount = errors.New("curve must have between 1 and MaxPiecewiseSegments segments")
+	errMismatchedSegments  = errors.New("breakpoints and outputs must have the same length")
+	errUnsortedBreakpoints = errors.New("breakpoints must be strictly increasing and non-zero")
+)
+
+// RandomPiecewiseInput is the input of the randomPiecewise method.
+type RandomPiecewiseInput struct {
+	Breakpoints []*big.Int
+	Outputs     []*big.Int
+}
+
+func PackRandomPiecewiseInput(breakpoints []*big.Int, outputs []*big.Int) ([]byte, error) {
+	return randomABI.Pack("randomPiecewise", breakpoints, outputs)
+}
+
+func UnpackRandomPiecewiseInput(input []byte) (RandomPiecewiseInput, error) {
+	var in RandomPiecewiseInput
+	if err := unpackInput("randomPiecewise", input, &in); err != nil {
+		return RandomPiecewiseInput{}, err
+	}
+	if len(in.Breakpoints) == 0 || len(in.Breakpoints) > MaxPiecewiseSegments {
+		return RandomPiecewiseInput{}, errInvalidSegmentCount
+	}
+	if len(in.Breakpoints) != len(in.Outputs) {
+		return RandomPiecewiseInput{}, errMismatchedSegments
+	}
+	if in.Breakpoints[0].Sign() == 0 {
+		return RandomPiecewiseInput{}, errUnsortedBreakpoints
+	}
+	for i := 1; i < len(in.Breakpoints); i++ {
+		if in.Breakpoints[i-1].Cmp(in.Breakpoints[i]) >= 0 {
+			return RandomPiecewiseInput{}, errUnsortedBreakpoints
+		}
+	}
+	return in, nil
+}
+
+func PackRandomPiecewiseOutput(output *big.Int) ([]byte, error) {
+	return randomABI.Methods["randomPiecewise"].Outputs.Pack(output)
+}
+
+// piecewiseSegment returns the index of the segment of [breakpoints] containing [v]. Segment i
+// covers [breakpoints[i-1], breakpoints[i]), the first one starting at 0. [v] must be below
+// the last breakpoint.
+func piecewiseSegment(breakpoints []*big.Int, v *big.Int) int {
+	return sort.Search(len(breakpoints), func(i int) bool {
+		return v.Cmp(breakpoints[i]) < 0
+	})
+}
+
+// generateRandomPiecewise draws a value uniformly in [0, last breakpoint) and returns the
+// output of the segment it falls into, so every output is returned with probability
+// proportional to the width of its segment.
+func generateRandomPiecewise(stream *randomStream, breakpoints []*big.Int, outputs []*big.Int) *big.Int {
+	v := stream.uniform(breakpoints[len(breakpoints)-1])
+	return outputs[piecewiseSegment(breakpoints, v)]
+}
+
+func RandomPiecewiseFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackRandomPiecewiseInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomPiecewiseBaseGas+uint64(len(in.Breakpoints))*RandomPiecewisePerSegmentGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackRandomPiecewiseOutput(generateRandomPiecewise(stream, in.Breakpoints, in.Outputs))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
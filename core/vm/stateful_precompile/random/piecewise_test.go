@@ -0,0 +1,67 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+// bigs returns [values] as big integers.
+func bigs(values ...int64) []*big.Int {
+	out := make([]*big.Int, len(values))
+	for i, v := range values {
+		out[i] = big.NewInt(v)
+	}
+	return out
+}
+
+func TestPiecewiseSegment(t *testing.T) {
+	breakpoints := bigs(10, 20, 100)
+	for v, want := range map[int64]int{0: 0, 9: 0, 10: 1, 19: 1, 20: 2, 99: 2} {
+		if got := piecewiseSegment(breakpoints, big.NewInt(v)); got != want {
+			t.Errorf("value %d: got segment %d, want %d", v, got, want)
+		}
+	}
+}
+
+func TestRandomPiecewise(t *testing.T) {
+	state := newMockAccessibleState()
+	breakpoints, outputs := bigs(10, 20, 100), bigs(1, 5, 50)
+	segment := map[int64]int{1: 0, 5: 1, 50: 2}
+
+	counts := make([]int, len(outputs))
+	for nonce := uint64(0); nonce < 1000; nonce++ {
+		state.state.SetNonce(testCaller, nonce)
+		output := mustRunMethod(t, state, testCaller, "randomPiecewise", breakpoints, outputs)[0].(*big.Int)
+
+		v := newCallerStream(randomNCSPRNGContractAddr, testCaller, state.state).uniform(big.NewInt(100))
+		if want := outputs[piecewiseSegment(breakpoints, v)]; output.Cmp(want) != 0 {
+			t.Fatalf("nonce %d: drew %v, got output %v, want %v", nonce, v, output, want)
+		}
+		counts[segment[output.Int64()]]++
+	}
+	// The segments cover 10%, 10% and 80% of the range.
+	for i, want := range []int{100, 100, 800} {
+		if diff := counts[i] - want; diff < -60 || diff > 60 {
+			t.Errorf("segment %d: got %d draws, expected about %d", i, counts[i], want)
+		}
+	}
+}
+
+func TestRandomPiecewiseInvalidInput(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "randomPiecewise", bigs(), bigs()); err != errInvalidSegmentCount {
+		t.Errorf("empty: got %v, want %v", err, errInvalidSegmentCount)
+	}
+	if _, _, err := runMethod(state, testCaller, "randomPiecewise", bigs(1, 2), bigs(1)); err != errMismatchedSegments {
+		t.Errorf("lengths: got %v, want %v", err, errMismatchedSegments)
+	}
+	if _, _, err := runMethod(state, testCaller, "randomPiecewise", bigs(5, 5), bigs(1, 2)); err != errUnsortedBreakpoints {
+		t.Errorf("unsorted: got %v, want %v", err, errUnsortedBreakpoints)
+	}
+	if _, _, err := runMethod(state, testCaller, "randomPiecewise", bigs(0, 5), bigs(1, 2)); err != errUnsortedBreakpoints {
+		t.Errorf("zero breakpoint: got %v, want %v", err, errUnsortedBreakpoints)
+	}
+}
@@ -0,0 +1,64 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomPrioritiesBaseGas = 1024
+)
+
+// RandomPrioritiesInput is the input of the randomPriorities method.
+type RandomPrioritiesInput struct {
+	Count *big.Int
+}
+
+func PackRandomPrioritiesInput(count *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomPriorities", count)
+}
+
+func UnpackRandomPrioritiesInput(input []byte) (uint64, error) {
+	var in RandomPrioritiesInput
+	if err := unpackInput("randomPriorities", input, &in); err != nil {
+		return 0, err
+	}
+	if !in.Count.IsUint64() || in.Count.Uint64() > MaxRandomValues {
+		return 0, errTooManyValues
+	}
+	return in.Count.Uint64(), nil
+}
+
+func PackRandomPrioritiesOutput(priorities []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomPriorities"].Outputs.Pack(priorities)
+}
+
+// generateRandomPriorities assigns task i the priority priorities[i], a uniformly random
+// permutation of [0, count) so no two tasks share a priority. Lower values are meant to be
+// scheduled first, but the ordering is the consumer's to define.
+func generateRandomPriorities(stream *randomStream, count uint64) []*big.Int {
+	return stream.permutation(count)
+}
+
+func RandomPrioritiesFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	count, err := UnpackRandomPrioritiesInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomPrioritiesBaseGas+count*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackRandomPrioritiesOutput(generateRandomPriorities(stream, count))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
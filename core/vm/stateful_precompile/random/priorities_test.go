@@ -0,0 +1,26 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRandomPrioritiesPermutation(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, count := range []int64{0, 1, 2, 17, MaxRandomValues} {
+		priorities := mustRunMethod(t, state, testCaller, "randomPriorities", big.NewInt(count))[0].([]*big.Int)
+		if int64(len(priorities)) != count {
+			t.Fatalf("count %d: got %d priorities", count, len(priorities))
+		}
+		seen := make([]bool, count)
+		for _, p := range priorities {
+			if p.Int64() >= count || seen[p.Int64()] {
+				t.Fatalf("count %d: priority %v out of range or repeated", count, p)
+			}
+			seen[p.Int64()] = true
+		}
+	}
+}
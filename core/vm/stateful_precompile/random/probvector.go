@@ -0,0 +1,89 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomProbVectorBaseGas = 1024
+)
+
+var errInvalidProbVectorLength = errors.New("length must be between 1 and MaxRandomValues")
+
+// RandomProbVectorInput is the input of the randomProbVector method.
+type RandomProbVectorInput struct {
+	Length *big.Int
+	Scale  *big.Int
+}
+
+func PackRandomProbVectorInput(length *big.Int, scale *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomProbVector", length, scale)
+}
+
+func UnpackRandomProbVectorInput(input []byte) (uint64, *big.Int, error) {
+	var in RandomProbVectorInput
+	if err := unpackInput("randomProbVector", input, &in); err != nil {
+		return 0, nil, err
+	}
+	if in.Length.Sign() == 0 || !in.Length.IsUint64() || in.Length.Uint64() > MaxRandomValues {
+		return 0, nil, errInvalidProbVectorLength
+	}
+	return in.Length.Uint64(), in.Scale, nil
+}
+
+func PackRandomProbVectorOutput(probabilities []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomProbVector"].Outputs.Pack(probabilities)
+}
+
+// generateProbVector returns [length] non-negative values summing to exactly [scale]: the next
+// [length] words of [stream] normalized by their sum and rounded down, with the rounding dust
+// added to the last value. Normalized uniform draws are not uniform over the simplex, so the
+// vector suits tie-breaking and mechanism randomization rather than statistical sampling; see
+// randomDirichlet for the latter.
+func generateProbVector(stream *randomStream, length uint64, scale *big.Int) []*big.Int {
+	words := stream.values(length)
+	sum := new(big.Int)
+	for _, w := range words {
+		sum.Add(sum, w)
+	}
+	probabilities := make([]*big.Int, length)
+	remainder := new(big.Int).Set(scale)
+	for i, w := range words[:length-1] {
+		probabilities[i] = new(big.Int)
+		// The words are all zero with negligible probability, which leaves the whole scale
+		// to the last value.
+		if sum.Sign() != 0 {
+			probabilities[i].Quo(probabilities[i].Mul(w, scale), sum)
+		}
+		remainder.Sub(remainder, probabilities[i])
+	}
+	probabilities[length-1] = remainder
+	return probabilities
+}
+
+// RandomProbVectorFunc returns a random probability vector of [length] entries in units of
+// [scale], e.g. 1e18 for fixed point probabilities.
+func RandomProbVectorFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	length, scale, err := UnpackRandomProbVectorInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomProbVectorBaseGas+length*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackRandomProbVectorOutput(generateProbVector(stream, length, scale))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
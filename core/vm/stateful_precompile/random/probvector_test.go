@@ -0,0 +1,38 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+)
+
+func TestRandomProbVector(t *testing.T) {
+	state := newMockAccessibleState()
+	scales := []*big.Int{big.NewInt(1e18), big.NewInt(7), big.NewInt(0), math.MaxBig256}
+	for _, scale := range scales {
+		for _, length := range []int64{1, 2, 10, MaxRandomValues} {
+			probabilities := mustRunMethod(t, state, testCaller, "randomProbVector", big.NewInt(length), scale)[0].([]*big.Int)
+			if int64(len(probabilities)) != length {
+				t.Fatalf("length %d: got %d values", length, len(probabilities))
+			}
+			sum := new(big.Int)
+			for i, p := range probabilities {
+				if p.Sign() < 0 {
+					t.Fatalf("scale %v, length %d: value %d is negative: %v", scale, length, i, p)
+				}
+				sum.Add(sum, p)
+			}
+			if sum.Cmp(scale) != 0 {
+				t.Errorf("scale %v, length %d: values sum to %v", scale, length, sum)
+			}
+		}
+	}
+
+	if _, _, err := runMethod(state, testCaller, "randomProbVector", big.NewInt(0), big.NewInt(1e18)); err != errInvalidProbVectorLength {
+		t.Errorf("empty vector: got %v, want %v", err, errInvalidProbVectorLength)
+	}
+}
@@ -0,0 +1,70 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	ProposalRandomBaseGas = 1024
+)
+
+// ProposalRandomInput is the input of the proposalRandom method.
+type ProposalRandomInput struct {
+	ProposalId *big.Int
+	N          *big.Int
+}
+
+func PackProposalRandomInput(proposalId *big.Int, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("proposalRandom", proposalId, n)
+}
+
+func UnpackProposalRandomInput(input []byte) (*big.Int, uint64, error) {
+	var in ProposalRandomInput
+	if err := unpackInput("proposalRandom", input, &in); err != nil {
+		return nil, 0, err
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return nil, 0, errTooManyValues
+	}
+	return in.ProposalId, in.N.Uint64(), nil
+}
+
+func PackProposalRandomOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["proposalRandom"].Outputs.Pack(randomValues)
+}
+
+// newProposalStream returns the stream of proposal [proposalId] of the governance contract
+// [caller]. It is keyed by the contract and the proposal alone, so every call for the proposal
+// returns the same draw and different proposals or contracts get independent ones.
+//
+// Nothing else enters the seed, so the draw of a proposal is known as soon as its ID is. A
+// contract that lets proposers choose IDs must not rely on it for anything they could profit
+// from predicting.
+func newProposalStream(precompileAddr common.Address, caller common.Address, proposalId *big.Int) *randomStream {
+	return newKeyedStream(precompileAddr, "proposalRandom", append(caller.Bytes(), common.BigToHash(proposalId).Bytes()...))
+}
+
+// ProposalRandomFunc returns [n] values reproducibly tied to governance proposal [proposalId]
+// of the caller, e.g. to sample a random quorum.
+func ProposalRandomFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	proposalId, n, err := UnpackProposalRandomInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, ProposalRandomBaseGas+n*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	ret, err = PackProposalRandomOutput(newProposalStream(addr, caller, proposalId).values(n))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,39 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestProposalRandom(t *testing.T) {
+	state := newMockAccessibleState()
+	draw := func(caller common.Address, proposalId int64) []*big.Int {
+		t.Helper()
+		return mustRunMethod(t, state, caller, "proposalRandom", big.NewInt(proposalId), big.NewInt(4))[0].([]*big.Int)
+	}
+
+	first := draw(testCaller, 1)
+	// The draw of a proposal does not depend on the nonce or block of the call.
+	state.state.SetNonce(testCaller, 42)
+	state.blockCtx.BlockNumber = big.NewInt(1000)
+	for i, v := range draw(testCaller, 1) {
+		if v.Cmp(first[i]) != 0 {
+			t.Fatalf("value %d: got %x, want %x", i, v, first[i])
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, values := range [][]*big.Int{first, draw(testCaller, 2), draw(common.HexToAddress("0xda0"), 1)} {
+		for _, v := range values {
+			if seen[v.String()] {
+				t.Fatalf("value %x repeated across proposals", v)
+			}
+			seen[v.String()] = true
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	ProposerRandomBaseGas = 1024
+)
+
+func PackProposerRandomInput(n *big.Int) ([]byte, error) {
+	return randomABI.Pack("proposerRandom", n)
+}
+
+func UnpackProposerRandomInput(input []byte) (uint64, error) {
+	var n *big.Int
+	if err := unpackInput("proposerRandom", input, &n); err != nil {
+		return 0, err
+	}
+	if !n.IsUint64() || n.Uint64() > MaxRandomValues {
+		return 0, errTooManyValues
+	}
+	return n.Uint64(), nil
+}
+
+func PackProposerRandomOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["proposerRandom"].Outputs.Pack(randomValues)
+}
+
+// newProposerStream returns the stream of [caller] at its current nonce bound to the block
+// proposer [coinbase], whose address is appended to the user seed.
+//
+// The binding is not a source of entropy. The proposer knows its own address and can compute
+// every value before building the block, so it can choose to include, delay or drop a
+// transaction depending on the outcome, and a proposer with several addresses can pick the
+// coinbase that suits it. Use these values only where the proposer is trusted with them, such
+// as tasks assigned to the validator itself.
+func newProposerStream(precompileAddr common.Address, caller common.Address, coinbase common.Address, state contract.StateDB) *randomStream {
+	key := activeServerSeed(state, precompileAddr)
+	seed := append(userSeed(key, caller), []byte("proposer")...)
+	return newRandomStream(key, append(seed, coinbase.Bytes()...), state.GetNonce(caller))
+}
+
+func ProposerRandomFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	n, err := UnpackProposerRandomInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, ProposerRandomBaseGas+n*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	coinbase := accessibleState.GetBlockContext().Coinbase
+	stream := newProposerStream(addr, caller, coinbase, accessibleState.GetStateDB())
+	ret, err = PackProposerRandomOutput(stream.values(n))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
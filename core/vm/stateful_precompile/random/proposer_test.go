@@ -0,0 +1,30 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestProposerRandom(t *testing.T) {
+	state := newMockAccessibleState()
+	draw := func(coinbase common.Address) []*big.Int {
+		state.blockCtx.Coinbase = coinbase
+		return mustRunMethod(t, state, testCaller, "proposerRandom", big.NewInt(3))[0].([]*big.Int)
+	}
+
+	alice, bob := common.HexToAddress("0xa11ce"), common.HexToAddress("0xb0b")
+	first, again, other := draw(alice), draw(alice), draw(bob)
+	for i := range first {
+		if first[i].Cmp(again[i]) != 0 {
+			t.Errorf("value %d: same proposer yielded %x and %x", i, first[i], again[i])
+		}
+		if first[i].Cmp(other[i]) == 0 {
+			t.Errorf("value %d: proposers %v and %v yielded the same value", i, alice, bob)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+// Bits of the provenance bitmask returned by randomWithProvenance. Each one is set when the
+// corresponding input contributed to the draw.
+const (
+	// EntropySourceDefaultSeed is set when the stream is keyed by the default server seed,
+	// keccak(precompileAddr).
+	EntropySourceDefaultSeed = 1 << iota
+	// EntropySourceConfiguredSeed is set when the stream is keyed by the seed configured with
+	// WithServerSeed.
+	EntropySourceConfiguredSeed
+	// EntropySourceRevealedSeed is set when the stream is keyed by a server seed revealed
+	// through revealServerSeed.
+	EntropySourceRevealedSeed
+	// EntropySourceEpoch is set when the server seed is derived per epoch, see WithEpochLength.
+	EntropySourceEpoch
+	// EntropySourceCallerNonce is set when the stream depends on the account nonce of the caller.
+	EntropySourceCallerNonce
+	// EntropySourceFallback is set together with EntropySourceDefaultSeed when the configured
+	// seed carried no entropy and the default seed was used in its place.
+	EntropySourceFallback
+	// EntropySourcePrevRandao is set when the server seed is mixed with the PREVRANDAO value of
+	// the block, see prevRandaoServerSeed.
+	EntropySourcePrevRandao
+	// EntropySourceCallCounter is set when the stream depends on the call counter of the caller,
+	// see WithCallCounter.
+	EntropySourceCallCounter
+	// EntropySourceBlockSeed is set when the server seed is mixed with the stored block seed,
+	// see WithBlockSeed.
+	EntropySourceBlockSeed
+	// EntropySourceParentHash is set when the server seed is mixed with the hash of the parent
+	// block, see parentHashServerSeed.
+	EntropySourceParentHash
+)
+
+const (
+	RandomWithProvenanceBaseGas = 1024
+)
+
+func PackRandomWithProvenanceInput(n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomWithProvenance", n)
+}
+
+func UnpackRandomWithProvenanceInput(input []byte) (uint64, error) {
+	var n *big.Int
+	if err := unpackInput("randomWithProvenance", input, &n); err != nil {
+		return 0, err
+	}
+	if !n.IsUint64() || n.Uint64() > MaxRandomValues {
+		return 0, errTooManyValues
+	}
+	return n.Uint64(), nil
+}
+
+func PackRandomWithProvenanceOutput(randomValues []*big.Int, sources uint64) ([]byte, error) {
+	return randomABI.Methods["randomWithProvenance"].Outputs.Pack(randomValues, new(big.Int).SetUint64(sources))
+}
+
+// newRandomWithProvenanceFunc returns the randomWithProvenance handler of a precompile built
+// with [o]. It returns the same values as randomNCSPRNG along with the bitmask of the entropy
+// sources they were derived from.
+func newRandomWithProvenanceFunc(o options) contract.RunStatefulPrecompileFunc {
+	return func(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		n, err := UnpackRandomWithProvenanceInput(input)
+		if err != nil {
+			return nil, suppliedGas, err
+		}
+		if remainingGas, err = contract.DeductGas(suppliedGas, RandomWithProvenanceBaseGas+n*RandomPerValueGas); err != nil {
+			return nil, 0, err
+		}
+
+		state := accessibleState.GetStateDB()
+		blockCtx := accessibleState.GetBlockContext()
+		blockNumber := blockCtx.BlockNumber.Uint64()
+		stream, sources, err := newRandomNCSPRNGStream(addr, caller, blockCtx, o, state)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		if sources&EntropySourceFallback != 0 {
+			if remainingGas, err = reportEntropyFallback(state, addr, caller, blockNumber, readOnly, remainingGas); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		ret, err = PackRandomWithProvenanceOutput(stream.values(n), sources)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+
+		return ret, remainingGas, nil
+	}
+}
@@ -0,0 +1,77 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRandomWithProvenance(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		revealed   bool
+		prevRandao bool
+		want       uint64
+	}{
+		{"default", nil, false, false, EntropySourceDefaultSeed | EntropySourceCallerNonce},
+		{"configured", []Option{WithServerSeed(common.HexToHash("0x5eed"))}, false, false, EntropySourceConfiguredSeed | EntropySourceCallerNonce},
+		{"fallback", []Option{WithServerSeed(common.Hash{})}, false, false, EntropySourceDefaultSeed | EntropySourceFallback | EntropySourceCallerNonce},
+		{"revealed", []Option{WithServerSeed(common.HexToHash("0x5eed"))}, true, false, EntropySourceRevealedSeed | EntropySourceCallerNonce},
+		{"epoch", []Option{WithEpochLength(10)}, false, false, EntropySourceDefaultSeed | EntropySourceEpoch | EntropySourceCallerNonce},
+		{"prevrandao", nil, false, true, EntropySourceDefaultSeed | EntropySourceCallerNonce | EntropySourcePrevRandao},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := newMockAccessibleState()
+			if tt.revealed {
+				state.state.SetState(randomNCSPRNGContractAddr, serverSeedActiveKey, common.HexToHash("0x1234"))
+			}
+			if tt.prevRandao {
+				random := common.HexToHash("0xabcd")
+				state.blockCtx.Random = &random
+			}
+			precompile := CreateRandomNCSPRNGPrecompile(DefaultConfig(), tt.opts...)
+
+			input, err := PackRandomWithProvenanceInput(big.NewInt(3))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ret, _, err := precompile.Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err := randomABI.Methods["randomWithProvenance"].Outputs.Unpack(ret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			values, sources := out[0].([]*big.Int), out[1].(*big.Int)
+			if sources.Uint64() != tt.want {
+				t.Errorf("got sources %b, want %b", sources, tt.want)
+			}
+
+			// The values are the ones randomNCSPRNG returns under the same configuration.
+			input, err = PackRandomNCSPRNGInput(big.NewInt(3))
+			if err != nil {
+				t.Fatal(err)
+			}
+			ret, _, err = precompile.Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err = randomABI.Methods["randomNCSPRNG"].Outputs.Unpack(ret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, want := range out[0].([]*big.Int) {
+				if values[i].Cmp(want) != 0 {
+					t.Errorf("value %d: got %x, want %x", i, values[i], want)
+				}
+			}
+		})
+	}
+}
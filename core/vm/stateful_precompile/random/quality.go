@@ -0,0 +1,91 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomQualityBaseGas = 1024
+	// RandomQualityPerRoundGas is charged for every HMAC round of every value.
+	RandomQualityPerRoundGas = RandomPerValueGas
+
+	// MaxQualityRounds bounds the number of HMAC rounds per value of randomQuality.
+	MaxQualityRounds = 64
+)
+
+var errInvalidRounds = errors.New("rounds must be between 1 and MaxQualityRounds")
+
+// RandomQualityInput is the input of the randomQuality method.
+type RandomQualityInput struct {
+	Rounds *big.Int
+	N      *big.Int
+}
+
+func PackRandomQualityInput(rounds *big.Int, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomQuality", rounds, n)
+}
+
+func UnpackRandomQualityInput(input []byte) (uint64, uint64, error) {
+	var in RandomQualityInput
+	if err := unpackInput("randomQuality", input, &in); err != nil {
+		return 0, 0, err
+	}
+	if in.Rounds.Sign() == 0 || !in.Rounds.IsUint64() || in.Rounds.Uint64() > MaxQualityRounds {
+		return 0, 0, errInvalidRounds
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return 0, 0, errTooManyValues
+	}
+	return in.Rounds.Uint64(), in.N.Uint64(), nil
+}
+
+func PackRandomQualityOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomQuality"].Outputs.Pack(randomValues)
+}
+
+// remix returns HMAC(serverSeed, word) under the key of the stream.
+func (s *randomStream) remix(word []byte) []byte {
+	s.mac.Reset()
+	s.mac.Write(word)
+	return s.mac.Sum(nil)
+}
+
+// generateRandomQuality returns [n] values that each go through [rounds] HMAC rounds: the first
+// is the stream word itself, every further round rehashes the previous output under the server
+// seed. With a single round the values are the plain stream words.
+func generateRandomQuality(stream *randomStream, rounds uint64, n uint64) []*big.Int {
+	values := make([]*big.Int, n)
+	for i := range values {
+		word := stream.nextBytes()
+		for r := uint64(1); r < rounds; r++ {
+			word = stream.remix(word)
+		}
+		values[i] = new(big.Int).SetBytes(word)
+	}
+	return values
+}
+
+func RandomQualityFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	rounds, n, err := UnpackRandomQualityInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomQualityBaseGas+rounds*n*RandomQualityPerRoundGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackRandomQualityOutput(generateRandomQuality(stream, rounds, n))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
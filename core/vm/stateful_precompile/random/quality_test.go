@@ -0,0 +1,55 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRandomQualityGas(t *testing.T) {
+	const n = 10
+	state := newMockAccessibleState()
+
+	used := make(map[int64]uint64)
+	outputs := make(map[int64][]*big.Int)
+	for _, rounds := range []int64{1, 4} {
+		out, remaining, err := runMethod(state, testCaller, "randomQuality", big.NewInt(rounds), big.NewInt(n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		used[rounds] = testGas - remaining - RandomQualityBaseGas
+		outputs[rounds] = out[0].([]*big.Int)
+		if len(outputs[rounds]) != n {
+			t.Fatalf("rounds %d: got %d values, want %d", rounds, len(outputs[rounds]), n)
+		}
+		for _, v := range outputs[rounds] {
+			if v.Sign() < 0 || v.BitLen() > 256 {
+				t.Fatalf("rounds %d: value %x out of range", rounds, v)
+			}
+		}
+	}
+	if used[4] != 4*used[1] {
+		t.Errorf("4 rounds used %d gas per call, want 4 times the %d of a single round", used[4], used[1])
+	}
+
+	stream := newCallerStream(randomNCSPRNGContractAddr, testCaller, state.state)
+	for i := range outputs[1] {
+		if want := stream.next(); outputs[1][i].Cmp(want) != 0 {
+			t.Errorf("single round value %d: got %x, want the stream word %x", i, outputs[1][i], want)
+		}
+		if outputs[1][i].Cmp(outputs[4][i]) == 0 {
+			t.Errorf("value %d unchanged by extra rounds", i)
+		}
+	}
+}
+
+func TestRandomQualityInvalidRounds(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, rounds := range []int64{0, MaxQualityRounds + 1} {
+		if _, _, err := runMethod(state, testCaller, "randomQuality", big.NewInt(rounds), big.NewInt(1)); err != errInvalidRounds {
+			t.Errorf("rounds %d: got %v, want %v", rounds, err, errInvalidRounds)
+		}
+	}
+}
@@ -4,32 +4,1993 @@
 package random
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
+	"encoding/binary"
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/holiman/uint256"
 )
 
 const (
-	RandomNCSPRNGGasCost = 1024
+	// RandomNCSPRNGBaseGas and RandomNCSPRNGPerValueGas make up the default cost of
+	// randomNCSPRNG, which hashes once for every value it returns, see Config.
+	RandomNCSPRNGBaseGas     = 1024
+	RandomNCSPRNGPerValueGas = 64
+	// MaxRandomNCSPRNGValues is the default bound on the number of values randomNCSPRNG
+	// returns in a single call, so the size of the output is capped independently of the
+	// supplied gas.
+	MaxRandomNCSPRNGValues = 1 << 16
+
+	// RandomPerValueGas is charged for every value drawn by the methods returning a
+	// caller-sized array of values.
+	RandomPerValueGas = 64
+	// MaxRandomValues bounds the number of values those methods return in a single call.
+	MaxRandomValues = 1024
 )
 
 var (
 	errInvalidInputLength = errors.New("invalid input length")
+	errTooManyValues      = errors.New("too many random values requested")
 	randomNCSPRNGABI      = `[
 	  {
 		"type": "function",
-		"name": "randomNCSPRNG",
+		"name": "randomNCSPRNG",
+		"inputs": [
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "randomNCSPRNG",
+		"inputs": [
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "salt",
+			"type": "bytes32",
+			"internalType": "bytes32"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "randomMultiple",
+		"inputs": [
+		  {
+			"name": "maxMultiples",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "tick",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValue",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomGraph",
+		"inputs": [
+		  {
+			"name": "nodes",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "edgeProbBps",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "sources",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  },
+		  {
+			"name": "targets",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomMerkleRoot",
+		"inputs": [
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "root",
+			"type": "bytes32",
+			"internalType": "bytes32"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "proveValue",
+		"inputs": [
+		  {
+			"name": "index",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "value",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "proof",
+			"type": "bytes32[]",
+			"internalType": "bytes32[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "dealCards",
+		"inputs": [
+		  {
+			"name": "numCards",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "cards",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "tokenRandom",
+		"inputs": [
+		  {
+			"name": "tokenId",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "requestDelayedRandom",
+		"inputs": [
+		  {
+			"name": "delay",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "commitment",
+			"type": "bytes32",
+			"internalType": "bytes32"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "revealDelayedRandom",
+		"inputs": [],
+		"outputs": [
+		  {
+			"name": "randomValue",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "seed",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "randomWalk",
+		"inputs": [
+		  {
+			"name": "steps",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "stepSize",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "start",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "min",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "max",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "positions",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "multiPartyRandom",
+		"inputs": [
+		  {
+			"name": "contributors",
+			"type": "address[]",
+			"internalType": "address[]"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "timestampedRandom",
+		"inputs": [
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  },
+		  {
+			"name": "timestamp",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomPartition",
+		"inputs": [
+		  {
+			"name": "total",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "maxParts",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "parts",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomByTxIndex",
+		"inputs": [
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "antiClustered",
+		"inputs": [
+		  {
+			"name": "max",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "memory",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "randomAffine",
+		"inputs": [],
+		"outputs": [
+		  {
+			"name": "a",
+			"type": "int256",
+			"internalType": "int256"
+		  },
+		  {
+			"name": "b",
+			"type": "int256",
+			"internalType": "int256"
+		  },
+		  {
+			"name": "c",
+			"type": "int256",
+			"internalType": "int256"
+		  },
+		  {
+			"name": "d",
+			"type": "int256",
+			"internalType": "int256"
+		  },
+		  {
+			"name": "e",
+			"type": "int256",
+			"internalType": "int256"
+		  },
+		  {
+			"name": "f",
+			"type": "int256",
+			"internalType": "int256"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomModWithStats",
+		"inputs": [
+		  {
+			"name": "modulus",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  },
+		  {
+			"name": "rejections",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "pickWithCooldown",
+		"inputs": [
+		  {
+			"name": "numOptions",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "cooldownBlocks",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "option",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "commitServerSeed",
+		"inputs": [
+		  {
+			"name": "commitment",
+			"type": "bytes32",
+			"internalType": "bytes32"
+		  }
+		],
+		"outputs": [],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "revealServerSeed",
+		"inputs": [
+		  {
+			"name": "seed",
+			"type": "bytes32",
+			"internalType": "bytes32"
+		  }
+		],
+		"outputs": [],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "randomBounded",
+		"inputs": [
+		  {
+			"name": "bound",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "distinct",
+			"type": "bool",
+			"internalType": "bool"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomPriorities",
+		"inputs": [
+		  {
+			"name": "count",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "priorities",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomFromLogs",
+		"inputs": [
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "signedRandom",
+		"inputs": [
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  },
+		  {
+			"name": "signature",
+			"type": "bytes",
+			"internalType": "bytes"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomCapacitatedGraph",
+		"inputs": [
+		  {
+			"name": "nodes",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "edgeProbBps",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "maxCapacity",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "sources",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  },
+		  {
+			"name": "targets",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  },
+		  {
+			"name": "capacities",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "abBucket",
+		"inputs": [
+		  {
+			"name": "user",
+			"type": "address",
+			"internalType": "address"
+		  },
+		  {
+			"name": "numBuckets",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "bucket",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomMixture",
+		"inputs": [
+		  {
+			"name": "mixProbBps",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "minA",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "maxA",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "minB",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "maxB",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomTarget",
+		"inputs": [
+		  {
+			"name": "difficultyBits",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "target",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomWithAlgo",
+		"inputs": [
+		  {
+			"name": "algoId",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  },
+		  {
+			"name": "algoId",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "delayRandom",
+		"inputs": [
+		  {
+			"name": "input",
+			"type": "bytes32",
+			"internalType": "bytes32"
+		  },
+		  {
+			"name": "iterations",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "output",
+			"type": "bytes32",
+			"internalType": "bytes32"
+		  },
+		  {
+			"name": "randomValue",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomPiecewise",
+		"inputs": [
+		  {
+			"name": "breakpoints",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  },
+		  {
+			"name": "outputs",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "output",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "event",
+		"name": "EntropyFallback",
+		"inputs": [
+		  {
+			"name": "caller",
+			"type": "address",
+			"indexed": true,
+			"internalType": "address"
+		  }
+		],
+		"anonymous": false
+	  },
+	  {
+		"type": "event",
+		"name": "BlockEntropyAudit",
+		"inputs": [
+		  {
+			"name": "blockNumber",
+			"type": "uint256",
+			"indexed": true,
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "serverSeedHash",
+			"type": "bytes32",
+			"indexed": false,
+			"internalType": "bytes32"
+		  },
+		  {
+			"name": "sources",
+			"type": "uint256",
+			"indexed": false,
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "commitment",
+			"type": "bytes32",
+			"indexed": false,
+			"internalType": "bytes32"
+		  }
+		],
+		"anonymous": false
+	  },
+	  {
+		"type": "event",
+		"name": "RandomnessRequested",
+		"inputs": [
+		  {
+			"name": "caller",
+			"type": "address",
+			"indexed": true,
+			"internalType": "address"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"indexed": false,
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "nonce",
+			"type": "uint256",
+			"indexed": false,
+			"internalType": "uint256"
+		  }
+		],
+		"anonymous": false
+	  },
+	  {
+		"type": "function",
+		"name": "stockItems",
+		"inputs": [
+		  {
+			"name": "poolId",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "weights",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"outputs": [],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "drawAndDeplete",
+		"inputs": [
+		  {
+			"name": "poolId",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "itemId",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "randomWithDomain",
+		"inputs": [
+		  {
+			"name": "domainTag",
+			"type": "bytes32",
+			"internalType": "bytes32"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "getCommitment",
+		"inputs": [],
+		"outputs": [
+		  {
+			"name": "commitment",
+			"type": "bytes32",
+			"internalType": "bytes32"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomAboveThreshold",
+		"inputs": [
+		  {
+			"name": "threshold",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "max",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomWithProvenance",
+		"inputs": [
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  },
+		  {
+			"name": "sources",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "randomBracket",
+		"inputs": [
+		  {
+			"name": "numPlayers",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "bracket",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomQuality",
+		"inputs": [
+		  {
+			"name": "rounds",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomSpanningTree",
+		"inputs": [
+		  {
+			"name": "nodes",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "sources",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  },
+		  {
+			"name": "targets",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomFromCounter",
+		"inputs": [
+		  {
+			"name": "startCounter",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "randomBoundedSum",
+		"inputs": [
+		  {
+			"name": "cap",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "k",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "sortedMerkleRoot",
+		"inputs": [],
+		"outputs": [
+		  {
+			"name": "root",
+			"type": "bytes32",
+			"internalType": "bytes32"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "proveAbsence",
+		"inputs": [
+		  {
+			"name": "value",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "count",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "position",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "low",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "lowProof",
+			"type": "bytes32[]",
+			"internalType": "bytes32[]"
+		  },
+		  {
+			"name": "high",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "highProof",
+			"type": "bytes32[]",
+			"internalType": "bytes32[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "uniquePerTx",
+		"inputs": [
+		  {
+			"name": "purposeTag",
+			"type": "bytes32",
+			"internalType": "bytes32"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "value",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "rollNotation",
+		"inputs": [
+		  {
+			"name": "expr",
+			"type": "bytes",
+			"internalType": "bytes"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "total",
+			"type": "int256",
+			"internalType": "int256"
+		  },
+		  {
+			"name": "rolls",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "feeJitter",
+		"inputs": [
+		  {
+			"name": "baseFee",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "maxJitterBps",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "fee",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "packedSmall",
+		"inputs": [
+		  {
+			"name": "count",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "bitsEach",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "packed",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "gachaPull",
+		"inputs": [
+		  {
+			"name": "baseRateBps",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "pityIncrementBps",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "hardPity",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "hit",
+			"type": "bool",
+			"internalType": "bool"
+		  },
+		  {
+			"name": "missStreak",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "randomDirichlet",
+		"inputs": [
+		  {
+			"name": "alphasScaled",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "weights",
+			"type": "uint256[][]",
+			"internalType": "uint256[][]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "proposerRandom",
+		"inputs": [
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomQR",
+		"inputs": [
+		  {
+			"name": "prime",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "residue",
+			"type": "bool",
+			"internalType": "bool"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomInRange",
+		"inputs": [
+		  {
+			"name": "min",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "max",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "randomCoprime",
+		"inputs": [
+		  {
+			"name": "modulus",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "count",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomRaw",
+		"inputs": [
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "randomOne",
+		"inputs": [],
+		"outputs": [
+		  {
+			"name": "randomValue",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "randomMaze",
+		"inputs": [
+		  {
+			"name": "width",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "height",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "walls",
+			"type": "bytes",
+			"internalType": "bytes"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomWithSelfCheck",
+		"inputs": [
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  },
+		  {
+			"name": "passed",
+			"type": "bool",
+			"internalType": "bool"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "drawWinners",
+		"inputs": [
+		  {
+			"name": "numEntrants",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "tierCounts",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "winners",
+			"type": "uint256[][]",
+			"internalType": "uint256[][]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "publishCommitmentRoot",
+		"inputs": [
+		  {
+			"name": "root",
+			"type": "bytes32",
+			"internalType": "bytes32"
+		  }
+		],
+		"outputs": [],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "drawFromLeaf",
+		"inputs": [
+		  {
+			"name": "index",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "leaf",
+			"type": "bytes32",
+			"internalType": "bytes32"
+		  },
+		  {
+			"name": "proof",
+			"type": "bytes32[]",
+			"internalType": "bytes32[]"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "shuffle",
+		"inputs": [
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "permutation",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "rateLimitedRandom",
+		"inputs": [
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "minBlockGap",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "proposalRandom",
+		"inputs": [
+		  {
+			"name": "proposalId",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomBytes",
+		"inputs": [
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomWords",
+			"type": "bytes32[]",
+			"internalType": "bytes32[]"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "cappedWeightedPick",
+		"inputs": [
+		  {
+			"name": "weights",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  },
+		  {
+			"name": "maxWeight",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "picks",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "commitRandomness",
+		"inputs": [
+		  {
+			"name": "commitment",
+			"type": "bytes32",
+			"internalType": "bytes32"
+		  }
+		],
+		"outputs": [],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "revealRandomness",
+		"inputs": [
+		  {
+			"name": "secret",
+			"type": "bytes32",
+			"internalType": "bytes32"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValue",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "verifyBeaconAndDraw",
+		"inputs": [
+		  {
+			"name": "beaconRound",
+			"type": "bytes",
+			"internalType": "bytes"
+		  },
+		  {
+			"name": "blsSignature",
+			"type": "bytes",
+			"internalType": "bytes"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "shuffledRandom",
+		"inputs": [
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "commitBoard",
+		"inputs": [
+		  {
+			"name": "boardCommitment",
+			"type": "bytes32",
+			"internalType": "bytes32"
+		  }
+		],
+		"outputs": [],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "revealCell",
+		"inputs": [
+		  {
+			"name": "cellIndex",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "value",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "salt",
+			"type": "bytes32",
+			"internalType": "bytes32"
+		  },
+		  {
+			"name": "proof",
+			"type": "bytes32[]",
+			"internalType": "bytes32[]"
+		  }
+		],
+		"outputs": [],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomProbVector",
+		"inputs": [
+		  {
+			"name": "length",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "scale",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "probabilities",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomFromMessage",
+		"inputs": [
+		  {
+			"name": "source",
+			"type": "address",
+			"internalType": "address"
+		  },
+		  {
+			"name": "index",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomWithLineage",
+		"inputs": [
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  },
+		  {
+			"name": "seedLineage",
+			"type": "bytes32",
+			"internalType": "bytes32"
+		  }
+		],
+		"stateMutability": "nonpayable"
+	  },
+	  {
+		"type": "function",
+		"name": "weightedPick",
+		"inputs": [
+		  {
+			"name": "weights",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "picks",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "interleavedStreams",
+		"inputs": [
+		  {
+			"name": "numStreams",
+			"type": "uint256",
+			"internalType": "uint256"
+		  },
+		  {
+			"name": "perStream",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "fairGame",
+		"inputs": [
+		  {
+			"name": "opponent",
+			"type": "address",
+			"internalType": "address"
+		  },
+		  {
+			"name": "n",
+			"type": "uint256",
+			"internalType": "uint256"
+		  }
+		],
+		"outputs": [
+		  {
+			"name": "randomValues",
+			"type": "uint256[]",
+			"internalType": "uint256[]"
+		  }
+		],
+		"stateMutability": "view"
+	  },
+	  {
+		"type": "function",
+		"name": "randomWithDropout",
 		"inputs": [
 		  {
 			"name": "n",
 			"type": "uint256",
 			"internalType": "uint256"
+		  },
+		  {
+			"name": "dropProbBps",
+			"type": "uint256",
+			"internalType": "uint256"
 		  }
 		],
 		"outputs": [
@@ -37,87 +1998,377 @@ var (
 			"name": "randomValues",
 			"type": "uint256[]",
 			"internalType": "uint256[]"
+		  },
+		  {
+			"name": "dropped",
+			"type": "bool[]",
+			"internalType": "bool[]"
 		  }
 		],
 		"stateMutability": "view"
 	  }
 	]`
+
+	// randomABI is the parsed form of randomNCSPRNGABI shared by all methods of the precompile.
+	randomABI = contract.ParseABI(randomNCSPRNGABI)
 )
 
 var randomNCSPRNGContractAddr = common.HexToAddress("0x6942000000000000000000000000000000000000")
 
 func PackRandomNCSPRNGInput(n *big.Int) ([]byte, error) {
-	abi := contract.ParseABI(randomNCSPRNGABI)
-	return abi.Pack("randomNCSPRNG", n)
+	return randomABI.Pack("randomNCSPRNG", n)
 }
 
-func UnpackRandomNCSPRNGInput(input []byte) (*big.Int, error) {
-	if len(input) != 32 {
-		return nil, errInvalidInputLength
-	}
-	return new(big.Int).SetBytes(input), nil
+// PackSaltedRandomNCSPRNGInput packs a call to the randomNCSPRNG(uint256,bytes32) overload,
+// which the ABI names randomNCSPRNG0.
+func PackSaltedRandomNCSPRNGInput(n *big.Int, salt common.Hash) ([]byte, error) {
+	return randomABI.Pack("randomNCSPRNG0", n, [32]byte(salt))
 }
 
-func PackRandomNCSPRNGOutput(randomValues []*big.Int) ([]byte, error) {
-	abi := contract.ParseABI(randomNCSPRNGABI)
-	return abi.Methods["randomNCSPRNG"].Outputs.Pack(randomValues)
-}
-
-func generateRandomNCSPRNG(precompileAddr common.Address, userAddr common.Address, n uint256.Int, state contract.StateDB) ([]*big.Int, error) {
-	serverSeed := crypto.Keccak256(precompileAddr.Bytes())
-	userSeed := crypto.Keccak256(append(userAddr.Bytes(), serverSeed...))
-	nonce := state.GetNonce(userAddr)
-
-	randomValues := make([]*big.Int, n.Uint64())
-	hmac := hmac.New(sha256.New, serverSeed)
-	for i := uint64(0); i < n.Uint64(); i++ {
-		hmac.Reset()
-		hmac.Write(userSeed)
-		hmac.Write(common.BigToHash(new(big.Int).SetUint64(nonce)).Bytes())
-		hmac.Write(common.BigToHash(new(big.Int).SetUint64(i)).Bytes())
-		hash := hmac.Sum(nil)
-		randomValues[i] = new(big.Int).SetBytes(hash)
+// UnpackRandomNCSPRNGInput returns the number of values and the salt of a randomNCSPRNG call.
+// Both overloads share the handler and are told apart by the length of their input: 32 bytes
+// for the legacy randomNCSPRNG(uint256), whose salt is zero, and 64 for the salted one.
+func UnpackRandomNCSPRNGInput(input []byte) (*big.Int, common.Hash, error) {
+	switch len(input) {
+	case common.HashLength:
+		return new(big.Int).SetBytes(input), common.Hash{}, nil
+	case 2 * common.HashLength:
+		return new(big.Int).SetBytes(input[:common.HashLength]), common.BytesToHash(input[common.HashLength:]), nil
+	default:
+		return nil, common.Hash{}, errInvalidInputLength
 	}
+}
 
-	return randomValues, nil
+func PackRandomNCSPRNGOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomNCSPRNG"].Outputs.Pack(randomValues)
 }
 
-func RandomNCSPRNGFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
-	if remainingGas, err = contract.DeductGas(suppliedGas, RandomNCSPRNGGasCost); err != nil {
-		return nil, 0, err
+// UnpackRandomNCSPRNGOutput decodes the values returned by randomNCSPRNG, so that Go clients
+// need not decode the uint256[] by hand. It fails on data that is not a well-formed encoding.
+func UnpackRandomNCSPRNGOutput(data []byte) ([]*big.Int, error) {
+	var values []*big.Int
+	if err := randomABI.UnpackIntoInterface(&values, "randomNCSPRNG", data); err != nil {
+		return nil, err
 	}
+	return values, nil
+}
 
-	n, err := UnpackRandomNCSPRNGInput(input)
+// unpackInput decodes the ABI-encoded arguments of [method] into the struct pointed to by [v].
+func unpackInput(method string, input []byte, v interface{}) error {
+	args := randomABI.Methods[method].Inputs
+	values, err := args.Unpack(input)
 	if err != nil {
-		return nil, remainingGas, err
+		return err
+	}
+	return args.Copy(v, values)
+}
+
+// newRandomNCSPRNGStream returns the stream randomNCSPRNG draws the values of [userAddr] from at
+// the block of [blockCtx], keyed by the server seed selected by [o], with the counter byte order
+// and HMAC hash of [o] and past its first o.warmupDiscard words, whitened if [o] enables it.
+// It also returns the EntropySource bits of every input the stream is derived from, or
+// errInsufficientEntropy if they hold fewer independent sources than [o] requires.
+func newRandomNCSPRNGStream(precompileAddr common.Address, userAddr common.Address, blockCtx *vm.BlockContext, o options, state contract.StateDB) (*randomStream, uint64, error) {
+	return newSaltedRandomNCSPRNGStream(precompileAddr, userAddr, common.Hash{}, blockCtx, o, state)
+}
+
+// newSaltedRandomNCSPRNGStream is like newRandomNCSPRNGStream, but separates the stream by the
+// caller-supplied [salt], see saltedUserSeed.
+func newSaltedRandomNCSPRNGStream(precompileAddr common.Address, userAddr common.Address, salt common.Hash, blockCtx *vm.BlockContext, o options, state contract.StateDB) (*randomStream, uint64, error) {
+	key, sources := ncsprngServerSeed(state, precompileAddr, blockCtx, o)
+	if independentEntropySources(sources) < o.minEntropySources {
+		return nil, sources, errInsufficientEntropy
 	}
+	key, counterSources := callCounterServerSeed(state, precompileAddr, userAddr, key, o)
+	stream := newSeededNCSPRNGStream(key, userAddr, salt, state.GetNonce(userAddr), o.warmupDiscard, o.counterByteOrder, o.hmacHash)
+	if o.whitening {
+		stream.whiten()
+	}
+	return stream, sources | counterSources | EntropySourceCallerNonce, nil
+}
 
-	nUint256, overflow := uint256.FromBig(n)
-	if overflow {
-		return nil, remainingGas, errors.New("n overflows uint256")
+// saltedUserSeed returns the user seed [seed] separated by [salt]. Contracts called in the same
+// transaction share the account nonce of its sender, so without a salt they would all receive
+// the same stream; appending the salt to the HMAC input gives every logical consumer its own.
+// A zero salt leaves the seed unchanged, so unsalted calls keep their values.
+func saltedUserSeed(seed []byte, salt common.Hash) []byte {
+	if salt == (common.Hash{}) {
+		return seed
 	}
+	return append(seed, salt.Bytes()...)
+}
+
+// newSeededNCSPRNGStream returns the randomNCSPRNG stream of [userAddr] at [nonce] separated by
+// [salt] once the server seed [key] is known, with its counter in [order], its HMAC over [h]
+// and past its first [warmup] words.
+// It is the part of the derivation that needs no chain state, shared by the precompile and by
+// the off-chain verifiers so that they cannot drift apart.
+func newSeededNCSPRNGStream(key []byte, userAddr common.Address, salt common.Hash, nonce uint64, warmup uint, order ByteOrder, h HMACHash) *randomStream {
+	stream := newRandomStreamWithHash(key, saltedUserSeed(userSeed(key, userAddr), salt), nonce, h)
+	stream.counterOrder = order
+	stream.skip(uint64(warmup))
+	return stream
+}
+
+// VerifyRandomNCSPRNG recomputes the [n] values randomNCSPRNG returned to [userAddr] when its
+// account nonce was [nonce], so that anyone can check a draw off-chain without a StateDB. It
+// covers a precompile at [precompileAddr] built without options, keyed by its default server
+// seed in a block without parent hash or PREVRANDAO. Draws made under any other configuration
+// are recomputed from a RandomnessWitness instead.
+func VerifyRandomNCSPRNG(precompileAddr common.Address, userAddr common.Address, nonce uint64, n uint64) []*big.Int {
+	return newSeededNCSPRNGStream(serverSeed(precompileAddr), userAddr, common.Hash{}, nonce, 0, BigEndian, SHA256).values(n)
+}
 
-	randomValues, err := generateRandomNCSPRNG(addr, caller, *nUint256, accessibleState.GetStateDB())
+// generateRandomNCSPRNG returns the [n] values randomNCSPRNG returns to [userAddr] for [salt]
+// in the block of [blockCtx].
+func generateRandomNCSPRNG(precompileAddr common.Address, userAddr common.Address, n uint256.Int, salt common.Hash, blockCtx *vm.BlockContext, o options, state contract.StateDB) ([]*big.Int, error) {
+	stream, _, err := newSaltedRandomNCSPRNGStream(precompileAddr, userAddr, salt, blockCtx, o, state)
 	if err != nil {
-		return nil, remainingGas, err
+		return nil, err
 	}
+	return stream.values(n.Uint64()), nil
+}
 
-	ret, err = PackRandomNCSPRNGOutput(randomValues)
-	if err != nil {
-		return nil, remainingGas, err
+// encodeRandomNCSPRNGOutput ABI-encodes the next [n] words of [stream] as the uint256[] output
+// of randomNCSPRNG. Every word is written straight into the encoding, so no []*big.Int is
+// materialized next to it and peak memory stays at the size of the output. The result is
+// identical to PackRandomNCSPRNGOutput of the same words. [stream] is not used when [n] is 0.
+func encodeRandomNCSPRNGOutput(stream *randomStream, n uint64) []byte {
+	ret := make([]byte, (2+n)*common.HashLength)
+	// Head: the offset of the array, which directly follows it, then its length.
+	ret[common.HashLength-1] = common.HashLength
+	binary.BigEndian.PutUint64(ret[2*common.HashLength-8:], n)
+	for i := uint64(0); i < n; i++ {
+		stream.fill(ret[(2+i)*common.HashLength : (3+i)*common.HashLength])
 	}
+	return ret
+}
+
+// RandomNCSPRNGFunc is the randomNCSPRNG handler of a precompile built with the default
+// configuration and without options.
+var RandomNCSPRNGFunc = newRandomNCSPRNGFunc(DefaultConfig(), options{})
+
+// newRandomNCSPRNGFunc returns the randomNCSPRNG handler of a precompile built with [cfg] and
+// [o].
+// randomNCSPRNG is a view method, so a read-only call must leave the state untouched. The
+// bookkeeping of a draw (rolling commitment, used nonces, block seed rotation and the
+// RandomnessRequested, block audit and fallback events) is skipped in such calls, as the values do not depend on it,
+// while a configuration whose values do depend on a write, see WithCallCounter, fails with
+// vm.ErrWriteProtection instead.
+func newRandomNCSPRNGFunc(cfg Config, o options) contract.RunStatefulPrecompileFunc {
+	return func(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		n, salt, err := UnpackRandomNCSPRNGInput(input)
+		if err != nil {
+			return nil, suppliedGas, err
+		}
 
-	return ret, remainingGas, nil
+		nUint256, overflow := uint256.FromBig(n)
+		if overflow {
+			return nil, suppliedGas, errors.New("n overflows uint256")
+		}
+
+		if !n.IsUint64() || n.Uint64() > cfg.MaxValues {
+			return nil, suppliedGas, errTooManyValues
+		}
+
+		// Charge for every value before hashing any, so a huge n runs out of gas up front.
+		gas, ok := cfg.drawGas(n.Uint64())
+		if !ok {
+			return nil, 0, vm.ErrOutOfGas
+		}
+		if remainingGas, err = contract.DeductGas(suppliedGas, gas); err != nil {
+			return nil, 0, err
+		}
+
+		// A draw of no values needs no stream: return the empty array for the base gas alone,
+		// without deriving a seed or doing any bookkeeping.
+		if n.Sign() == 0 {
+			return encodeRandomNCSPRNGOutput(nil, 0), remainingGas, nil
+		}
+
+		if !readOnly {
+			if remainingGas, err = contract.DeductGas(remainingGas, RollingCommitmentGasCost+RandomnessRequestedGasCost); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		if o.callCounter {
+			if readOnly {
+				return nil, remainingGas, vm.ErrWriteProtection
+			}
+			if remainingGas, err = contract.DeductGas(remainingGas, CallCounterGasCost); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		state := accessibleState.GetStateDB()
+		if o.detectNonceReuse {
+			if remainingGas, err = contract.DeductGas(remainingGas, NonceReuseGasCost); err != nil {
+				return nil, 0, err
+			}
+			if err := checkNonceReuse(state, addr, caller, state.GetNonce(caller), readOnly); err != nil {
+				return nil, remainingGas, err
+			}
+		}
+
+		blockCtx := accessibleState.GetBlockContext()
+		blockNumber := blockCtx.BlockNumber.Uint64()
+		if o.blockAuditLog && !readOnly {
+			if remainingGas, err = contract.DeductGas(remainingGas, BlockAuditGasCost); err != nil {
+				return nil, 0, err
+			}
+			if err := auditBlockEntropy(state, addr, blockCtx, o); err != nil {
+				return nil, remainingGas, err
+			}
+		}
+		if o.blockSeed && !readOnly {
+			if remainingGas, err = contract.DeductGas(remainingGas, BlockSeedGasCost); err != nil {
+				return nil, 0, err
+			}
+			rotateBlockSeed(state, addr, blockNumber)
+		}
+		stream, sources, err := newSaltedRandomNCSPRNGStream(addr, caller, salt, blockCtx, o, state)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		if sources&EntropySourceFallback != 0 {
+			if remainingGas, err = reportEntropyFallback(state, addr, caller, blockNumber, readOnly, remainingGas); err != nil {
+				return nil, 0, err
+			}
+		}
+		ret = encodeRandomNCSPRNGOutput(stream, nUint256.Uint64())
+		if !readOnly {
+			// The words of the array follow its offset and length in the encoding.
+			updateRollingCommitment(state, addr, ret[2*common.HashLength:])
+			if err := emitRandomnessRequested(state, addr, caller, nUint256.Uint64(), blockNumber); err != nil {
+				return nil, remainingGas, err
+			}
+		}
+		if o.callCounter {
+			advanceCallCounter(state, addr, caller)
+		}
+
+		return ret, remainingGas, nil
+	}
 }
 
-// CreateRandomNCSPRNGPrecompile returns a StatefulPrecompiledContract with randomNCSPRNG function
-func CreateRandomNCSPRNGPrecompile() contract.StatefulPrecompiledContract {
-	abi := contract.ParseABI(randomNCSPRNGABI)
+// randomHandlers returns the handler of every method of randomABI on a precompile built with
+// [cfg] and [o], keyed by method name. Overloaded methods are keyed by the name the ABI gives
+// them, e.g. randomNCSPRNG0.
+func randomHandlers(cfg Config, o options) map[string]contract.RunStatefulPrecompileFunc {
+	return map[string]contract.RunStatefulPrecompileFunc{
+		"randomNCSPRNG":          newRandomNCSPRNGFunc(cfg, o),
+		"randomNCSPRNG0":         newRandomNCSPRNGFunc(cfg, o),
+		"randomMultiple":         RandomMultipleFunc,
+		"randomGraph":            RandomGraphFunc,
+		"randomMerkleRoot":       RandomMerkleRootFunc,
+		"proveValue":             ProveValueFunc,
+		"dealCards":              DealCardsFunc,
+		"tokenRandom":            TokenRandomFunc,
+		"requestDelayedRandom":   RequestDelayedRandomFunc,
+		"revealDelayedRandom":    RevealDelayedRandomFunc,
+		"randomWalk":             RandomWalkFunc,
+		"multiPartyRandom":       MultiPartyRandomFunc,
+		"timestampedRandom":      TimestampedRandomFunc,
+		"randomPartition":        RandomPartitionFunc,
+		"randomByTxIndex":        RandomByTxIndexFunc,
+		"antiClustered":          AntiClusteredFunc,
+		"randomAffine":           RandomAffineFunc,
+		"randomModWithStats":     RandomModWithStatsFunc,
+		"pickWithCooldown":       PickWithCooldownFunc,
+		"commitServerSeed":       CommitServerSeedFunc,
+		"revealServerSeed":       RevealServerSeedFunc,
+		"randomBounded":          RandomBoundedFunc,
+		"randomPriorities":       RandomPrioritiesFunc,
+		"randomFromLogs":         RandomFromLogsFunc,
+		"signedRandom":           NewSignedRandomFunc(o.signingKey),
+		"randomCapacitatedGraph": RandomCapacitatedGraphFunc,
+		"abBucket":               AbBucketFunc,
+		"randomMixture":          RandomMixtureFunc,
+		"randomTarget":           RandomTargetFunc,
+		"randomWithAlgo":         RandomWithAlgoFunc,
+		"delayRandom":            DelayRandomFunc,
+		"randomPiecewise":        RandomPiecewiseFunc,
+		"stockItems":             StockItemsFunc,
+		"drawAndDeplete":         DrawAndDepleteFunc,
+		"randomWithDomain":       RandomWithDomainFunc,
+		"getCommitment":          GetCommitmentFunc,
+		"randomAboveThreshold":   RandomAboveThresholdFunc,
+		"randomWithProvenance":   newRandomWithProvenanceFunc(o),
+		"randomBracket":          RandomBracketFunc,
+		"randomQuality":          RandomQualityFunc,
+		"randomSpanningTree":     RandomSpanningTreeFunc,
+		"randomFromCounter":      newRandomFromCounterFunc(o),
+		"randomBoundedSum":       RandomBoundedSumFunc,
+		"sortedMerkleRoot":       SortedMerkleRootFunc,
+		"proveAbsence":           ProveAbsenceFunc,
+		"uniquePerTx":            UniquePerTxFunc,
+		"rollNotation":           RollNotationFunc,
+		"feeJitter":              FeeJitterFunc,
+		"packedSmall":            PackedSmallFunc,
+		"gachaPull":              GachaPullFunc,
+		"randomDirichlet":        RandomDirichletFunc,
+		"proposerRandom":         ProposerRandomFunc,
+		"randomQR":               RandomQRFunc,
+		"randomInRange":          newRandomInRangeFunc(o),
+		"randomCoprime":          RandomCoprimeFunc,
+		"randomRaw":              newRandomRawFunc(cfg, o),
+		"randomOne":              newRandomOneFunc(o),
+		"randomMaze":             RandomMazeFunc,
+		"randomWithSelfCheck":    newRandomWithSelfCheckFunc(o),
+		"drawWinners":            DrawWinnersFunc,
+		"publishCommitmentRoot":  PublishCommitmentRootFunc,
+		"drawFromLeaf":           DrawFromLeafFunc,
+		"shuffle":                ShuffleFunc,
+		"rateLimitedRandom":      newRateLimitedRandomFunc(cfg),
+		"proposalRandom":         ProposalRandomFunc,
+		"randomBytes":            newRandomBytesFunc(cfg, o),
+		"cappedWeightedPick":     CappedWeightedPickFunc,
+		"commitRandomness":       CommitRandomnessFunc,
+		"revealRandomness":       RevealRandomnessFunc,
+		"verifyBeaconAndDraw":    NewVerifyBeaconAndDrawFunc(o.beaconPublicKey, o.beaconSchedule),
+		"shuffledRandom":         newShuffledRandomFunc(cfg, o),
+		"commitBoard":            CommitBoardFunc,
+		"revealCell":             RevealCellFunc,
+		"randomProbVector":       RandomProbVectorFunc,
+		"randomFromMessage":      RandomFromMessageFunc,
+		"randomWithLineage":      newRandomWithLineageFunc(o),
+		"weightedPick":           WeightedPickFunc,
+		"interleavedStreams":     InterleavedStreamsFunc,
+		"fairGame":               FairGameFunc,
+		"randomWithDropout":      RandomWithDropoutFunc,
+	}
+}
 
-	randomNCSPRNGFunction := contract.NewStatefulPrecompileFunction(abi.Methods["randomNCSPRNG"].ID, RandomNCSPRNGFunc)
-	contract, err := contract.NewStatefulPrecompileContract(nil, []*contract.StatefulPrecompileFunction{randomNCSPRNGFunction})
+// newRandomPrecompile returns a StatefulPrecompiledContract dispatching every method of
+// randomABI by its 4 byte selector to the handler of the same name in [handlers]. It panics if
+// a method has no handler or a handler no method, so the ABI and the handlers cannot drift
+// apart.
+func newRandomPrecompile(handlers map[string]contract.RunStatefulPrecompileFunc) contract.StatefulPrecompiledContract {
+	if len(handlers) != len(randomABI.Methods) {
+		panic(fmt.Sprintf("random precompile has %d handlers for %d methods", len(handlers), len(randomABI.Methods)))
+	}
+	functions := make([]*contract.StatefulPrecompileFunction, 0, len(randomABI.Methods))
+	for name, method := range randomABI.Methods {
+		handler, ok := handlers[name]
+		if !ok {
+			panic(fmt.Sprintf("random precompile has no handler for method %s", name))
+		}
+		functions = append(functions, contract.NewStatefulPrecompileFunctionWithActivator(method.ID, handler, isRandomnessActivated))
+	}
+	contract, err := contract.NewStatefulPrecompileContract(nil, functions)
 	if err != nil {
 		panic(err)
 	}
 	return contract
 }
+
+// CreateRandomNCSPRNGPrecompile returns a StatefulPrecompiledContract exposing every randomness function of the package,
+// to be registered at cfg.Address, the only address it runs at. Its functions are only active from the RandomnessBlock of
+// the chain config.
+func CreateRandomNCSPRNGPrecompile(cfg Config, opts ...Option) contract.StatefulPrecompiledContract {
+	return &addressBoundPrecompile{
+		address:  cfg.Address,
+		contract: newRandomPrecompile(randomHandlers(cfg, newOptions(opts))),
+	}
+}
@@ -0,0 +1,649 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+const testGas = 100_000_000
+
+var testCaller = common.HexToAddress("0x00000000000000000000000000000000000c0ffe")
+
+// mockStateDB is an in-memory contract.StateDB used to exercise the precompile.
+type mockStateDB struct {
+	storage    map[common.Address]map[common.Hash]common.Hash
+	nonces     map[common.Address]uint64
+	balances   map[common.Address]*uint256.Int
+	predicates map[common.Address][][]byte
+	txHash     common.Hash
+	txIndex    int
+
+	logTopics [][]common.Hash
+	logData   [][]byte
+
+	snapshots []mockSnapshot
+}
+
+type mockSnapshot struct {
+	storage map[common.Address]map[common.Hash]common.Hash
+	nonces  map[common.Address]uint64
+	logs    int
+}
+
+func newMockStateDB() *mockStateDB {
+	return &mockStateDB{
+		storage:    make(map[common.Address]map[common.Hash]common.Hash),
+		nonces:     make(map[common.Address]uint64),
+		balances:   make(map[common.Address]*uint256.Int),
+		predicates: make(map[common.Address][][]byte),
+	}
+}
+
+func (s *mockStateDB) GetState(addr common.Address, key common.Hash) common.Hash {
+	return s.storage[addr][key]
+}
+
+func (s *mockStateDB) SetState(addr common.Address, key common.Hash, value common.Hash) {
+	if s.storage[addr] == nil {
+		s.storage[addr] = make(map[common.Hash]common.Hash)
+	}
+	s.storage[addr][key] = value
+}
+
+func (s *mockStateDB) SetNonce(addr common.Address, nonce uint64) { s.nonces[addr] = nonce }
+func (s *mockStateDB) GetNonce(addr common.Address) uint64        { return s.nonces[addr] }
+
+func (s *mockStateDB) GetBalance(addr common.Address) *uint256.Int {
+	if balance, ok := s.balances[addr]; ok {
+		return balance
+	}
+	return new(uint256.Int)
+}
+
+func (s *mockStateDB) AddBalance(addr common.Address, amount *uint256.Int) {
+	s.balances[addr] = new(uint256.Int).Add(s.GetBalance(addr), amount)
+}
+
+func (s *mockStateDB) CreateAccount(addr common.Address) {}
+
+func (s *mockStateDB) Exist(addr common.Address) bool {
+	_, ok := s.nonces[addr]
+	return ok
+}
+
+func (s *mockStateDB) AddLog(addr common.Address, topics []common.Hash, data []byte, blockNumber uint64) {
+	s.logTopics = append(s.logTopics, topics)
+	s.logData = append(s.logData, data)
+}
+
+func (s *mockStateDB) GetLogData() ([][]common.Hash, [][]byte) {
+	return s.logTopics, s.logData
+}
+
+func (s *mockStateDB) GetPredicateStorageSlots(addr common.Address, index int) ([]byte, bool) {
+	predicates := s.predicates[addr]
+	if index < 0 || index >= len(predicates) {
+		return nil, false
+	}
+	return predicates[index], true
+}
+
+func (s *mockStateDB) SetPredicateStorageSlots(addr common.Address, predicates [][]byte) {
+	s.predicates[addr] = predicates
+}
+
+func (s *mockStateDB) GetTxHash() common.Hash { return s.txHash }
+func (s *mockStateDB) GetTxIndex() int        { return s.txIndex }
+
+func (s *mockStateDB) Snapshot() int {
+	snap := mockSnapshot{
+		storage: make(map[common.Address]map[common.Hash]common.Hash),
+		nonces:  make(map[common.Address]uint64),
+		logs:    len(s.logData),
+	}
+	for addr, slots := range s.storage {
+		snap.storage[addr] = make(map[common.Hash]common.Hash)
+		for k, v := range slots {
+			snap.storage[addr][k] = v
+		}
+	}
+	for addr, nonce := range s.nonces {
+		snap.nonces[addr] = nonce
+	}
+	s.snapshots = append(s.snapshots, snap)
+	return len(s.snapshots) - 1
+}
+
+func (s *mockStateDB) RevertToSnapshot(id int) {
+	snap := s.snapshots[id]
+	s.storage, s.nonces = snap.storage, snap.nonces
+	s.logTopics, s.logData = s.logTopics[:snap.logs], s.logData[:snap.logs]
+	s.snapshots = s.snapshots[:id]
+}
+
+// eventLogs returns the positions of the logs of event [name] emitted into [s].
+func eventLogs(s *mockStateDB, name string) []int {
+	var logs []int
+	for i, topics := range s.logTopics {
+		if len(topics) > 0 && topics[0] == randomABI.Events[name].ID {
+			logs = append(logs, i)
+		}
+	}
+	return logs
+}
+
+// mockAccessibleState is a contract.AccessibleState backed by a mockStateDB.
+type mockAccessibleState struct {
+	state       *mockStateDB
+	blockCtx    *vm.BlockContext
+	chainConfig *params.ChainConfig
+}
+
+func newMockAccessibleState() *mockAccessibleState {
+	return &mockAccessibleState{
+		state: newMockStateDB(),
+		blockCtx: &vm.BlockContext{
+			BlockNumber: big.NewInt(1),
+			Time:        1_700_000_000,
+		},
+		chainConfig: randomnessChainConfig(big.NewInt(0)),
+	}
+}
+
+// randomnessChainConfig returns a copy of params.TestChainConfig activating the randomness
+// precompiles at [block].
+func randomnessChainConfig(block *big.Int) *params.ChainConfig {
+	config := *params.TestChainConfig
+	config.RandomnessBlock = block
+	return &config
+}
+
+func (s *mockAccessibleState) GetStateDB() contract.StateDB        { return s.state }
+func (s *mockAccessibleState) GetBlockContext() *vm.BlockContext   { return s.blockCtx }
+func (s *mockAccessibleState) GetChainConfig() *params.ChainConfig { return s.chainConfig }
+
+// runMethod packs a call to [method], runs it through the precompile and returns the
+// unpacked outputs together with the remaining gas.
+func runMethod(state contract.AccessibleState, caller common.Address, method string, args ...interface{}) ([]interface{}, uint64, error) {
+	return runMethodWithGas(state, caller, testGas, method, args...)
+}
+
+// runMethodWithGas is like runMethod but supplies [suppliedGas] to the call.
+func runMethodWithGas(state contract.AccessibleState, caller common.Address, suppliedGas uint64, method string, args ...interface{}) ([]interface{}, uint64, error) {
+	input, err := randomABI.Pack(method, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	ret, remainingGas, err := CreateRandomNCSPRNGPrecompile(DefaultConfig()).Run(state, caller, randomNCSPRNGContractAddr, input, suppliedGas, false)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	out, err := randomABI.Methods[method].Outputs.Unpack(ret)
+	return out, remainingGas, err
+}
+
+// runMethodAsSolidity is like runMethod, but makes the call the way Solidity compiles it: as a
+// STATICCALL, which is read-only, for view and pure methods. The precompile is built with [opts].
+func runMethodAsSolidity(state contract.AccessibleState, caller common.Address, opts []Option, method string, args ...interface{}) ([]interface{}, error) {
+	input, err := randomABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	readOnly := randomABI.Methods[method].IsConstant()
+	ret, _, err := CreateRandomNCSPRNGPrecompile(DefaultConfig(), opts...).Run(state, caller, randomNCSPRNGContractAddr, input, testGas, readOnly)
+	if err != nil {
+		return nil, err
+	}
+	return randomABI.Methods[method].Outputs.Unpack(ret)
+}
+
+// mustRunMethod is like runMethod but fails the test on error.
+func mustRunMethod(t *testing.T, state contract.AccessibleState, caller common.Address, method string, args ...interface{}) []interface{} {
+	t.Helper()
+	out, _, err := runMethod(state, caller, method, args...)
+	if err != nil {
+		t.Fatalf("%s failed: %v", method, err)
+	}
+	return out
+}
+
+// blockHashes returns a GetHash function knowing the hashes of the 256 blocks before the
+// block of [state], like the EVM.
+func blockHashes(state *mockAccessibleState) func(uint64) common.Hash {
+	return func(n uint64) common.Hash {
+		current := state.blockCtx.BlockNumber.Uint64()
+		if n >= current || n+256 < current {
+			return common.Hash{}
+		}
+		return crypto.Keccak256Hash(new(big.Int).SetUint64(n).Bytes())
+	}
+}
+
+// runRandomNCSPRNG draws [n] values through a precompile built with [opts].
+func runRandomNCSPRNG(t *testing.T, state *mockAccessibleState, n int64, readOnly bool, opts ...Option) []*big.Int {
+	t.Helper()
+	input, err := PackRandomNCSPRNGInput(big.NewInt(n))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret, _, err := CreateRandomNCSPRNGPrecompile(DefaultConfig(), opts...).Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, readOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := randomABI.Methods["randomNCSPRNG"].Outputs.Unpack(ret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out[0].([]*big.Int)
+}
+
+func TestRandomNCSPRNGMatchesCallerStream(t *testing.T) {
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 7)
+
+	out := mustRunMethod(t, state, testCaller, "randomNCSPRNG", big.NewInt(4))
+	values := out[0].([]*big.Int)
+	if len(values) != 4 {
+		t.Fatalf("got %d values, want 4", len(values))
+	}
+	stream := newCallerStream(randomNCSPRNGContractAddr, testCaller, state.state)
+	for i, v := range values {
+		if want := stream.next(); v.Cmp(want) != 0 {
+			t.Errorf("value %d: got %x, want %x", i, v, want)
+		}
+	}
+}
+
+func TestVerifyRandomNCSPRNG(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, nonce := range []uint64{0, 1, 41} {
+		state.state.SetNonce(testCaller, nonce)
+		values := runRandomNCSPRNG(t, state, 6, false)
+		verified := VerifyRandomNCSPRNG(randomNCSPRNGContractAddr, testCaller, nonce, 6)
+		if len(verified) != len(values) {
+			t.Fatalf("nonce %d: verifier returned %d values, want %d", nonce, len(verified), len(values))
+		}
+		for i := range values {
+			if verified[i].Cmp(values[i]) != 0 {
+				t.Errorf("nonce %d, value %d: verifier got %x, precompile returned %x", nonce, i, verified[i], values[i])
+			}
+		}
+	}
+}
+
+func TestRandomNCSPRNGWarmupDiscard(t *testing.T) {
+	const warmup = 5
+	state := newMockAccessibleState()
+	input, err := PackRandomNCSPRNGInput(big.NewInt(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ret, _, err := CreateRandomNCSPRNGPrecompile(DefaultConfig(), WithWarmupDiscard(warmup)).Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := randomABI.Methods["randomNCSPRNG"].Outputs.Unpack(ret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := out[0].([]*big.Int)
+
+	unwarmed := newCallerStream(randomNCSPRNGContractAddr, testCaller, state.state).values(warmup + 3)
+	for i, v := range values {
+		if want := unwarmed[warmup+i]; v.Cmp(want) != 0 {
+			t.Errorf("value %d: got %x, want %x", i, v, want)
+		}
+	}
+}
+
+func TestRandomNCSPRNGPrevRandao(t *testing.T) {
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 3)
+
+	// Pre-merge blocks have no PREVRANDAO and keep the plain derivation.
+	plain := runRandomNCSPRNG(t, state, 4, true)
+	key := serverSeed(randomNCSPRNGContractAddr)
+	stream := newRandomStream(key, userSeed(key, testCaller), 3)
+	for i, v := range plain {
+		if want := stream.next(); v.Cmp(want) != 0 {
+			t.Errorf("nil PREVRANDAO, value %d: got %x, want %x", i, v, want)
+		}
+	}
+
+	random := common.HexToHash("0x5eed")
+	state.blockCtx.Random = &random
+	mixed := runRandomNCSPRNG(t, state, 4, true)
+	key = prevRandaoServerSeed(key, random)
+	stream = newRandomStream(key, userSeed(key, testCaller), 3)
+	for i, v := range mixed {
+		if want := stream.next(); v.Cmp(want) != 0 {
+			t.Errorf("PREVRANDAO set, value %d: got %x, want %x", i, v, want)
+		}
+		if v.Cmp(plain[i]) == 0 {
+			t.Errorf("value %d not affected by PREVRANDAO", i)
+		}
+	}
+
+	other := common.HexToHash("0x5eee")
+	state.blockCtx.Random = &other
+	if v := runRandomNCSPRNG(t, state, 1, true)[0]; v.Cmp(mixed[0]) == 0 {
+		t.Errorf("different PREVRANDAO values yielded the same value %x", v)
+	}
+}
+
+func TestRandomNCSPRNGParentHash(t *testing.T) {
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 3)
+	state.blockCtx.BlockNumber = big.NewInt(7)
+
+	// Without block hashes the plain derivation is kept.
+	plain := runRandomNCSPRNG(t, state, 4, true)
+
+	parent := common.HexToHash("0x9a7e47")
+	var requested []uint64
+	state.blockCtx.GetHash = func(n uint64) common.Hash {
+		requested = append(requested, n)
+		return parent
+	}
+	mixed := runRandomNCSPRNG(t, state, 4, true)
+	if len(requested) != 1 || requested[0] != 6 {
+		t.Fatalf("looked up blocks %v, want the parent 6", requested)
+	}
+	key := parentHashServerSeed(serverSeed(randomNCSPRNGContractAddr), parent)
+	stream := newRandomStream(key, userSeed(key, testCaller), 3)
+	for i, v := range mixed {
+		if want := stream.next(); v.Cmp(want) != 0 {
+			t.Errorf("parent hash set, value %d: got %x, want %x", i, v, want)
+		}
+		if v.Cmp(plain[i]) == 0 {
+			t.Errorf("value %d not affected by the parent hash", i)
+		}
+	}
+
+	// Genesis has no parent, and an unknown parent hash is zero: both fall back.
+	for name, setup := range map[string]func(){
+		"genesis":      func() { state.blockCtx.BlockNumber = big.NewInt(0) },
+		"unknown hash": func() { state.blockCtx.GetHash = func(uint64) common.Hash { return common.Hash{} } },
+	} {
+		setup()
+		if v := runRandomNCSPRNG(t, state, 1, true)[0]; v.Cmp(plain[0]) != 0 {
+			t.Errorf("%s: got %x, want the plain value %x", name, v, plain[0])
+		}
+	}
+}
+
+func TestRandomNCSPRNGGas(t *testing.T) {
+	state := newMockAccessibleState()
+	run := func(n *big.Int, suppliedGas uint64) (uint64, error) {
+		input, err := PackRandomNCSPRNGInput(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, remainingGas, err := CreateRandomNCSPRNGPrecompile(DefaultConfig()).Run(state, testCaller, randomNCSPRNGContractAddr, input, suppliedGas, true)
+		return remainingGas, err
+	}
+
+	for _, n := range []uint64{0, 1, MaxRandomNCSPRNGValues} {
+		remainingGas, err := run(new(big.Int).SetUint64(n), testGas)
+		if err != nil {
+			t.Fatalf("n = %d: %v", n, err)
+		}
+		if used, want := testGas-remainingGas, RandomNCSPRNGBaseGas+n*RandomNCSPRNGPerValueGas; used != want {
+			t.Errorf("n = %d: used %d gas, want %d", n, used, want)
+		}
+	}
+
+	// A request whose cost exceeds the supplied gas fails before drawing anything.
+	if remainingGas, err := run(big.NewInt(MaxRandomNCSPRNGValues), RandomNCSPRNGBaseGas); err != vm.ErrOutOfGas || remainingGas != 0 {
+		t.Errorf("got %d gas left and %v, want 0 and %v", remainingGas, err, vm.ErrOutOfGas)
+	}
+}
+
+func TestRandomNCSPRNGMaxValues(t *testing.T) {
+	state := newMockAccessibleState()
+	if values := runRandomNCSPRNG(t, state, 0, true); len(values) != 0 {
+		t.Errorf("n = 0: got %d values, want none", len(values))
+	}
+	if values := runRandomNCSPRNG(t, state, MaxRandomNCSPRNGValues, true); len(values) != MaxRandomNCSPRNGValues {
+		t.Errorf("n = MaxRandomNCSPRNGValues: got %d values", len(values))
+	}
+
+	for _, n := range []*big.Int{big.NewInt(MaxRandomNCSPRNGValues + 1), new(big.Int).SetUint64(1<<64 - 1), new(big.Int).Lsh(common.Big1, 200)} {
+		input, err := PackRandomNCSPRNGInput(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, remainingGas, err := CreateRandomNCSPRNGPrecompile(DefaultConfig()).Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, true)
+		if err != errTooManyValues || remainingGas != testGas {
+			t.Errorf("n = %v: got %d gas left and %v, want %d and %v", n, remainingGas, err, testGas, errTooManyValues)
+		}
+	}
+}
+
+func TestEncodeRandomNCSPRNGOutput(t *testing.T) {
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 2)
+
+	for _, n := range []uint64{0, 1, 7, MaxRandomValues} {
+		values, err := generateRandomNCSPRNG(randomNCSPRNGContractAddr, testCaller, *uint256.NewInt(n), common.Hash{}, state.blockCtx, options{}, state.state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := PackRandomNCSPRNGOutput(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stream, _, _ := newRandomNCSPRNGStream(randomNCSPRNGContractAddr, testCaller, state.blockCtx, options{}, state.state)
+		got := encodeRandomNCSPRNGOutput(stream, n)
+		if !bytes.Equal(got, want) {
+			t.Errorf("n = %d: incremental encoding differs from packed output", n)
+		}
+	}
+}
+
+func TestUnpackRandomNCSPRNGOutput(t *testing.T) {
+	for _, want := range [][]*big.Int{
+		{},
+		{big.NewInt(0), big.NewInt(42), new(big.Int).Sub(two256, common.Big1)},
+	} {
+		data, err := PackRandomNCSPRNGOutput(want)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := UnpackRandomNCSPRNGOutput(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("got %d values, want %d", len(got), len(want))
+		}
+		for i := range want {
+			if got[i].Cmp(want[i]) != 0 {
+				t.Errorf("value %d: got %v, want %v", i, got[i], want[i])
+			}
+		}
+	}
+
+	// Data truncated inside the head or the words, or claiming more words than it holds.
+	data, _ := PackRandomNCSPRNGOutput([]*big.Int{big.NewInt(1), big.NewInt(2)})
+	for _, malformed := range [][]byte{nil, data[:common.HashLength], data[:len(data)-1], data[:len(data)-common.HashLength]} {
+		if _, err := UnpackRandomNCSPRNGOutput(malformed); err == nil {
+			t.Errorf("malformed data of %d bytes was decoded", len(malformed))
+		}
+	}
+}
+
+func TestRandomNCSPRNGZeroValues(t *testing.T) {
+	input, err := PackRandomNCSPRNGInput(big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Under these options any stream derivation fails, so the draw must not derive one.
+	precompile := CreateRandomNCSPRNGPrecompile(DefaultConfig(), WithMinEntropySources(3), WithCallCounter())
+	state := newMockAccessibleState()
+	ret, remainingGas, err := precompile.Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if used := testGas - remainingGas; used != RandomNCSPRNGBaseGas {
+		t.Errorf("used %d gas, want %d", used, RandomNCSPRNGBaseGas)
+	}
+	if len(state.state.storage) != 0 || len(state.state.logData) != 0 {
+		t.Errorf("empty draw wrote %d accounts and %d logs", len(state.state.storage), len(state.state.logData))
+	}
+	values, err := UnpackRandomNCSPRNGOutput(ret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != 0 {
+		t.Fatalf("got %d values, want none", len(values))
+	}
+
+	// Only the output buffer is allocated; the hashing loop never runs.
+	if allocs := testing.AllocsPerRun(100, func() { encodeRandomNCSPRNGOutput(nil, 0) }); allocs > 1 {
+		t.Errorf("empty encoding made %v allocations, want at most 1", allocs)
+	}
+}
+
+func BenchmarkRandomNCSPRNGZeroValues(b *testing.B) {
+	input, err := PackRandomNCSPRNGInput(big.NewInt(0))
+	if err != nil {
+		b.Fatal(err)
+	}
+	state := newMockAccessibleState()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := RandomNCSPRNGFunc(state, testCaller, randomNCSPRNGContractAddr, input[contract.SelectorLen:], testGas, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkRandomNCSPRNGOutput(b *testing.B) {
+	const n = 1 << 14
+	state := newMockAccessibleState()
+
+	b.Run("pack", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			values, _ := generateRandomNCSPRNG(randomNCSPRNGContractAddr, testCaller, *uint256.NewInt(n), common.Hash{}, state.blockCtx, options{}, state.state)
+			if _, err := PackRandomNCSPRNGOutput(values); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("encode", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			stream, _, _ := newRandomNCSPRNGStream(randomNCSPRNGContractAddr, testCaller, state.blockCtx, options{}, state.state)
+			encodeRandomNCSPRNGOutput(stream, n)
+		}
+	})
+}
+
+func TestRandomNCSPRNGReadOnly(t *testing.T) {
+	opts := []Option{WithServerSeed(common.Hash{}), WithNonceReuseDetection(), WithBlockAuditLog()}
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 5)
+
+	readOnly := runRandomNCSPRNG(t, state, 4, true, opts...)
+	if len(state.state.storage) != 0 || len(state.state.logData) != 0 {
+		t.Fatalf("read-only call wrote %d accounts and %d logs", len(state.state.storage), len(state.state.logData))
+	}
+	// The values do not depend on the skipped bookkeeping.
+	values := runRandomNCSPRNG(t, state, 4, false, opts...)
+	for i := range values {
+		if values[i].Cmp(readOnly[i]) != 0 {
+			t.Errorf("value %d: got %x, want %x", i, values[i], readOnly[i])
+		}
+	}
+	if len(state.state.storage) == 0 || len(state.state.logData) == 0 {
+		t.Errorf("call wrote no state")
+	}
+}
+
+func TestRandomNCSPRNGSalt(t *testing.T) {
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 4)
+
+	run := func(input []byte) []*big.Int {
+		t.Helper()
+		ret, _, err := CreateRandomNCSPRNGPrecompile(DefaultConfig()).Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := randomABI.Methods["randomNCSPRNG"].Outputs.Unpack(ret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out[0].([]*big.Int)
+	}
+	salted := func(salt common.Hash) []*big.Int {
+		t.Helper()
+		input, err := PackSaltedRandomNCSPRNGInput(big.NewInt(4), salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return run(input)
+	}
+
+	// The legacy overload keeps its values, and a zero salt reproduces them.
+	input, err := PackRandomNCSPRNGInput(big.NewInt(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := run(input)
+	want := VerifyRandomNCSPRNG(randomNCSPRNGContractAddr, testCaller, 4, 4)
+	unsalted := salted(common.Hash{})
+	for i := range want {
+		if legacy[i].Cmp(want[i]) != 0 || unsalted[i].Cmp(want[i]) != 0 {
+			t.Fatalf("value %d: got %x and %x, want %x", i, legacy[i], unsalted[i], want[i])
+		}
+	}
+
+	// Different salts separate the streams.
+	a, b := salted(common.HexToHash("0xa")), salted(common.HexToHash("0xb"))
+	seen := make(map[string]bool)
+	for _, values := range [][]*big.Int{legacy, a, b} {
+		for _, v := range values {
+			if seen[v.String()] {
+				t.Fatalf("value %x repeated across salts", v)
+			}
+			seen[v.String()] = true
+		}
+	}
+	if again := salted(common.HexToHash("0xa")); again[0].Cmp(a[0]) != 0 {
+		t.Errorf("salted draw is not deterministic")
+	}
+
+	// The witness of a salted call recomputes it once its salt is set.
+	w := NewRandomnessWitness(state.state, randomNCSPRNGContractAddr, testCaller, state.blockCtx, 4)
+	w.Salt = common.HexToHash("0xa")
+	recomputed, err := ComputeFromWitness(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range a {
+		if recomputed[i].Cmp(a[i]) != 0 {
+			t.Errorf("witness value %d: got %x, want %x", i, recomputed[i], a[i])
+		}
+	}
+
+	for _, length := range []int{0, 31, 33, 96} {
+		if _, _, err := UnpackRandomNCSPRNGInput(make([]byte, length)); err != errInvalidInputLength {
+			t.Errorf("length %d: got %v, want %v", length, err, errInvalidInputLength)
+		}
+	}
+}
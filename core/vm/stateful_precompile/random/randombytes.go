@@ -0,0 +1,78 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+func PackRandomBytesInput(n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomBytes", n)
+}
+
+func UnpackRandomBytesInput(input []byte) (uint64, error) {
+	var n *big.Int
+	if err := unpackInput("randomBytes", input, &n); err != nil {
+		return 0, err
+	}
+	if !n.IsUint64() {
+		return 0, errTooManyValues
+	}
+	return n.Uint64(), nil
+}
+
+func PackRandomBytesOutput(randomWords [][32]byte) ([]byte, error) {
+	return randomABI.Methods["randomBytes"].Outputs.Pack(randomWords)
+}
+
+// randomWords returns the next [n] words of [stream] as the HMAC digests themselves.
+func randomWords(stream *randomStream, n uint64) [][32]byte {
+	words := make([][32]byte, n)
+	for i := range words {
+		stream.fill(words[i][:])
+	}
+	return words
+}
+
+// newRandomBytesFunc returns the randomBytes handler of a precompile built with [cfg] and [o].
+// It returns the values of randomNCSPRNG as bytes32[] for callers wanting raw entropy. A
+// bytes32[] is encoded exactly like a uint256[], so the words are written straight into the
+// output by encodeRandomNCSPRNGOutput, and the result equals PackRandomBytesOutput of the same
+// words.
+func newRandomBytesFunc(cfg Config, o options) contract.RunStatefulPrecompileFunc {
+	return func(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		n, err := UnpackRandomBytesInput(input)
+		if err != nil {
+			return nil, suppliedGas, err
+		}
+		if n > cfg.MaxValues {
+			return nil, suppliedGas, errTooManyValues
+		}
+		gas, ok := cfg.drawGas(n)
+		if !ok {
+			return nil, 0, vm.ErrOutOfGas
+		}
+		if remainingGas, err = contract.DeductGas(suppliedGas, gas); err != nil {
+			return nil, 0, err
+		}
+
+		state := accessibleState.GetStateDB()
+		blockCtx := accessibleState.GetBlockContext()
+		stream, sources, err := newRandomNCSPRNGStream(addr, caller, blockCtx, o, state)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		if sources&EntropySourceFallback != 0 {
+			if remainingGas, err = reportEntropyFallback(state, addr, caller, blockCtx.BlockNumber.Uint64(), readOnly, remainingGas); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		return encodeRandomNCSPRNGOutput(stream, n), remainingGas, nil
+	}
+}
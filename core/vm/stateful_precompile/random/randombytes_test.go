@@ -0,0 +1,89 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+)
+
+func TestRandomBytes(t *testing.T) {
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 3)
+
+	for _, n := range []uint64{0, 1, 9, MaxRandomValues} {
+		values, err := generateRandomNCSPRNG(randomNCSPRNGContractAddr, testCaller, *uint256.NewInt(n), common.Hash{}, state.blockCtx, options{}, state.state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := PackRandomNCSPRNGOutput(values)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stream, _, _ := newRandomNCSPRNGStream(randomNCSPRNGContractAddr, testCaller, state.blockCtx, options{}, state.state)
+		packed, err := PackRandomBytesOutput(randomWords(stream, n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(packed, want) {
+			t.Errorf("n = %d: bytes32[] output differs from uint256[] output", n)
+		}
+
+		input, err := PackRandomBytesInput(new(big.Int).SetUint64(n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret, _, err := CreateRandomNCSPRNGPrecompile(DefaultConfig()).Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(ret, want) {
+			t.Errorf("n = %d: randomBytes output differs from randomNCSPRNG output", n)
+		}
+		out, err := randomABI.Methods["randomBytes"].Outputs.Unpack(ret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, word := range out[0].([][32]byte) {
+			if new(big.Int).SetBytes(word[:]).Cmp(values[i]) != 0 {
+				t.Errorf("n = %d, word %d: got %x, want %x", n, i, word, values[i])
+			}
+		}
+	}
+}
+
+func BenchmarkRandomBytesOutput(b *testing.B) {
+	const n = 1 << 14
+	state := newMockAccessibleState()
+
+	b.Run("uint256", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			values, _ := generateRandomNCSPRNG(randomNCSPRNGContractAddr, testCaller, *uint256.NewInt(n), common.Hash{}, state.blockCtx, options{}, state.state)
+			if _, err := PackRandomNCSPRNGOutput(values); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("bytes32", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			stream, _, _ := newRandomNCSPRNGStream(randomNCSPRNGContractAddr, testCaller, state.blockCtx, options{}, state.state)
+			if _, err := PackRandomBytesOutput(randomWords(stream, n)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("encode", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			stream, _, _ := newRandomNCSPRNGStream(randomNCSPRNGContractAddr, testCaller, state.blockCtx, options{}, state.state)
+			encodeRandomNCSPRNGOutput(stream, n)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RateLimitedRandomBaseGas = 1024 + contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot
+
+	// DefaultMinDrawGap is the MinDrawGap of the default configuration: one draw per caller and
+	// block.
+	DefaultMinDrawGap = 1
+)
+
+var errDrawTooSoon = errors.New("caller drew within the minimum block gap")
+
+// RateLimitedRandomInput is the input of the rateLimitedRandom method.
+type RateLimitedRandomInput struct {
+	N           *big.Int
+	MinBlockGap *big.Int
+}
+
+func PackRateLimitedRandomInput(n *big.Int, minBlockGap *big.Int) ([]byte, error) {
+	return randomABI.Pack("rateLimitedRandom", n, minBlockGap)
+}
+
+func UnpackRateLimitedRandomInput(input []byte) (uint64, *big.Int, error) {
+	var in RateLimitedRandomInput
+	if err := unpackInput("rateLimitedRandom", input, &in); err != nil {
+		return 0, nil, err
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return 0, nil, errTooManyValues
+	}
+	return in.N.Uint64(), in.MinBlockGap, nil
+}
+
+func PackRateLimitedRandomOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["rateLimitedRandom"].Outputs.Pack(randomValues)
+}
+
+// lastDrawKey returns the slot holding the block after the one of the last rateLimitedRandom
+// draw of [caller], or zero if it never drew.
+func lastDrawKey(caller common.Address) common.Hash {
+	return stateKey("ratelimit.last", caller.Bytes())
+}
+
+// checkDrawGap returns errDrawTooSoon if [caller] drew less than [minBlockGap] blocks before
+// [blockNumber] and otherwise records [blockNumber] as its last draw.
+//
+// The record only stops a caller from drawing again until the gap elapsed. A transaction that
+// reverts after seeing an unfavorable draw also reverts the record, so consumers must still
+// commit to acting on the draw in the transaction that makes it.
+func checkDrawGap(state contract.StateDB, addr common.Address, caller common.Address, minBlockGap *big.Int, blockNumber *big.Int) error {
+	key := lastDrawKey(caller)
+	if last := state.GetState(addr, key).Big(); last.Sign() != 0 {
+		// The slot holds the draw block plus one.
+		ready := last.Sub(last, common.Big1).Add(last, minBlockGap)
+		if blockNumber.Cmp(ready) < 0 {
+			return errDrawTooSoon
+		}
+	}
+	state.SetState(addr, key, common.BigToHash(new(big.Int).Add(blockNumber, common.Big1)))
+	return nil
+}
+
+// newRateLimitedRandomFunc returns the rateLimitedRandom handler of a precompile built with
+// [cfg]. It returns [n] values, rejecting callers that drew fewer than [minBlockGap] blocks
+// ago, and never fewer than cfg.MinDrawGap, so they cannot draw and discard values in a loop
+// by asking for a smaller gap.
+func newRateLimitedRandomFunc(cfg Config) contract.RunStatefulPrecompileFunc {
+	return func(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		n, minBlockGap, err := UnpackRateLimitedRandomInput(input)
+		if err != nil {
+			return nil, suppliedGas, err
+		}
+		if floor := new(big.Int).SetUint64(cfg.MinDrawGap); minBlockGap.Cmp(floor) < 0 {
+			minBlockGap = floor
+		}
+		if remainingGas, err = contract.DeductGas(suppliedGas, RateLimitedRandomBaseGas+n*RandomPerValueGas); err != nil {
+			return nil, 0, err
+		}
+		if readOnly {
+			return nil, remainingGas, vm.ErrWriteProtection
+		}
+
+		state := accessibleState.GetStateDB()
+		if err := checkDrawGap(state, addr, caller, minBlockGap, accessibleState.GetBlockContext().BlockNumber); err != nil {
+			return nil, remainingGas, err
+		}
+
+		ret, err = PackRateLimitedRandomOutput(newCallerStream(addr, caller, state).values(n))
+		if err != nil {
+			return nil, remainingGas, err
+		}
+
+		return ret, remainingGas, nil
+	}
+}
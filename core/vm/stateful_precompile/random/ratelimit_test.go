@@ -0,0 +1,59 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRateLimitedRandom(t *testing.T) {
+	const gap = 5
+	state := newMockAccessibleState()
+	state.blockCtx.BlockNumber = big.NewInt(100)
+
+	values := mustRunMethod(t, state, testCaller, "rateLimitedRandom", big.NewInt(3), big.NewInt(gap))[0].([]*big.Int)
+	if len(values) != 3 {
+		t.Fatalf("got %d values, want 3", len(values))
+	}
+	for _, block := range []int64{100, 101, 104} {
+		state.blockCtx.BlockNumber = big.NewInt(block)
+		if _, _, err := runMethod(state, testCaller, "rateLimitedRandom", big.NewInt(3), big.NewInt(gap)); err != errDrawTooSoon {
+			t.Fatalf("block %d: got %v, want %v", block, err, errDrawTooSoon)
+		}
+	}
+
+	state.blockCtx.BlockNumber = big.NewInt(105)
+	mustRunMethod(t, state, testCaller, "rateLimitedRandom", big.NewInt(3), big.NewInt(gap))
+	// The gap restarts from the last successful draw.
+	state.blockCtx.BlockNumber = big.NewInt(107)
+	if _, _, err := runMethod(state, testCaller, "rateLimitedRandom", big.NewInt(3), big.NewInt(gap)); err != errDrawTooSoon {
+		t.Fatalf("got %v, want %v", err, errDrawTooSoon)
+	}
+}
+
+func TestRateLimitedRandomMinDrawGap(t *testing.T) {
+	state := newMockAccessibleState()
+	state.blockCtx.BlockNumber = big.NewInt(100)
+	mustRunMethod(t, state, testCaller, "rateLimitedRandom", big.NewInt(3), big.NewInt(0))
+
+	// Asking for a zero gap does not lift the gap of the configuration.
+	if _, _, err := runMethod(state, testCaller, "rateLimitedRandom", big.NewInt(3), big.NewInt(0)); err != errDrawTooSoon {
+		t.Fatalf("got %v, want %v", err, errDrawTooSoon)
+	}
+	state.blockCtx.BlockNumber = big.NewInt(100 + DefaultMinDrawGap)
+	mustRunMethod(t, state, testCaller, "rateLimitedRandom", big.NewInt(3), big.NewInt(0))
+
+	// A larger configured gap applies to every caller.
+	cfg := DefaultConfig()
+	cfg.MinDrawGap = 10
+	input, err := PackRateLimitedRandomInput(big.NewInt(3), big.NewInt(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.blockCtx.BlockNumber = big.NewInt(105)
+	if _, _, err := CreateRandomNCSPRNGPrecompile(cfg).Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, false); err != errDrawTooSoon {
+		t.Fatalf("configured gap: got %v, want %v", err, errDrawTooSoon)
+	}
+}
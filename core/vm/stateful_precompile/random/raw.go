@@ -0,0 +1,88 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+var errInvalidRawLength = errors.New("raw output length is not a multiple of 32")
+
+func PackRandomRawInput(n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomRaw", n)
+}
+
+func UnpackRandomRawInput(input []byte) (uint64, error) {
+	var n *big.Int
+	if err := unpackInput("randomRaw", input, &n); err != nil {
+		return 0, err
+	}
+	if !n.IsUint64() {
+		return 0, errTooManyValues
+	}
+	return n.Uint64(), nil
+}
+
+// UnpackRaw splits the output of randomRaw into its 32 byte big-endian words.
+func UnpackRaw(data []byte) ([]*big.Int, error) {
+	if len(data)%common.HashLength != 0 {
+		return nil, errInvalidRawLength
+	}
+	values := make([]*big.Int, len(data)/common.HashLength)
+	for i := range values {
+		values[i] = new(big.Int).SetBytes(data[i*common.HashLength : (i+1)*common.HashLength])
+	}
+	return values, nil
+}
+
+// encodeRandomRawOutput returns the next [n] words of [stream] concatenated.
+func encodeRandomRawOutput(stream *randomStream, n uint64) []byte {
+	ret := make([]byte, n*common.HashLength)
+	for i := uint64(0); i < n; i++ {
+		stream.fill(ret[i*common.HashLength : (i+1)*common.HashLength])
+	}
+	return ret
+}
+
+// newRandomRawFunc returns the randomRaw handler of a precompile built with [cfg] and [o]. It
+// returns the values of randomNCSPRNG as exactly 32*n bytes of return data, without the
+// offset and length words of an ABI-encoded array, for callers decoding the words in
+// assembly. The output is therefore not declared in the ABI; decode it with UnpackRaw.
+func newRandomRawFunc(cfg Config, o options) contract.RunStatefulPrecompileFunc {
+	return func(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		n, err := UnpackRandomRawInput(input)
+		if err != nil {
+			return nil, suppliedGas, err
+		}
+		if n > cfg.MaxValues {
+			return nil, suppliedGas, errTooManyValues
+		}
+		gas, ok := cfg.drawGas(n)
+		if !ok {
+			return nil, 0, vm.ErrOutOfGas
+		}
+		if remainingGas, err = contract.DeductGas(suppliedGas, gas); err != nil {
+			return nil, 0, err
+		}
+
+		state := accessibleState.GetStateDB()
+		blockCtx := accessibleState.GetBlockContext()
+		stream, sources, err := newRandomNCSPRNGStream(addr, caller, blockCtx, o, state)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		if sources&EntropySourceFallback != 0 {
+			if remainingGas, err = reportEntropyFallback(state, addr, caller, blockCtx.BlockNumber.Uint64(), readOnly, remainingGas); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		return encodeRandomRawOutput(stream, n), remainingGas, nil
+	}
+}
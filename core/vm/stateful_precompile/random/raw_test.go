@@ -0,0 +1,61 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestRandomRaw(t *testing.T) {
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 4)
+	precompile := CreateRandomNCSPRNGPrecompile(DefaultConfig())
+
+	for _, n := range []int64{0, 1, 9} {
+		input, err := PackRandomRawInput(big.NewInt(n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, _, err := precompile.Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(raw) != int(32*n) {
+			t.Fatalf("n = %d: got %d bytes, want %d", n, len(raw), 32*n)
+		}
+
+		input, err = PackRandomNCSPRNGInput(big.NewInt(n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		encoded, _, err := precompile.Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The raw output is the ABI array without its offset and length words.
+		if !bytes.Equal(raw, encoded[64:]) {
+			t.Fatalf("n = %d: raw output differs from the words of the ABI array", n)
+		}
+
+		values, err := UnpackRaw(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := runRandomNCSPRNG(t, state, n, true)
+		if len(values) != len(want) {
+			t.Fatalf("n = %d: unpacked %d values, want %d", n, len(values), len(want))
+		}
+		for i := range values {
+			if values[i].Cmp(want[i]) != 0 {
+				t.Errorf("n = %d, value %d: got %x, want %x", n, i, values[i], want[i])
+			}
+		}
+	}
+
+	if _, err := UnpackRaw(make([]byte, 33)); err != errInvalidRawLength {
+		t.Errorf("got %v, want %v", err, errInvalidRawLength)
+	}
+}
@@ -0,0 +1,30 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// RandomnessRequestedGasCost is charged by randomNCSPRNG draws that are not read-only for the
+// RandomnessRequested event, priced like a LOG2 of two words.
+const RandomnessRequestedGasCost = params.LogGas + 2*params.LogTopicGas + 2*common.HashLength*params.LogDataGas
+
+// emitRandomnessRequested emits the RandomnessRequested event recording that [caller] drew [n]
+// values from randomNCSPRNG at its current account nonce, so auditors and front-ends can
+// follow every request on-chain and recompute it with VerifyRandomNCSPRNG or a witness.
+func emitRandomnessRequested(state contract.StateDB, precompileAddr common.Address, caller common.Address, n uint64, blockNumber uint64) error {
+	event := randomABI.Events["RandomnessRequested"]
+	data, err := event.Inputs.NonIndexed().Pack(new(big.Int).SetUint64(n), new(big.Int).SetUint64(state.GetNonce(caller)))
+	if err != nil {
+		return err
+	}
+	topics := []common.Hash{event.ID, common.BytesToHash(caller.Bytes())}
+	state.AddLog(precompileAddr, topics, data, blockNumber)
+	return nil
+}
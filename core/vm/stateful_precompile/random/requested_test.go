@@ -0,0 +1,38 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRandomnessRequested(t *testing.T) {
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 9)
+
+	runRandomNCSPRNG(t, state, 3, true)
+	if logs := eventLogs(state.state, "RandomnessRequested"); len(logs) != 0 {
+		t.Fatalf("read-only call emitted %d logs", len(logs))
+	}
+
+	runRandomNCSPRNG(t, state, 3, false)
+	logs := eventLogs(state.state, "RandomnessRequested")
+	if len(logs) != 1 {
+		t.Fatalf("got %d logs, want 1", len(logs))
+	}
+	topics, data := state.state.GetLogData()
+	if topics[logs[0]][1] != common.BytesToHash(testCaller.Bytes()) {
+		t.Errorf("got caller topic %x, want %x", topics[logs[0]][1], testCaller)
+	}
+	out, err := randomABI.Events["RandomnessRequested"].Inputs.NonIndexed().Unpack(data[logs[0]])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, nonce := out[0].(*big.Int), out[1].(*big.Int); n.Uint64() != 3 || nonce.Uint64() != 9 {
+		t.Errorf("got n %d and nonce %d, want 3 and 9", n, nonce)
+	}
+}
@@ -0,0 +1,93 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomQRBaseGas = 2048
+	// RandomQRPerValueGas covers the expected two candidates and Legendre symbols per value.
+	RandomQRPerValueGas = 512
+)
+
+var errInvalidPrime = errors.New("prime must be an odd prime")
+
+// RandomQRInput is the input of the randomQR method.
+type RandomQRInput struct {
+	Prime   *big.Int
+	Residue bool
+	N       *big.Int
+}
+
+func PackRandomQRInput(prime *big.Int, residue bool, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomQR", prime, residue, n)
+}
+
+func UnpackRandomQRInput(input []byte) (RandomQRInput, error) {
+	var in RandomQRInput
+	if err := unpackInput("randomQR", input, &in); err != nil {
+		return RandomQRInput{}, err
+	}
+	// Besides oddness, the modulus must really be prime: modulo a square no value has Jacobi
+	// symbol -1 and sampling non-residues would never terminate. ProbablyPrime(0) runs the
+	// deterministic Baillie-PSW test.
+	if in.Prime.Cmp(common.Big2) <= 0 || in.Prime.Bit(0) == 0 || !in.Prime.ProbablyPrime(0) {
+		return RandomQRInput{}, errInvalidPrime
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return RandomQRInput{}, errTooManyValues
+	}
+	return in, nil
+}
+
+func PackRandomQROutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomQR"].Outputs.Pack(randomValues)
+}
+
+// randomQuadraticResidue draws values uniformly from [1, prime) until one whose Legendre
+// symbol is 1, if [residue] is set, or -1 otherwise. Each half of the nonzero residues mod an
+// odd prime is exactly (prime-1)/2 values, so every candidate is accepted with probability 1/2.
+func randomQuadraticResidue(stream *randomStream, prime *big.Int, residue bool) *big.Int {
+	want := -1
+	if residue {
+		want = 1
+	}
+	bound := new(big.Int).Sub(prime, common.Big1)
+	for {
+		v := stream.uniform(bound)
+		v.Add(v, common.Big1)
+		if big.Jacobi(v, prime) == want {
+			return v
+		}
+	}
+}
+
+func RandomQRFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackRandomQRInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	n := in.N.Uint64()
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomQRBaseGas+n*RandomQRPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	values := make([]*big.Int, n)
+	for i := range values {
+		values[i] = randomQuadraticResidue(stream, in.Prime, in.Residue)
+	}
+	ret, err = PackRandomQROutput(values)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,43 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRandomQR(t *testing.T) {
+	state := newMockAccessibleState()
+	// 2^255 - 19, the prime of Curve25519, next to a small prime.
+	large := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	for _, prime := range []*big.Int{big.NewInt(7), big.NewInt(1_000_003), large} {
+		for _, residue := range []bool{true, false} {
+			values := mustRunMethod(t, state, testCaller, "randomQR", prime, residue, big.NewInt(50))[0].([]*big.Int)
+			if len(values) != 50 {
+				t.Fatalf("got %d values, want 50", len(values))
+			}
+			// Euler's criterion: v^((p-1)/2) is 1 for residues and p-1 for non-residues.
+			exp := new(big.Int).Rsh(prime, 1)
+			for _, v := range values {
+				if v.Sign() <= 0 || v.Cmp(prime) >= 0 {
+					t.Fatalf("p = %v: value %v out of range [1, p)", prime, v)
+				}
+				isResidue := new(big.Int).Exp(v, exp, prime).Cmp(big.NewInt(1)) == 0
+				if isResidue != residue {
+					t.Fatalf("p = %v: value %v is residue = %v, want %v", prime, v, isResidue, residue)
+				}
+			}
+		}
+	}
+}
+
+func TestRandomQRInvalidPrime(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, p := range []int64{0, 1, 2, 4, 9, 15} {
+		if _, _, err := runMethod(state, testCaller, "randomQR", big.NewInt(p), false, big.NewInt(1)); err != errInvalidPrime {
+			t.Errorf("p = %d: got %v, want %v", p, err, errInvalidPrime)
+		}
+	}
+}
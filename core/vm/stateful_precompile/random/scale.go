@@ -0,0 +1,66 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/bits"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/holiman/uint256"
+)
+
+// mul512 returns the 512 bit product of [x] and [y] as its high and low 256 bit halves.
+func mul512(x *uint256.Int, y *uint256.Int) (hi uint256.Int, lo uint256.Int) {
+	var p [8]uint64
+	for i := 0; i < 4; i++ {
+		var carry uint64
+		for j := 0; j < 4; j++ {
+			// h:l + p[i+j] + carry fits in 128 bits, as (2^64-1)^2 + 2*(2^64-1) = 2^128-1.
+			h, l := bits.Mul64(x[i], y[j])
+			l, c := bits.Add64(l, p[i+j], 0)
+			h += c
+			l, c = bits.Add64(l, carry, 0)
+			h += c
+			p[i+j], carry = l, h
+		}
+		p[i+4] = carry
+	}
+	copy(lo[:], p[:4])
+	copy(hi[:], p[4:])
+	return hi, lo
+}
+
+// scaleTo returns a value uniformly distributed in [0, r) with Lemire's multiply-then-shift
+// method: a word x of the stream is scaled to the high half of the 512 bit product x*r, which
+// cannot overflow. Scaling alone favours some values slightly, so words whose low half falls
+// below (2^256 - r) mod r are rejected. Unlike uniform, it stays in fixed width uint256 math
+// and only computes the rejection threshold, which needs a division, when the low half is
+// below r. [r] must be non-zero.
+func (s *randomStream) scaleTo(r *uint256.Int) *uint256.Int {
+	v, _ := s.scaleToWithRejections(r)
+	return v
+}
+
+// scaleToWithRejections is like scaleTo but also reports how many words were rejected before
+// one was accepted.
+func (s *randomStream) scaleToWithRejections(r *uint256.Int) (*uint256.Int, uint64) {
+	var (
+		word      [common.HashLength]byte
+		threshold *uint256.Int
+	)
+	for rejections := uint64(0); ; rejections++ {
+		s.fill(word[:])
+		hi, lo := mul512(new(uint256.Int).SetBytes32(word[:]), r)
+		if lo.Lt(r) {
+			if threshold == nil {
+				threshold = new(uint256.Int).Neg(r)
+				threshold.Mod(threshold, r)
+			}
+			if lo.Lt(threshold) {
+				continue
+			}
+		}
+		return &hi, rejections
+	}
+}
@@ -0,0 +1,92 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+)
+
+func TestMul512(t *testing.T) {
+	max := new(uint256.Int).SetAllOne()
+	for _, tt := range [][2]*uint256.Int{
+		{uint256.NewInt(0), max},
+		{uint256.NewInt(3), uint256.NewInt(5)},
+		{max, max},
+		{new(uint256.Int).Lsh(uint256.NewInt(1), 255), uint256.NewInt(6)},
+		{uint256.MustFromHex("0x123456789abcdef0fedcba9876543210deadbeefcafebabe0123456789abcdef"), uint256.MustFromHex("0xfedcba9876543210123456789abcdef0abad1deafeedface0f1e2d3c4b5a6978")},
+	} {
+		hi, lo := mul512(tt[0], tt[1])
+		want := new(big.Int).Mul(tt[0].ToBig(), tt[1].ToBig())
+		got := new(big.Int).Lsh(hi.ToBig(), 256)
+		got.Add(got, lo.ToBig())
+		if got.Cmp(want) != 0 {
+			t.Errorf("%x * %x: got %x, want %x", tt[0], tt[1], got, want)
+		}
+	}
+}
+
+func TestScaleTo(t *testing.T) {
+	const draws = 3000
+	// Ranges around 2^255 make the 512 bit product necessary and the rejection threshold large.
+	quarter := new(uint256.Int).Lsh(uint256.NewInt(1), 254)
+	for _, r := range []*uint256.Int{
+		new(uint256.Int).Mul(quarter, uint256.NewInt(3)),
+		new(uint256.Int).AddUint64(new(uint256.Int).Lsh(uint256.NewInt(1), 255), 1),
+		new(uint256.Int).SetAllOne(),
+	} {
+		stream := newRandomStream([]byte("key"), r.Bytes(), 0)
+		// Split [0, r) into three equal-sized thirds and count the values in each.
+		third := new(uint256.Int).Div(r, uint256.NewInt(3))
+		var counts [4]int
+		for i := 0; i < draws; i++ {
+			v := stream.scaleTo(r)
+			if !v.Lt(r) {
+				t.Fatalf("range %x: value %x out of range", r, v)
+			}
+			counts[new(uint256.Int).Div(v, third).Uint64()]++
+		}
+		// Each third expects 1000 values with a standard deviation of about 26.
+		for i, c := range counts[:3] {
+			if c < 900 || c > 1100 {
+				t.Errorf("range %x: third %d got %d of %d values", r, i, c, draws)
+			}
+		}
+	}
+
+	// A range of one always scales to zero.
+	stream := newRandomStream([]byte("key"), []byte("seed"), 0)
+	for i := 0; i < 10; i++ {
+		if v := stream.scaleTo(uint256.NewInt(1)); !v.IsZero() {
+			t.Fatalf("got %x in [0, 1)", v)
+		}
+	}
+}
+
+func TestUniformInRange(t *testing.T) {
+	// Ranges are inclusive and scaled like randomInRange.
+	min := new(big.Int).Lsh(big.NewInt(1), 200)
+	max := new(big.Int).Add(min, new(big.Int).Lsh(big.NewInt(1), 255))
+	stream := newRandomStream([]byte("key"), []byte("seed"), 0)
+	replay := newRandomStream([]byte("key"), []byte("seed"), 0)
+	width := uint256.MustFromBig(new(big.Int).Sub(max, min))
+	width.AddUint64(width, 1)
+	for i := 0; i < 100; i++ {
+		v := uniformInRange(stream, min, max)
+		if v.Cmp(min) < 0 || v.Cmp(max) > 0 {
+			t.Fatalf("value %x out of [%x, %x]", v, min, max)
+		}
+		if want := replay.scaleTo(width).ToBig(); new(big.Int).Sub(v, min).Cmp(want) != 0 {
+			t.Fatalf("got %x, want %x + min", v, want)
+		}
+	}
+
+	// The full 256 bit range is a single word, which scaleTo cannot express.
+	full := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	if got, want := uniformInRange(stream, new(big.Int), full), replay.next(); got.Cmp(want) != 0 {
+		t.Fatalf("full range: got %x, want %x", got, want)
+	}
+}
@@ -0,0 +1,149 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	PublishCommitmentRootGasCost = 1024 + contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot
+	DrawFromLeafBaseGas          = 1024 + contract.ReadGasCostPerSlot
+	DrawFromLeafPerNodeGas       = 64
+)
+
+// For long-running fairness the block producer commits to a merkle tree of future seeds in
+// advance with publishCommitmentRoot. Leaves are then revealed over time, and drawFromLeaf
+// only accepts a leaf proven to belong to the published tree, so the operator cannot choose
+// the seeds after seeing the draws they key. The tree is built like the ones of
+// randomMerkleRoot, leaf i committing to seed s as keccak(i || s), so off-chain tooling can
+// reuse merkleTree and VerifyMerkleProof.
+var commitmentRootKey = stateKey("seedtree.root")
+
+var (
+	errCommitmentRootPublished = errors.New("a seed commitment root is already published")
+	errNoCommitmentRoot        = errors.New("no seed commitment root published")
+	errInvalidLeafProof        = errors.New("leaf is not part of the published seed tree")
+)
+
+// PublishCommitmentRootInput is the input of the publishCommitmentRoot method.
+type PublishCommitmentRootInput struct {
+	Root [32]byte
+}
+
+// DrawFromLeafInput is the input of the drawFromLeaf method.
+type DrawFromLeafInput struct {
+	Index *big.Int
+	Leaf  [32]byte
+	Proof [][32]byte
+	N     *big.Int
+}
+
+func PackPublishCommitmentRootInput(root common.Hash) ([]byte, error) {
+	return randomABI.Pack("publishCommitmentRoot", [32]byte(root))
+}
+
+func UnpackPublishCommitmentRootInput(input []byte) (common.Hash, error) {
+	var in PublishCommitmentRootInput
+	if err := unpackInput("publishCommitmentRoot", input, &in); err != nil {
+		return common.Hash{}, err
+	}
+	return in.Root, nil
+}
+
+func PackDrawFromLeafInput(index *big.Int, leaf common.Hash, proof []common.Hash, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("drawFromLeaf", index, [32]byte(leaf), hashesToWords(proof), n)
+}
+
+// UnpackDrawFromLeafInput returns the position of the leaf, the leaf, its proof and the number
+// of values to draw.
+func UnpackDrawFromLeafInput(input []byte) (uint64, common.Hash, []common.Hash, uint64, error) {
+	var in DrawFromLeafInput
+	if err := unpackInput("drawFromLeaf", input, &in); err != nil {
+		return 0, common.Hash{}, nil, 0, err
+	}
+	if !in.Index.IsUint64() {
+		return 0, common.Hash{}, nil, 0, errIndexOutOfRange
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return 0, common.Hash{}, nil, 0, errTooManyValues
+	}
+	proof := make([]common.Hash, len(in.Proof))
+	for i, node := range in.Proof {
+		proof[i] = node
+	}
+	return in.Index.Uint64(), in.Leaf, proof, in.N.Uint64(), nil
+}
+
+func PackDrawFromLeafOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["drawFromLeaf"].Outputs.Pack(randomValues)
+}
+
+// newLeafStream returns the stream keyed by the seed [leaf]. It does not depend on the caller,
+// so anyone holding the revealed leaf can recompute the values of every draw it keyed.
+func newLeafStream(precompileAddr common.Address, leaf common.Hash) *randomStream {
+	return newKeyedStream(precompileAddr, "drawFromLeaf", leaf.Bytes())
+}
+
+// PublishCommitmentRootFunc lets the block producer publish the root of the seed tree. The root
+// cannot be replaced once published.
+func PublishCommitmentRootFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, PublishCommitmentRootGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+	if caller != accessibleState.GetBlockContext().Coinbase {
+		return nil, remainingGas, errNotBlockProducer
+	}
+
+	root, err := UnpackPublishCommitmentRootInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	if root == (common.Hash{}) {
+		return nil, remainingGas, errZeroCommitment
+	}
+
+	state := accessibleState.GetStateDB()
+	if state.GetState(addr, commitmentRootKey) != (common.Hash{}) {
+		return nil, remainingGas, errCommitmentRootPublished
+	}
+	state.SetState(addr, commitmentRootKey, root)
+
+	return []byte{}, remainingGas, nil
+}
+
+// DrawFromLeafFunc returns [n] values keyed by the seed [leaf] once [proof] shows it is the
+// leaf at position [index] of the published seed tree.
+func DrawFromLeafFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	index, leaf, proof, n, err := UnpackDrawFromLeafInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, DrawFromLeafBaseGas+uint64(len(proof))*DrawFromLeafPerNodeGas+n*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	root := accessibleState.GetStateDB().GetState(addr, commitmentRootKey)
+	if root == (common.Hash{}) {
+		return nil, remainingGas, errNoCommitmentRoot
+	}
+	if !VerifyMerkleProof(root, index, leaf.Big(), proof) {
+		return nil, remainingGas, errInvalidLeafProof
+	}
+
+	ret, err = PackDrawFromLeafOutput(newLeafStream(addr, leaf).values(n))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,62 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestDrawFromLeaf(t *testing.T) {
+	producer := common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	state := newMockAccessibleState()
+	state.blockCtx.Coinbase = producer
+
+	seeds := []common.Hash{common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03"), common.HexToHash("0x04"), common.HexToHash("0x05")}
+	leaves := make([]common.Hash, len(seeds))
+	for i, seed := range seeds {
+		leaves[i] = merkleLeaf(uint64(i), seed.Big())
+	}
+	levels := merkleTree(leaves)
+	root := levels[len(levels)-1][0]
+
+	proof := hashesToWords(merkleProof(levels, 3))
+	if _, _, err := runMethod(state, testCaller, "drawFromLeaf", big.NewInt(3), [32]byte(seeds[3]), proof, big.NewInt(2)); err != errNoCommitmentRoot {
+		t.Fatalf("draw before publication: got %v, want %v", err, errNoCommitmentRoot)
+	}
+	if _, _, err := runMethod(state, testCaller, "publishCommitmentRoot", [32]byte(root)); err != errNotBlockProducer {
+		t.Fatalf("publish by non-producer: got %v, want %v", err, errNotBlockProducer)
+	}
+	mustRunMethod(t, state, producer, "publishCommitmentRoot", [32]byte(root))
+	if _, _, err := runMethod(state, producer, "publishCommitmentRoot", [32]byte(common.HexToHash("0xbad"))); err != errCommitmentRootPublished {
+		t.Fatalf("republish: got %v, want %v", err, errCommitmentRootPublished)
+	}
+
+	values := mustRunMethod(t, state, testCaller, "drawFromLeaf", big.NewInt(3), [32]byte(seeds[3]), proof, big.NewInt(2))[0].([]*big.Int)
+	want := newLeafStream(randomNCSPRNGContractAddr, seeds[3]).values(2)
+	for i := range want {
+		if values[i].Cmp(want[i]) != 0 {
+			t.Errorf("value %d: got %x, want %x", i, values[i], want[i])
+		}
+	}
+
+	invalid := []struct {
+		name  string
+		index int64
+		leaf  common.Hash
+		proof [][32]byte
+	}{
+		{"wrong leaf", 3, seeds[2], proof},
+		{"wrong index", 2, seeds[3], proof},
+		{"short proof", 3, seeds[3], proof[:1]},
+		{"tampered proof", 3, seeds[3], append([][32]byte{common.HexToHash("0xbad")}, proof[1:]...)},
+	}
+	for _, tt := range invalid {
+		if _, _, err := runMethod(state, testCaller, "drawFromLeaf", big.NewInt(tt.index), [32]byte(tt.leaf), tt.proof, big.NewInt(2)); err != errInvalidLeafProof {
+			t.Errorf("%s: got %v, want %v", tt.name, err, errInvalidLeafProof)
+		}
+	}
+}
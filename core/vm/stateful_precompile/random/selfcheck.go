@@ -0,0 +1,99 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"math/bits"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	// RandomWithSelfCheckPerValueGas covers the bit count of every word on top of its draw.
+	RandomWithSelfCheckPerValueGas = RandomNCSPRNGPerValueGas + 16
+
+	// monobitThreshold is the square of 2.5758, the two-sided critical value of the standard
+	// normal distribution at the 0.01 significance level, scaled by monobitScale. The NIST
+	// SP 800-22 frequency test passes when erfc(|S|/sqrt(2N)) >= 0.01, i.e. S^2 < 2.5758^2 * N.
+	monobitThreshold = 66_349
+	monobitScale     = 10_000
+)
+
+func PackRandomWithSelfCheckInput(n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomWithSelfCheck", n)
+}
+
+func UnpackRandomWithSelfCheckInput(input []byte) (uint64, error) {
+	var n *big.Int
+	if err := unpackInput("randomWithSelfCheck", input, &n); err != nil {
+		return 0, err
+	}
+	if !n.IsUint64() || n.Uint64() > MaxRandomValues {
+		return 0, errTooManyValues
+	}
+	return n.Uint64(), nil
+}
+
+func PackRandomWithSelfCheckOutput(randomValues []*big.Int, passed bool) ([]byte, error) {
+	return randomABI.Methods["randomWithSelfCheck"].Outputs.Pack(randomValues, passed)
+}
+
+// monobitCheck runs the NIST SP 800-22 frequency (monobit) test over the bits of [data] and
+// reports whether it passes at the 0.01 significance level. With N bits of which ones are set,
+// the statistic is S = 2*ones - N, and a balanced sequence keeps S^2 below 2.5758^2 * N. The
+// computation is exact integer arithmetic so every node reaches the same verdict. Empty data
+// passes.
+func monobitCheck(data []byte) bool {
+	var ones int64
+	for _, b := range data {
+		ones += int64(bits.OnesCount8(b))
+	}
+	n := int64(len(data)) * 8
+	s := big.NewInt(2*ones - n)
+	lhs := new(big.Int).Mul(s, s)
+	lhs.Mul(lhs, big.NewInt(monobitScale))
+	return n == 0 || lhs.Cmp(big.NewInt(monobitThreshold*n)) < 0
+}
+
+// newRandomWithSelfCheckFunc returns the randomWithSelfCheck handler of a precompile built with
+// [o]. It returns the values randomNCSPRNG would return together with the verdict of the
+// monobit test over their bits. A failing block is still returned: the flag only reports it,
+// and a sound stream fails about once in a hundred draws by chance.
+func newRandomWithSelfCheckFunc(o options) contract.RunStatefulPrecompileFunc {
+	return func(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		n, err := UnpackRandomWithSelfCheckInput(input)
+		if err != nil {
+			return nil, suppliedGas, err
+		}
+		if remainingGas, err = contract.DeductGas(suppliedGas, RandomNCSPRNGBaseGas+n*RandomWithSelfCheckPerValueGas); err != nil {
+			return nil, 0, err
+		}
+
+		state := accessibleState.GetStateDB()
+		blockCtx := accessibleState.GetBlockContext()
+		stream, sources, err := newRandomNCSPRNGStream(addr, caller, blockCtx, o, state)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		if sources&EntropySourceFallback != 0 {
+			if remainingGas, err = reportEntropyFallback(state, addr, caller, blockCtx.BlockNumber.Uint64(), readOnly, remainingGas); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		words := encodeRandomRawOutput(stream, n)
+		values, err := UnpackRaw(words)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		ret, err = PackRandomWithSelfCheckOutput(values, monobitCheck(words))
+		if err != nil {
+			return nil, remainingGas, err
+		}
+
+		return ret, remainingGas, nil
+	}
+}
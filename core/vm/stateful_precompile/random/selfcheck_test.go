@@ -0,0 +1,55 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestMonobitCheck(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want bool
+	}{
+		{"empty", nil, true},
+		{"zeros", make([]byte, 32*8), false},
+		{"ones", bytes.Repeat([]byte{0xff}, 32*8), false},
+		{"alternating", bytes.Repeat([]byte{0x55}, 32*8), true},
+		// 1088 ones out of 2048 bits: S = 128, S^2 = 16384 > 6.6349 * 2048 = 13588.
+		{"biased", append(bytes.Repeat([]byte{0xff}, 16), bytes.Repeat([]byte{0x55}, 240)...), false},
+		// 1072 ones out of 2048 bits: S = 96, S^2 = 9216 < 13588.
+		{"slightly biased", append(bytes.Repeat([]byte{0xff}, 12), bytes.Repeat([]byte{0x55}, 244)...), true},
+	}
+	for _, tt := range tests {
+		if got := monobitCheck(tt.data); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRandomWithSelfCheck(t *testing.T) {
+	state := newMockAccessibleState()
+	var failures int
+	for nonce := uint64(0); nonce < 200; nonce++ {
+		state.state.SetNonce(testCaller, nonce)
+		out := mustRunMethod(t, state, testCaller, "randomWithSelfCheck", big.NewInt(8))
+		values, passed := out[0].([]*big.Int), out[1].(bool)
+		want := runRandomNCSPRNG(t, state, 8, true)
+		for i := range values {
+			if values[i].Cmp(want[i]) != 0 {
+				t.Fatalf("nonce %d, value %d: got %x, want %x", nonce, i, values[i], want[i])
+			}
+		}
+		if !passed {
+			failures++
+		}
+	}
+	// A sound stream fails the test at the 0.01 level about twice in 200 draws.
+	if failures > 10 {
+		t.Errorf("%d of 200 draws failed the monobit test", failures)
+	}
+}
@@ -0,0 +1,150 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	CommitServerSeedGasCost = 1024 + 2*contract.WriteGasCostPerSlot
+	RevealServerSeedGasCost = 1024 + 2*contract.ReadGasCostPerSlot + 3*contract.WriteGasCostPerSlot
+)
+
+// Server seed rotation is a two step, provably fair process run by the block producer:
+//
+//  1. commitServerSeed publishes keccak(seed) and records the block it was published in.
+//  2. revealServerSeed, in any later block, publishes seed itself. It must hash to the pending
+//     commitment, and from then on it keys the streams of all callers in place of the base
+//     server seed keccak(precompileAddr).
+//
+// Anyone can recompute keccak(seed) to check the operator did not swap the seed after the fact.
+var (
+	serverSeedCommitmentKey  = stateKey("serverseed.commitment")
+	serverSeedCommitBlockKey = stateKey("serverseed.block")
+	serverSeedActiveKey      = stateKey("serverseed.active")
+)
+
+var (
+	errNotBlockProducer       = errors.New("only the block producer may rotate the server seed")
+	errZeroCommitment         = errors.New("commitment must be non-zero")
+	errCommitmentPending      = errors.New("a server seed commitment is already pending")
+	errNoPendingCommitment    = errors.New("no server seed commitment pending")
+	errRevealInCommitBlock    = errors.New("server seed cannot be revealed in the block it was committed in")
+	errSeedCommitmentMismatch = errors.New("server seed does not match the commitment")
+)
+
+// CommitServerSeedInput is the input of the commitServerSeed method.
+type CommitServerSeedInput struct {
+	Commitment [32]byte
+}
+
+// RevealServerSeedInput is the input of the revealServerSeed method.
+type RevealServerSeedInput struct {
+	Seed [32]byte
+}
+
+func PackCommitServerSeedInput(commitment common.Hash) ([]byte, error) {
+	return randomABI.Pack("commitServerSeed", [32]byte(commitment))
+}
+
+func UnpackCommitServerSeedInput(input []byte) (common.Hash, error) {
+	var in CommitServerSeedInput
+	if err := unpackInput("commitServerSeed", input, &in); err != nil {
+		return common.Hash{}, err
+	}
+	return in.Commitment, nil
+}
+
+func PackRevealServerSeedInput(seed common.Hash) ([]byte, error) {
+	return randomABI.Pack("revealServerSeed", [32]byte(seed))
+}
+
+func UnpackRevealServerSeedInput(input []byte) (common.Hash, error) {
+	var in RevealServerSeedInput
+	if err := unpackInput("revealServerSeed", input, &in); err != nil {
+		return common.Hash{}, err
+	}
+	return in.Seed, nil
+}
+
+// activeServerSeed returns the HMAC key of the caller streams: the last revealed server seed,
+// or the base server seed if none was revealed yet.
+func activeServerSeed(state contract.StateDB, precompileAddr common.Address) []byte {
+	if seed := state.GetState(precompileAddr, serverSeedActiveKey); seed != (common.Hash{}) {
+		return seed.Bytes()
+	}
+	return serverSeed(precompileAddr)
+}
+
+func CommitServerSeedFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, CommitServerSeedGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+	if caller != accessibleState.GetBlockContext().Coinbase {
+		return nil, remainingGas, errNotBlockProducer
+	}
+
+	commitment, err := UnpackCommitServerSeedInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	if commitment == (common.Hash{}) {
+		return nil, remainingGas, errZeroCommitment
+	}
+
+	state := accessibleState.GetStateDB()
+	if state.GetState(addr, serverSeedCommitmentKey) != (common.Hash{}) {
+		return nil, remainingGas, errCommitmentPending
+	}
+	state.SetState(addr, serverSeedCommitmentKey, commitment)
+	state.SetState(addr, serverSeedCommitBlockKey, common.BigToHash(accessibleState.GetBlockContext().BlockNumber))
+
+	return []byte{}, remainingGas, nil
+}
+
+func RevealServerSeedFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, RevealServerSeedGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+	if caller != accessibleState.GetBlockContext().Coinbase {
+		return nil, remainingGas, errNotBlockProducer
+	}
+
+	seed, err := UnpackRevealServerSeedInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	state := accessibleState.GetStateDB()
+	commitment := state.GetState(addr, serverSeedCommitmentKey)
+	if commitment == (common.Hash{}) {
+		return nil, remainingGas, errNoPendingCommitment
+	}
+	commitBlock := state.GetState(addr, serverSeedCommitBlockKey).Big()
+	if accessibleState.GetBlockContext().BlockNumber.Cmp(new(big.Int).Add(commitBlock, common.Big1)) < 0 {
+		return nil, remainingGas, errRevealInCommitBlock
+	}
+	if crypto.Keccak256Hash(seed.Bytes()) != commitment {
+		return nil, remainingGas, errSeedCommitmentMismatch
+	}
+
+	state.SetState(addr, serverSeedActiveKey, seed)
+	state.SetState(addr, serverSeedCommitmentKey, common.Hash{})
+	state.SetState(addr, serverSeedCommitBlockKey, common.Hash{})
+
+	return []byte{}, remainingGas, nil
+}
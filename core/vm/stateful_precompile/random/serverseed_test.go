@@ -0,0 +1,59 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestServerSeedRotation(t *testing.T) {
+	producer := common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	state := newMockAccessibleState()
+	state.blockCtx.Coinbase = producer
+	state.blockCtx.BlockNumber = big.NewInt(50)
+
+	seed := common.HexToHash("0x5eed")
+	commitment := crypto.Keccak256Hash(seed.Bytes())
+	before := mustRunMethod(t, state, testCaller, "randomNCSPRNG", big.NewInt(2))[0].([]*big.Int)
+
+	if _, _, err := runMethod(state, testCaller, "commitServerSeed", [32]byte(commitment)); err != errNotBlockProducer {
+		t.Fatalf("commit by non-producer: got %v, want %v", err, errNotBlockProducer)
+	}
+	if _, _, err := runMethod(state, producer, "revealServerSeed", [32]byte(seed)); err != errNoPendingCommitment {
+		t.Fatalf("reveal without commit: got %v, want %v", err, errNoPendingCommitment)
+	}
+	mustRunMethod(t, state, producer, "commitServerSeed", [32]byte(commitment))
+	if _, _, err := runMethod(state, producer, "revealServerSeed", [32]byte(seed)); err != errRevealInCommitBlock {
+		t.Fatalf("same-block reveal: got %v, want %v", err, errRevealInCommitBlock)
+	}
+
+	state.blockCtx.BlockNumber = big.NewInt(51)
+	if _, _, err := runMethod(state, producer, "revealServerSeed", [32]byte(common.HexToHash("0xbad"))); err != errSeedCommitmentMismatch {
+		t.Fatalf("mismatched reveal: got %v, want %v", err, errSeedCommitmentMismatch)
+	}
+	// Draws are unchanged until the seed is revealed.
+	if unchanged := mustRunMethod(t, state, testCaller, "randomNCSPRNG", big.NewInt(2))[0].([]*big.Int); unchanged[0].Cmp(before[0]) != 0 {
+		t.Fatalf("draw changed before reveal")
+	}
+	mustRunMethod(t, state, producer, "revealServerSeed", [32]byte(seed))
+
+	after := mustRunMethod(t, state, testCaller, "randomNCSPRNG", big.NewInt(2))[0].([]*big.Int)
+	if after[0].Cmp(before[0]) == 0 {
+		t.Fatalf("draw unchanged after rotation")
+	}
+	// Everyone can recompute the draw from the revealed seed.
+	expected := newRandomStream(seed.Bytes(), userSeed(seed.Bytes(), testCaller), 0)
+	for i, v := range after {
+		if want := expected.next(); v.Cmp(want) != 0 {
+			t.Fatalf("value %d: got %x, want %x", i, v, want)
+		}
+	}
+	if _, _, err := runMethod(state, producer, "revealServerSeed", [32]byte(seed)); err != errNoPendingCommitment {
+		t.Fatalf("second reveal: got %v, want %v", err, errNoPendingCommitment)
+	}
+}
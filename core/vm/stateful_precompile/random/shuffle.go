@@ -0,0 +1,68 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	ShuffleBaseGas = 1024
+	// ShufflePerElementGas covers drawing the swap of one Fisher-Yates step and packing the
+	// element.
+	ShufflePerElementGas = 64
+)
+
+// ShuffleInput is the input of the shuffle method.
+type ShuffleInput struct {
+	N *big.Int
+}
+
+func PackShuffleInput(n *big.Int) ([]byte, error) {
+	return randomABI.Pack("shuffle", n)
+}
+
+func UnpackShuffleInput(input []byte) (uint64, error) {
+	var in ShuffleInput
+	if err := unpackInput("shuffle", input, &in); err != nil {
+		return 0, err
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return 0, errTooManyValues
+	}
+	return in.N.Uint64(), nil
+}
+
+func PackShuffleOutput(permutation []*big.Int) ([]byte, error) {
+	return randomABI.Methods["shuffle"].Outputs.Pack(permutation)
+}
+
+// shuffleGas returns the gas charged for shuffling [n] elements.
+func shuffleGas(n uint64) uint64 {
+	return ShuffleBaseGas + n*ShufflePerElementGas
+}
+
+// ShuffleFunc returns a random permutation of [0, n), e.g. to shuffle a deck, in one call.
+func ShuffleFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	n, err := UnpackShuffleInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, shuffleGas(n)); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	// Shuffling consumes the stream like randomPriorities, so both methods return the same
+	// permutation to the same caller at the same nonce.
+	ret, err = PackShuffleOutput(stream.permutation(n))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
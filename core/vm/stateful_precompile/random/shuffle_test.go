@@ -0,0 +1,69 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+func TestShuffle(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, n := range []int64{0, 1, 2, 52, MaxRandomValues} {
+		permutation := mustRunMethod(t, state, testCaller, "shuffle", big.NewInt(n))[0].([]*big.Int)
+		if len(permutation) != int(n) {
+			t.Fatalf("n=%d: got %d elements", n, len(permutation))
+		}
+		seen := make([]bool, n)
+		for _, v := range permutation {
+			if !v.IsInt64() || v.Int64() >= n || seen[v.Int64()] {
+				t.Fatalf("n=%d: %d is out of range or repeated", n, v)
+			}
+			seen[v.Int64()] = true
+		}
+
+		again := mustRunMethod(t, state, testCaller, "shuffle", big.NewInt(n))[0].([]*big.Int)
+		for i := range permutation {
+			if again[i].Cmp(permutation[i]) != 0 {
+				t.Fatalf("n=%d: shuffle is not deterministic at %d", n, i)
+			}
+		}
+	}
+
+	if _, _, err := runMethod(state, testCaller, "shuffle", big.NewInt(MaxRandomValues+1)); err != errTooManyValues {
+		t.Errorf("got %v, want %v", err, errTooManyValues)
+	}
+}
+
+func TestShuffleFixedSeed(t *testing.T) {
+	// Fisher-Yates over a fixed stream always yields the same permutation.
+	want := []int64{2, 7, 4, 1, 3, 0, 5, 6}
+	got := newRandomStream([]byte("key"), []byte("seed"), 0).permutation(uint64(len(want)))
+	for i := range want {
+		if got[i].Int64() != want[i] {
+			t.Fatalf("element %d: got %d, want %d", i, got[i], want[i])
+		}
+	}
+}
+
+func TestShuffleGas(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, n := range []uint64{0, 1, MaxRandomValues} {
+		_, remaining, err := runMethod(state, testCaller, "shuffle", new(big.Int).SetUint64(n))
+		if err != nil {
+			t.Fatalf("n=%d: %v", n, err)
+		}
+		if used, want := testGas-remaining, ShuffleBaseGas+n*ShufflePerElementGas; used != want {
+			t.Errorf("n=%d: used %d gas, want %d", n, used, want)
+		}
+	}
+
+	// One gas short of the full cost fails before shuffling anything.
+	suppliedGas := shuffleGas(MaxRandomValues) - 1
+	if _, remaining, err := runMethodWithGas(state, testCaller, suppliedGas, "shuffle", big.NewInt(MaxRandomValues)); err != vm.ErrOutOfGas || remaining != 0 {
+		t.Errorf("got %d gas left and %v, want 0 and %v", remaining, err, vm.ErrOutOfGas)
+	}
+}
@@ -0,0 +1,89 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+// shuffledRandomDomain separates the stream ordering the values of shuffledRandom from the
+// stream generating them.
+const shuffledRandomDomain = "shuffledRandom.order"
+
+func PackShuffledRandomInput(n *big.Int) ([]byte, error) {
+	return randomABI.Pack("shuffledRandom", n)
+}
+
+func UnpackShuffledRandomInput(input []byte) (uint64, error) {
+	var n *big.Int
+	if err := unpackInput("shuffledRandom", input, &n); err != nil {
+		return 0, err
+	}
+	if !n.IsUint64() {
+		return 0, errTooManyValues
+	}
+	return n.Uint64(), nil
+}
+
+func PackShuffledRandomOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["shuffledRandom"].Outputs.Pack(randomValues)
+}
+
+// generateShuffledRandom returns the next [n] values of [stream] reordered by a Fisher-Yates
+// shuffle drawn from the stream of [stream] in the shuffledRandomDomain, so the position of a
+// value says nothing about when it was generated.
+func generateShuffledRandom(stream *randomStream, n uint64) []*big.Int {
+	values := stream.values(n)
+	shuffled := make([]*big.Int, n)
+	for i, j := range stream.withDomain(shuffledRandomDomain).partialPermutation(n, n) {
+		shuffled[i] = values[j]
+	}
+	return shuffled
+}
+
+// newShuffledRandomFunc returns the shuffledRandom handler of a precompile built with [cfg]
+// and [o]. It returns the values of randomNCSPRNG in a reproducibly shuffled order: the same
+// caller at the same nonce always gets the same values in the same order. It costs a draw of
+// [n] values plus ShufflePerElementGas per value for the shuffle.
+func newShuffledRandomFunc(cfg Config, o options) contract.RunStatefulPrecompileFunc {
+	return func(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		n, err := UnpackShuffledRandomInput(input)
+		if err != nil {
+			return nil, suppliedGas, err
+		}
+		if n > cfg.MaxValues {
+			return nil, suppliedGas, errTooManyValues
+		}
+		gas, ok := cfg.drawGas(n)
+		if !ok {
+			return nil, 0, vm.ErrOutOfGas
+		}
+		if remainingGas, err = contract.DeductGas(suppliedGas, gas+n*ShufflePerElementGas); err != nil {
+			return nil, 0, err
+		}
+
+		state := accessibleState.GetStateDB()
+		blockCtx := accessibleState.GetBlockContext()
+		stream, sources, err := newRandomNCSPRNGStream(addr, caller, blockCtx, o, state)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		if sources&EntropySourceFallback != 0 {
+			if remainingGas, err = reportEntropyFallback(state, addr, caller, blockCtx.BlockNumber.Uint64(), readOnly, remainingGas); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		ret, err = PackShuffledRandomOutput(generateShuffledRandom(stream, n))
+		if err != nil {
+			return nil, remainingGas, err
+		}
+
+		return ret, remainingGas, nil
+	}
+}
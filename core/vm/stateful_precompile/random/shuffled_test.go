@@ -0,0 +1,43 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"slices"
+	"testing"
+)
+
+func TestShuffledRandom(t *testing.T) {
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 7)
+
+	const n = 16
+	generated := runRandomNCSPRNG(t, state, n, true)
+	shuffled := mustRunMethod(t, state, testCaller, "shuffledRandom", big.NewInt(n))[0].([]*big.Int)
+	if len(shuffled) != n {
+		t.Fatalf("got %d values, want %d", len(shuffled), n)
+	}
+	if slices.EqualFunc(shuffled, generated, func(a, b *big.Int) bool { return a.Cmp(b) == 0 }) {
+		t.Fatal("values were returned in generation order")
+	}
+	sorted := func(values []*big.Int) []*big.Int {
+		values = slices.Clone(values)
+		slices.SortFunc(values, (*big.Int).Cmp)
+		return values
+	}
+	if !slices.EqualFunc(sorted(shuffled), sorted(generated), func(a, b *big.Int) bool { return a.Cmp(b) == 0 }) {
+		t.Fatal("shuffled values differ from the generated ones")
+	}
+
+	// The order is reproducible at the same nonce and changes with it.
+	again := mustRunMethod(t, state, testCaller, "shuffledRandom", big.NewInt(n))[0].([]*big.Int)
+	if !slices.EqualFunc(again, shuffled, func(a, b *big.Int) bool { return a.Cmp(b) == 0 }) {
+		t.Fatal("same nonce yielded a different order")
+	}
+	state.state.SetNonce(testCaller, 8)
+	if next := mustRunMethod(t, state, testCaller, "shuffledRandom", big.NewInt(n))[0].([]*big.Int); next[0].Cmp(shuffled[0]) == 0 {
+		t.Fatal("next nonce yielded the same values")
+	}
+}
@@ -0,0 +1,90 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// SignedRandomBaseGas includes the cost of producing the signature, priced like ECRECOVER.
+	SignedRandomBaseGas = 1024 + 3000
+)
+
+var errNoSigningKey = errors.New("no signing key configured")
+
+// SignedRandomInput is the input of the signedRandom method.
+type SignedRandomInput struct {
+	N *big.Int
+}
+
+func PackSignedRandomInput(n *big.Int) ([]byte, error) {
+	return randomABI.Pack("signedRandom", n)
+}
+
+func UnpackSignedRandomInput(input []byte) (uint64, error) {
+	var in SignedRandomInput
+	if err := unpackInput("signedRandom", input, &in); err != nil {
+		return 0, err
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return 0, errTooManyValues
+	}
+	return in.N.Uint64(), nil
+}
+
+func PackSignedRandomOutput(randomValues []*big.Int, signature []byte) ([]byte, error) {
+	return randomABI.Methods["signedRandom"].Outputs.Pack(randomValues, signature)
+}
+
+// SignedRandomDigest returns the digest signed for [values] drawn by [caller] from the
+// precompile at [precompileAddr]: keccak(precompileAddr || caller || values...), with every
+// value encoded as a 32 byte word, i.e. keccak256(abi.encodePacked(...)) in Solidity.
+func SignedRandomDigest(precompileAddr common.Address, caller common.Address, values []*big.Int) common.Hash {
+	data := make([][]byte, 0, len(values)+2)
+	data = append(data, precompileAddr.Bytes(), caller.Bytes())
+	for _, v := range values {
+		data = append(data, common.BigToHash(v).Bytes())
+	}
+	return crypto.Keccak256Hash(data...)
+}
+
+// NewSignedRandomFunc returns the signedRandom handler, which returns the caller's next values
+// together with a 65 byte [R || S || V] signature of their SignedRandomDigest by [key]. V is
+// 27 or 28 so the signature can be fed to ecrecover directly.
+func NewSignedRandomFunc(key *ecdsa.PrivateKey) contract.RunStatefulPrecompileFunc {
+	return func(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+		n, err := UnpackSignedRandomInput(input)
+		if err != nil {
+			return nil, suppliedGas, err
+		}
+		if remainingGas, err = contract.DeductGas(suppliedGas, SignedRandomBaseGas+n*RandomPerValueGas); err != nil {
+			return nil, 0, err
+		}
+		if key == nil {
+			return nil, remainingGas, errNoSigningKey
+		}
+
+		values := newCallerStream(addr, caller, accessibleState.GetStateDB()).values(n)
+		digest := SignedRandomDigest(addr, caller, values)
+		signature, err := crypto.Sign(digest.Bytes(), key)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+		signature[crypto.RecoveryIDOffset] += 27
+
+		ret, err = PackSignedRandomOutput(values, signature)
+		if err != nil {
+			return nil, remainingGas, err
+		}
+
+		return ret, remainingGas, nil
+	}
+}
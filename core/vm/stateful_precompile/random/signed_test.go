@@ -0,0 +1,57 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSignedRandom(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := newMockAccessibleState()
+	input, err := PackSignedRandomInput(big.NewInt(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ret, _, err := CreateRandomNCSPRNGPrecompile(DefaultConfig(), WithSigningKey(key)).Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := randomABI.Methods["signedRandom"].Outputs.Unpack(ret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, signature := out[0].([]*big.Int), out[1].([]byte)
+	if len(signature) != crypto.SignatureLength || signature[crypto.RecoveryIDOffset] < 27 {
+		t.Fatalf("malformed signature %x", signature)
+	}
+
+	digest := SignedRandomDigest(randomNCSPRNGContractAddr, testCaller, values)
+	sig := append([]byte{}, signature...)
+	sig[crypto.RecoveryIDOffset] -= 27
+	pub, err := crypto.SigToPub(digest.Bytes(), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crypto.PubkeyToAddress(*pub) != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Fatalf("signature recovers to the wrong key")
+	}
+	if !crypto.VerifySignature(crypto.FromECDSAPub(&key.PublicKey), digest.Bytes(), sig[:64]) {
+		t.Fatalf("signature does not verify")
+	}
+}
+
+func TestSignedRandomWithoutKey(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "signedRandom", big.NewInt(1)); err != errNoSigningKey {
+		t.Fatalf("got %v, want %v", err, errNoSigningKey)
+	}
+}
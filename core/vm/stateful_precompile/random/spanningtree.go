@@ -0,0 +1,106 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomSpanningTreeBaseGas = 1024
+
+	// MaxSpanningTreeNodes bounds the number of nodes spanned by randomSpanningTree.
+	MaxSpanningTreeNodes = MaxRandomValues
+)
+
+var errTooManyTreeNodes = errors.New("too many spanning tree nodes")
+
+func PackRandomSpanningTreeInput(nodes *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomSpanningTree", nodes)
+}
+
+func UnpackRandomSpanningTreeInput(input []byte) (uint64, error) {
+	var nodes *big.Int
+	if err := unpackInput("randomSpanningTree", input, &nodes); err != nil {
+		return 0, err
+	}
+	if !nodes.IsUint64() || nodes.Uint64() > MaxSpanningTreeNodes {
+		return 0, errTooManyTreeNodes
+	}
+	return nodes.Uint64(), nil
+}
+
+func PackRandomSpanningTreeOutput(sources []*big.Int, targets []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomSpanningTree"].Outputs.Pack(sources, targets)
+}
+
+// generateRandomSpanningTree returns the nodes-1 edges of a spanning tree of the complete graph
+// over [0, nodes), drawn uniformly among all nodes^(nodes-2) of them. It draws a uniformly
+// random Prüfer sequence of length nodes-2 and decodes it in linear time: every entry is
+// joined to the smallest remaining leaf, which is then removed.
+func generateRandomSpanningTree(stream *randomStream, nodes uint64) ([]*big.Int, []*big.Int) {
+	sources, targets := []*big.Int{}, []*big.Int{}
+	if nodes < 2 {
+		return sources, targets
+	}
+	addEdge := func(u, v uint64) {
+		sources = append(sources, new(big.Int).SetUint64(u))
+		targets = append(targets, new(big.Int).SetUint64(v))
+	}
+
+	sequence := make([]uint64, nodes-2)
+	degree := make([]uint64, nodes)
+	for i := range degree {
+		degree[i] = 1
+	}
+	for i := range sequence {
+		sequence[i] = stream.uniformUint64(nodes)
+		degree[sequence[i]]++
+	}
+
+	ptr := uint64(0)
+	for degree[ptr] != 1 {
+		ptr++
+	}
+	leaf := ptr
+	for _, v := range sequence {
+		addEdge(leaf, v)
+		degree[leaf]--
+		degree[v]--
+		if degree[v] == 1 && v < ptr {
+			leaf = v
+			continue
+		}
+		ptr++
+		for degree[ptr] != 1 {
+			ptr++
+		}
+		leaf = ptr
+	}
+	addEdge(leaf, nodes-1)
+	return sources, targets
+}
+
+func RandomSpanningTreeFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	nodes, err := UnpackRandomSpanningTreeInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomSpanningTreeBaseGas+nodes*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	sources, targets := generateRandomSpanningTree(stream, nodes)
+	ret, err = PackRandomSpanningTreeOutput(sources, targets)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,90 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+// isSpanningTree reports whether the edges form a spanning tree of [0, nodes): exactly
+// nodes-1 edges that never close a cycle, which also makes the graph connected.
+func isSpanningTree(nodes int64, sources []*big.Int, targets []*big.Int) bool {
+	if nodes > 0 && int64(len(sources)) != nodes-1 {
+		return false
+	}
+	parent := make([]int64, nodes)
+	for i := range parent {
+		parent[i] = int64(i)
+	}
+	var find func(int64) int64
+	find = func(x int64) int64 {
+		if parent[x] != x {
+			parent[x] = find(parent[x])
+		}
+		return parent[x]
+	}
+	for i := range sources {
+		u, v := sources[i].Int64(), targets[i].Int64()
+		if u >= nodes || v >= nodes {
+			return false
+		}
+		ru, rv := find(u), find(v)
+		if ru == rv {
+			return false
+		}
+		parent[ru] = rv
+	}
+	return true
+}
+
+func TestRandomSpanningTree(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, nodes := range []int64{0, 1, 2, 3, 10, 100} {
+		for nonce := uint64(0); nonce < 5; nonce++ {
+			state.state.SetNonce(testCaller, nonce)
+			out := mustRunMethod(t, state, testCaller, "randomSpanningTree", big.NewInt(nodes))
+			sources, targets := out[0].([]*big.Int), out[1].([]*big.Int)
+			if !isSpanningTree(nodes, sources, targets) {
+				t.Fatalf("%d nodes, nonce %d: edges %v -> %v are not a spanning tree", nodes, nonce, sources, targets)
+			}
+		}
+	}
+}
+
+func TestRandomSpanningTreeUniform(t *testing.T) {
+	// K4 has 16 spanning trees, each a set of 3 of its 6 edges.
+	edgeIndex := map[[2]int64]int{{0, 1}: 0, {0, 2}: 1, {0, 3}: 2, {1, 2}: 3, {1, 3}: 4, {2, 3}: 5}
+	state := newMockAccessibleState()
+	counts := make(map[[6]bool]int)
+	for nonce := uint64(0); nonce < 1600; nonce++ {
+		state.state.SetNonce(testCaller, nonce)
+		out := mustRunMethod(t, state, testCaller, "randomSpanningTree", big.NewInt(4))
+		var tree [6]bool
+		for i, s := range out[0].([]*big.Int) {
+			u, v := s.Int64(), out[1].([]*big.Int)[i].Int64()
+			if u > v {
+				u, v = v, u
+			}
+			tree[edgeIndex[[2]int64{u, v}]] = true
+		}
+		counts[tree]++
+	}
+	if len(counts) != 16 {
+		t.Fatalf("got %d distinct trees, want 16", len(counts))
+	}
+	// Each tree expects 100 draws with a standard deviation of about 10.
+	for tree, count := range counts {
+		if count < 55 || count > 145 {
+			t.Errorf("tree %v drawn %d times, expected about 100", tree, count)
+		}
+	}
+}
+
+func TestRandomSpanningTreeTooManyNodes(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "randomSpanningTree", big.NewInt(MaxSpanningTreeNodes+1)); err != errTooManyTreeNodes {
+		t.Fatalf("got %v, want %v", err, errTooManyTreeNodes)
+	}
+}
@@ -0,0 +1,15 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// stateKey derives the storage slot of the precompile holding [label] for the given key
+// material. Labels keep the slots of the different methods from colliding.
+func stateKey(label string, parts ...[]byte) common.Hash {
+	return crypto.Keccak256Hash(append([][]byte{[]byte(label)}, parts...)...)
+}
@@ -0,0 +1,267 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"hash"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// maxBasisPoints is the denominator of probabilities expressed in basis points.
+const maxBasisPoints = 10_000
+
+// two256 is 2^256, the size of the space every stream word is drawn from.
+var two256 = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// ByteOrder is the byte order of the counter word hashed into every word of a stream.
+type ByteOrder uint8
+
+const (
+	// BigEndian encodes the counter as a 32 byte big-endian word. It is the default.
+	BigEndian ByteOrder = iota
+	// LittleEndian encodes the counter as a 32 byte little-endian word.
+	LittleEndian
+)
+
+// HMACHash is the hash function of the HMAC of a stream.
+type HMACHash uint8
+
+const (
+	// SHA256 makes the stream HMAC-SHA256, as specified in RFC 2104 and FIPS 180-4. It is the
+	// default.
+	SHA256 HMACHash = iota
+	// Keccak256 makes the stream HMAC over the legacy Keccak-256 of Solidity's keccak256, with
+	// a 136 byte block, so contracts and tooling can reproduce words with the hash they have.
+	Keccak256
+)
+
+// newHash returns a constructor of [h] for hmac.New.
+func (h HMACHash) newHash() func() hash.Hash {
+	if h == Keccak256 {
+		return func() hash.Hash { return crypto.NewKeccakState() }
+	}
+	return sha256.New
+}
+
+// randomStream is the counter-mode HMAC stream every randomness method draws from, over
+// SHA-256 unless built with another HMACHash. The i-th word is
+// HMAC(serverSeed, userSeed || nonce || i), with the nonce encoded as a 32 byte big-endian
+// word and the counter as a 32 byte word in counterOrder, or its keccak hash when the stream
+// is whitened.
+type randomStream struct {
+	mac          hash.Hash
+	userSeed     []byte
+	nonce        []byte
+	counter      uint64
+	counterOrder ByteOrder
+
+	// whitener hashes every word after the HMAC when set, see whiten.
+	whitener crypto.KeccakState
+}
+
+// newRandomStream returns a stream keyed by [serverSeed] producing words for [userSeed] at [nonce].
+func newRandomStream(serverSeed []byte, userSeed []byte, nonce uint64) *randomStream {
+	return newRandomStreamWithHash(serverSeed, userSeed, nonce, SHA256)
+}
+
+// newRandomStreamWithHash is like newRandomStream, but its HMAC is over [h].
+func newRandomStreamWithHash(serverSeed []byte, userSeed []byte, nonce uint64, h HMACHash) *randomStream {
+	return &randomStream{
+		mac:      hmac.New(h.newHash(), serverSeed),
+		userSeed: userSeed,
+		nonce:    common.BigToHash(new(big.Int).SetUint64(nonce)).Bytes(),
+	}
+}
+
+// serverSeed returns the HMAC key shared by every stream of the precompile at [precompileAddr].
+func serverSeed(precompileAddr common.Address) []byte {
+	return crypto.Keccak256(precompileAddr.Bytes())
+}
+
+// userSeed returns the per-account seed of [addr] under [serverSeed].
+func userSeed(serverSeed []byte, addr common.Address) []byte {
+	return crypto.Keccak256(addr.Bytes(), serverSeed)
+}
+
+// newCallerStream returns the stream of [caller] at its current account nonce, keyed by the
+// active server seed. Its words are exactly the values returned by randomNCSPRNG for the same
+// caller.
+func newCallerStream(precompileAddr common.Address, caller common.Address, state contract.StateDB) *randomStream {
+	key := activeServerSeed(state, precompileAddr)
+	return newRandomStream(key, userSeed(key, caller), state.GetNonce(caller))
+}
+
+// newCallerStreamAt returns the stream of [caller] as it was when its account nonce was [nonce].
+// It is always keyed by the base server seed, so draws replayed later from a recorded nonce are
+// not affected by server seed rotations.
+func newCallerStreamAt(precompileAddr common.Address, caller common.Address, nonce uint64) *randomStream {
+	key := serverSeed(precompileAddr)
+	return newRandomStream(key, userSeed(key, caller), nonce)
+}
+
+// newKeyedStream returns a stream that depends only on the precompile, [label] and [key], not
+// on who calls it or when, so the same key always yields the same values.
+func newKeyedStream(precompileAddr common.Address, label string, key []byte) *randomStream {
+	seed := serverSeed(precompileAddr)
+	return newRandomStream(seed, crypto.Keccak256([]byte(label), key, seed), 0)
+}
+
+// withDomain returns a stream independent of [s] for the same key and nonce, whose user seed
+// is the one of [s] separated by [label]. It starts at counter 0 and shares the HMAC of [s],
+// so the two streams must not be drawn from concurrently.
+func (s *randomStream) withDomain(label string) *randomStream {
+	return &randomStream{
+		mac:          s.mac,
+		userSeed:     crypto.Keccak256([]byte(label), s.userSeed),
+		nonce:        s.nonce,
+		counterOrder: s.counterOrder,
+		whitener:     s.whitener,
+	}
+}
+
+// nextBytes returns the next 32 byte word of the stream.
+func (s *randomStream) nextBytes() []byte {
+	word := make([]byte, common.HashLength)
+	s.fill(word)
+	return word
+}
+
+// fill writes the next word of the stream into [dst], which must be 32 bytes long.
+func (s *randomStream) fill(dst []byte) {
+	s.mac.Reset()
+	s.mac.Write(s.userSeed)
+	s.mac.Write(s.nonce)
+	s.mac.Write(s.counterWord())
+	s.counter++
+	s.mac.Sum(dst[:0])
+	if s.whitener != nil {
+		s.whitener.Reset()
+		s.whitener.Write(dst)
+		s.whitener.Read(dst)
+	}
+}
+
+// whiten makes every later word of the stream the keccak hash of its HMAC digest. Keccak is a
+// different construction from SHA-256, so a statistical weakness of HMAC-SHA256 would have to
+// survive an unrelated permutation to show in the output; the words remain uniform and as
+// unpredictable as before, and stay as reproducible, since the transform is deterministic.
+func (s *randomStream) whiten() {
+	s.whitener = crypto.NewKeccakState()
+}
+
+// counterWord returns the encoding of the current counter in the byte order of the stream.
+func (s *randomStream) counterWord() []byte {
+	word := common.BigToHash(new(big.Int).SetUint64(s.counter))
+	if s.counterOrder == LittleEndian {
+		slices.Reverse(word[:])
+	}
+	return word[:]
+}
+
+// next returns the next word of the stream as an integer in [0, 2^256).
+func (s *randomStream) next() *big.Int {
+	return new(big.Int).SetBytes(s.nextBytes())
+}
+
+// skip advances the stream past its next [n] words without computing them.
+func (s *randomStream) skip(n uint64) {
+	s.counter += n
+}
+
+// values returns the next [n] words of the stream.
+func (s *randomStream) values(n uint64) []*big.Int {
+	values := make([]*big.Int, n)
+	for i := range values {
+		values[i] = s.next()
+	}
+	return values
+}
+
+// uniform returns a value uniformly distributed in [0, bound). Words falling into the
+// incomplete last multiple of [bound] below 2^256 are rejected, so the reduction carries no
+// modulo bias. Every word is accepted with probability above 1/2, so the loop terminates
+// quickly. [bound] must be in (0, 2^256].
+func (s *randomStream) uniform(bound *big.Int) *big.Int {
+	v, _ := s.uniformWithRejections(bound)
+	return v
+}
+
+// uniformWithRejections is like uniform but also reports how many words were rejected
+// before one below the acceptance limit 2^256 - (2^256 mod bound) was found.
+func (s *randomStream) uniformWithRejections(bound *big.Int) (*big.Int, uint64) {
+	limit := acceptanceLimit(bound)
+	for rejections := uint64(0); ; rejections++ {
+		v := s.next()
+		if v.Cmp(limit) < 0 {
+			return v.Mod(v, bound), rejections
+		}
+	}
+}
+
+// acceptanceLimit returns the largest multiple of [bound] not exceeding 2^256. Words at or
+// above it are rejected by uniform.
+func acceptanceLimit(bound *big.Int) *big.Int {
+	return new(big.Int).Sub(two256, new(big.Int).Mod(two256, bound))
+}
+
+// uniformUint64 is like uniform for bounds that fit in a uint64. [bound] must be non-zero.
+func (s *randomStream) uniformUint64(bound uint64) uint64 {
+	return s.uniform(new(big.Int).SetUint64(bound)).Uint64()
+}
+
+// bernoulli returns true with probability [bps] / maxBasisPoints.
+func (s *randomStream) bernoulli(bps uint64) bool {
+	return s.uniformUint64(maxBasisPoints) < bps
+}
+
+// partialPermutation returns the first [k] entries of a uniformly random permutation of
+// [0, n) using a partial Fisher-Yates shuffle: position i is swapped with a uniform position
+// in [i, n), so only k draws are consumed. [k] must not exceed [n].
+func (s *randomStream) partialPermutation(n uint64, k uint64) []uint64 {
+	deck := make([]uint64, n)
+	for i := range deck {
+		deck[i] = uint64(i)
+	}
+	for i := uint64(0); i < k; i++ {
+		j := i + s.uniformUint64(n-i)
+		deck[i], deck[j] = deck[j], deck[i]
+	}
+	return deck[:k]
+}
+
+// permutation returns a uniformly random permutation of [0, n), as the full Fisher-Yates
+// shuffle partialPermutation(n, n).
+func (s *randomStream) permutation(n uint64) []*big.Int {
+	permutation := make([]*big.Int, n)
+	for i, v := range s.partialPermutation(n, n) {
+		permutation[i] = new(big.Int).SetUint64(v)
+	}
+	return permutation
+}
+
+// sparsePartialPermutation returns the same entries as partialPermutation for the same stream
+// state, but only records the positions the shuffle swapped, so its memory is bounded by [k]
+// rather than [n] and it suits populations too large to materialize.
+func (s *randomStream) sparsePartialPermutation(n uint64, k uint64) []uint64 {
+	swapped := make(map[uint64]uint64, 2*k)
+	at := func(i uint64) uint64 {
+		if v, ok := swapped[i]; ok {
+			return v
+		}
+		return i
+	}
+	out := make([]uint64, k)
+	for i := uint64(0); i < k; i++ {
+		j := i + s.uniformUint64(n-i)
+		out[i], swapped[j] = at(j), at(i)
+	}
+	return out
+}
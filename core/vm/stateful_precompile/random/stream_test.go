@@ -0,0 +1,142 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"hash"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestCounterByteOrder(t *testing.T) {
+	const n = 4
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 6)
+
+	key := serverSeed(randomNCSPRNGContractAddr)
+	nonce := common.BigToHash(big.NewInt(6))
+	draws := make(map[ByteOrder][]*big.Int)
+	for _, order := range []ByteOrder{BigEndian, LittleEndian} {
+		draws[order] = runRandomNCSPRNG(t, state, n, false, WithCounterByteOrder(order))
+
+		// Recompute every word from its definition with the counter in the chosen order.
+		for i, v := range draws[order] {
+			counter := common.BigToHash(big.NewInt(int64(i)))
+			if order == LittleEndian {
+				slices.Reverse(counter[:])
+			}
+			mac := hmac.New(sha256.New, key)
+			mac.Write(userSeed(key, testCaller))
+			mac.Write(nonce[:])
+			mac.Write(counter[:])
+			if want := new(big.Int).SetBytes(mac.Sum(nil)); v.Cmp(want) != 0 {
+				t.Errorf("order %d, value %d: got %x, want %x", order, i, v, want)
+			}
+		}
+
+		witness := NewRandomnessWitness(state.state, randomNCSPRNGContractAddr, testCaller, state.blockCtx, n, WithCounterByteOrder(order))
+		replayed, err := ComputeFromWitness(witness)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := range replayed {
+			if replayed[i].Cmp(draws[order][i]) != 0 {
+				t.Errorf("order %d, value %d: witness replay differs", order, i)
+			}
+		}
+	}
+	// The zero counter encodes identically in both orders, so the streams part after it.
+	for i := 1; i < n; i++ {
+		if draws[BigEndian][i].Cmp(draws[LittleEndian][i]) == 0 {
+			t.Errorf("value %d identical under both byte orders", i)
+		}
+	}
+}
+
+func TestRandomNCSPRNGWhitening(t *testing.T) {
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 4)
+
+	raw := runRandomNCSPRNG(t, state, 4, true)
+	whitened := runRandomNCSPRNG(t, state, 4, true, WithWhitening())
+	for i := range raw {
+		// Every whitened value is the keccak hash of the raw one.
+		if want := new(big.Int).SetBytes(crypto.Keccak256(common.BigToHash(raw[i]).Bytes())); whitened[i].Cmp(want) != 0 {
+			t.Errorf("value %d: got %x, want keccak of the raw value %x", i, whitened[i], want)
+		}
+	}
+	if again := runRandomNCSPRNG(t, state, 4, true, WithWhitening()); again[0].Cmp(whitened[0]) != 0 {
+		t.Fatal("same configuration yielded different whitened values")
+	}
+
+	// The witness reproduces whitened draws.
+	w := NewRandomnessWitness(state.state, randomNCSPRNGContractAddr, testCaller, state.blockCtx, 4, WithWhitening())
+	want, err := ComputeFromWitness(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		if want[i].Cmp(whitened[i]) != 0 {
+			t.Errorf("witness value %d: got %x, want %x", i, want[i], whitened[i])
+		}
+	}
+}
+
+func TestRandomNCSPRNGHMACHash(t *testing.T) {
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 4)
+
+	sha := runRandomNCSPRNG(t, state, 4, true)
+	if def := runRandomNCSPRNG(t, state, 4, true, WithHMACHash(SHA256)); def[0].Cmp(sha[0]) != 0 {
+		t.Fatal("SHA256 is not the default HMAC hash")
+	}
+	keccak := runRandomNCSPRNG(t, state, 4, true, WithHMACHash(Keccak256))
+	if again := runRandomNCSPRNG(t, state, 4, true, WithHMACHash(Keccak256)); again[0].Cmp(keccak[0]) != 0 {
+		t.Fatal("same configuration yielded different values")
+	}
+
+	// Both modes follow the documented derivation over their own hash.
+	key := serverSeed(randomNCSPRNGContractAddr)
+	nonce := common.BigToHash(big.NewInt(4)).Bytes()
+	for name, tt := range map[string]struct {
+		hash   func() hash.Hash
+		values []*big.Int
+	}{
+		"sha256":    {sha256.New, sha},
+		"keccak256": {func() hash.Hash { return crypto.NewKeccakState() }, keccak},
+	} {
+		for i := range tt.values {
+			mac := hmac.New(tt.hash, key)
+			mac.Write(userSeed(key, testCaller))
+			mac.Write(nonce)
+			mac.Write(common.BigToHash(big.NewInt(int64(i))).Bytes())
+			if want := new(big.Int).SetBytes(mac.Sum(nil)); tt.values[i].Cmp(want) != 0 {
+				t.Errorf("%s value %d: got %x, want %x", name, i, tt.values[i], want)
+			}
+		}
+	}
+	for i := range sha {
+		if sha[i].Cmp(keccak[i]) == 0 {
+			t.Errorf("value %d is the same under both hashes", i)
+		}
+	}
+
+	// The witness reproduces Keccak256 draws.
+	w := NewRandomnessWitness(state.state, randomNCSPRNGContractAddr, testCaller, state.blockCtx, 4, WithHMACHash(Keccak256))
+	want, err := ComputeFromWitness(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range want {
+		if want[i].Cmp(keccak[i]) != 0 {
+			t.Errorf("witness value %d: got %x, want %x", i, want[i], keccak[i])
+		}
+	}
+}
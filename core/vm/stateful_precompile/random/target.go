@@ -0,0 +1,63 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomTargetGasCost = 1024
+)
+
+var errInvalidDifficulty = errors.New("difficulty exceeds 256 bits")
+
+func PackRandomTargetInput(difficultyBits *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomTarget", difficultyBits)
+}
+
+func UnpackRandomTargetInput(input []byte) (uint, error) {
+	var difficultyBits *big.Int
+	if err := unpackInput("randomTarget", input, &difficultyBits); err != nil {
+		return 0, err
+	}
+	if !difficultyBits.IsUint64() || difficultyBits.Uint64() > 256 {
+		return 0, errInvalidDifficulty
+	}
+	return uint(difficultyBits.Uint64()), nil
+}
+
+func PackRandomTargetOutput(target *big.Int) ([]byte, error) {
+	return randomABI.Methods["randomTarget"].Outputs.Pack(target)
+}
+
+// generateRandomTarget returns a proof-of-work style threshold: a stream word whose top
+// [difficultyBits] bits are cleared, leaving the low 256-difficultyBits bits random.
+func generateRandomTarget(stream *randomStream, difficultyBits uint) *big.Int {
+	v := stream.next()
+	return v.Rsh(v, difficultyBits)
+}
+
+func RandomTargetFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomTargetGasCost); err != nil {
+		return nil, 0, err
+	}
+
+	difficultyBits, err := UnpackRandomTargetInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackRandomTargetOutput(generateRandomTarget(stream, difficultyBits))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,38 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRandomTarget(t *testing.T) {
+	const difficultyBits = 20
+	state := newMockAccessibleState()
+
+	seen := make(map[string]bool)
+	for nonce := uint64(0); nonce < 32; nonce++ {
+		state.state.SetNonce(testCaller, nonce)
+		target := mustRunMethod(t, state, testCaller, "randomTarget", big.NewInt(difficultyBits))[0].(*big.Int)
+		if target.BitLen() > 256-difficultyBits {
+			t.Fatalf("nonce %d: target %x has one of its top %d bits set", nonce, target, difficultyBits)
+		}
+		seen[target.String()] = true
+	}
+	if len(seen) != 32 {
+		t.Errorf("got %d distinct targets over 32 nonces", len(seen))
+	}
+
+	if target := mustRunMethod(t, state, testCaller, "randomTarget", big.NewInt(256))[0].(*big.Int); target.Sign() != 0 {
+		t.Errorf("got target %x for 256 difficulty bits, want 0", target)
+	}
+}
+
+func TestRandomTargetInvalidDifficulty(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "randomTarget", big.NewInt(257)); err != errInvalidDifficulty {
+		t.Fatalf("got %v, want %v", err, errInvalidDifficulty)
+	}
+}
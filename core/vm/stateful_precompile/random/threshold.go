@@ -0,0 +1,79 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomAboveThresholdBaseGas = 1024
+)
+
+var errInvalidThreshold = errors.New("open interval (threshold, max) must not be empty")
+
+// RandomAboveThresholdInput is the input of the randomAboveThreshold method.
+type RandomAboveThresholdInput struct {
+	Threshold *big.Int
+	Max       *big.Int
+	N         *big.Int
+}
+
+func PackRandomAboveThresholdInput(threshold *big.Int, max *big.Int, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomAboveThreshold", threshold, max, n)
+}
+
+func UnpackRandomAboveThresholdInput(input []byte) (RandomAboveThresholdInput, error) {
+	var in RandomAboveThresholdInput
+	if err := unpackInput("randomAboveThreshold", input, &in); err != nil {
+		return RandomAboveThresholdInput{}, err
+	}
+	// The interval holds at least one value only if threshold + 1 < max.
+	if new(big.Int).Add(in.Threshold, common.Big1).Cmp(in.Max) >= 0 {
+		return RandomAboveThresholdInput{}, errInvalidThreshold
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return RandomAboveThresholdInput{}, errTooManyValues
+	}
+	return in, nil
+}
+
+func PackRandomAboveThresholdOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomAboveThreshold"].Outputs.Pack(randomValues)
+}
+
+// generateRandomAboveThreshold draws [n] values uniformly from the open interval
+// (threshold, max) by offsetting an unbiased draw over its width from threshold + 1.
+func generateRandomAboveThreshold(stream *randomStream, threshold *big.Int, max *big.Int, n uint64) []*big.Int {
+	low := new(big.Int).Add(threshold, common.Big1)
+	high := new(big.Int).Sub(max, common.Big1)
+	values := make([]*big.Int, n)
+	for i := range values {
+		values[i] = uniformInRange(stream, low, high)
+	}
+	return values
+}
+
+func RandomAboveThresholdFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackRandomAboveThresholdInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	n := in.N.Uint64()
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomAboveThresholdBaseGas+n*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackRandomAboveThresholdOutput(generateRandomAboveThreshold(stream, in.Threshold, in.Max, n))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,35 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRandomAboveThreshold(t *testing.T) {
+	state := newMockAccessibleState()
+	threshold, max := big.NewInt(90), big.NewInt(100)
+
+	seen := make(map[int64]bool)
+	values := mustRunMethod(t, state, testCaller, "randomAboveThreshold", threshold, max, big.NewInt(MaxRandomValues))[0].([]*big.Int)
+	for _, v := range values {
+		if v.Cmp(threshold) <= 0 || v.Cmp(max) >= 0 {
+			t.Fatalf("value %v outside (%v, %v)", v, threshold, max)
+		}
+		seen[v.Int64()] = true
+	}
+	if len(seen) != 9 {
+		t.Errorf("got %d distinct values, want all 9 of the interval", len(seen))
+	}
+}
+
+func TestRandomAboveThresholdInvalidInterval(t *testing.T) {
+	state := newMockAccessibleState()
+	for _, bounds := range [][2]int64{{10, 10}, {10, 5}, {10, 11}} {
+		if _, _, err := runMethod(state, testCaller, "randomAboveThreshold", big.NewInt(bounds[0]), big.NewInt(bounds[1]), big.NewInt(1)); err != errInvalidThreshold {
+			t.Errorf("(%d, %d): got %v, want %v", bounds[0], bounds[1], err, errInvalidThreshold)
+		}
+	}
+}
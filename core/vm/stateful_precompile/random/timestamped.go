@@ -0,0 +1,59 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	TimestampedRandomBaseGas = 1024
+)
+
+// TimestampedRandomInput is the input of the timestampedRandom method.
+type TimestampedRandomInput struct {
+	N *big.Int
+}
+
+func PackTimestampedRandomInput(n *big.Int) ([]byte, error) {
+	return randomABI.Pack("timestampedRandom", n)
+}
+
+func UnpackTimestampedRandomInput(input []byte) (uint64, error) {
+	var in TimestampedRandomInput
+	if err := unpackInput("timestampedRandom", input, &in); err != nil {
+		return 0, err
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return 0, errTooManyValues
+	}
+	return in.N.Uint64(), nil
+}
+
+func PackTimestampedRandomOutput(randomValues []*big.Int, timestamp uint64) ([]byte, error) {
+	return randomABI.Methods["timestampedRandom"].Outputs.Pack(randomValues, new(big.Int).SetUint64(timestamp))
+}
+
+// TimestampedRandomFunc returns the caller's next values together with the timestamp of the
+// block they were generated in, so off-chain consumers can judge their freshness.
+func TimestampedRandomFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	n, err := UnpackTimestampedRandomInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, TimestampedRandomBaseGas+n*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	values := newCallerStream(addr, caller, accessibleState.GetStateDB()).values(n)
+	ret, err = PackTimestampedRandomOutput(values, accessibleState.GetBlockContext().Time)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,26 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestTimestampedRandom(t *testing.T) {
+	state := newMockAccessibleState()
+	state.blockCtx.Time = 1_712_345_678
+
+	out := mustRunMethod(t, state, testCaller, "timestampedRandom", big.NewInt(3))
+	values, timestamp := out[0].([]*big.Int), out[1].(*big.Int)
+	if timestamp.Uint64() != state.blockCtx.Time {
+		t.Errorf("timestamp %v, want %d", timestamp, state.blockCtx.Time)
+	}
+	stream := newCallerStream(randomNCSPRNGContractAddr, testCaller, state.state)
+	for i, v := range values {
+		if want := stream.next(); v.Cmp(want) != 0 {
+			t.Errorf("value %d: got %x, want %x", i, v, want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	TokenRandomBaseGas = 1024
+)
+
+// TokenRandomInput is the input of the tokenRandom method.
+type TokenRandomInput struct {
+	TokenId *big.Int
+	N       *big.Int
+}
+
+func PackTokenRandomInput(tokenId *big.Int, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("tokenRandom", tokenId, n)
+}
+
+func UnpackTokenRandomInput(input []byte) (TokenRandomInput, error) {
+	var in TokenRandomInput
+	if err := unpackInput("tokenRandom", input, &in); err != nil {
+		return TokenRandomInput{}, err
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return TokenRandomInput{}, errTooManyValues
+	}
+	return in, nil
+}
+
+func PackTokenRandomOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["tokenRandom"].Outputs.Pack(randomValues)
+}
+
+// generateTokenRandom returns the first [n] values of the stream of [tokenId]. The stream is
+// keyed by the token alone, so every caller sees the same traits for a given token.
+func generateTokenRandom(precompileAddr common.Address, tokenId *big.Int, n uint64) []*big.Int {
+	return newKeyedStream(precompileAddr, "token", common.BigToHash(tokenId).Bytes()).values(n)
+}
+
+func TokenRandomFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackTokenRandomInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	n := in.N.Uint64()
+	if remainingGas, err = contract.DeductGas(suppliedGas, TokenRandomBaseGas+n*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	ret, err = PackTokenRandomOutput(generateTokenRandom(addr, in.TokenId, n))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
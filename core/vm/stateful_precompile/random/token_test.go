@@ -0,0 +1,40 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestTokenRandomDeterministic(t *testing.T) {
+	state := newMockAccessibleState()
+	other := common.HexToAddress("0x00000000000000000000000000000000000beef0")
+	state.state.SetNonce(testCaller, 5)
+	state.state.SetNonce(other, 42)
+
+	traits := mustRunMethod(t, state, testCaller, "tokenRandom", big.NewInt(1234), big.NewInt(8))[0].([]*big.Int)
+	again := mustRunMethod(t, state, other, "tokenRandom", big.NewInt(1234), big.NewInt(8))[0].([]*big.Int)
+	for i := range traits {
+		if traits[i].Cmp(again[i]) != 0 {
+			t.Fatalf("trait %d differs between callers: %x != %x", i, traits[i], again[i])
+		}
+	}
+
+	different := mustRunMethod(t, state, testCaller, "tokenRandom", big.NewInt(1235), big.NewInt(8))[0].([]*big.Int)
+	for i := range traits {
+		if traits[i].Cmp(different[i]) == 0 {
+			t.Fatalf("trait %d identical for different tokens", i)
+		}
+	}
+}
+
+func TestTokenRandomTooMany(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "tokenRandom", big.NewInt(1), big.NewInt(MaxRandomValues+1)); err != errTooManyValues {
+		t.Fatalf("got %v, want %v", err, errTooManyValues)
+	}
+}
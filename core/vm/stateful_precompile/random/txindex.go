@@ -0,0 +1,72 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	RandomByTxIndexBaseGas = 1024
+)
+
+// RandomByTxIndexInput is the input of the randomByTxIndex method.
+type RandomByTxIndexInput struct {
+	N *big.Int
+}
+
+func PackRandomByTxIndexInput(n *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomByTxIndex", n)
+}
+
+func UnpackRandomByTxIndexInput(input []byte) (uint64, error) {
+	var in RandomByTxIndexInput
+	if err := unpackInput("randomByTxIndex", input, &in); err != nil {
+		return 0, err
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return 0, errTooManyValues
+	}
+	return in.N.Uint64(), nil
+}
+
+func PackRandomByTxIndexOutput(randomValues []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomByTxIndex"].Outputs.Pack(randomValues)
+}
+
+// ReplayRandomByTxIndex recomputes the [n] values randomByTxIndex returned to [caller] in
+// transaction [txIndex] of block [blockNumber]. The draw depends on nothing else, so it can be
+// audited from those coordinates alone.
+func ReplayRandomByTxIndex(precompileAddr common.Address, caller common.Address, blockNumber uint64, txIndex uint64, n uint64) []*big.Int {
+	key := serverSeed(precompileAddr)
+	seed := crypto.Keccak256(
+		userSeed(key, caller),
+		common.BigToHash(new(big.Int).SetUint64(blockNumber)).Bytes(),
+		common.BigToHash(new(big.Int).SetUint64(txIndex)).Bytes(),
+	)
+	return newRandomStream(key, seed, 0).values(n)
+}
+
+func RandomByTxIndexFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	n, err := UnpackRandomByTxIndexInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomByTxIndexBaseGas+n*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	blockNumber := accessibleState.GetBlockContext().BlockNumber.Uint64()
+	txIndex := uint64(accessibleState.GetStateDB().GetTxIndex())
+	ret, err = PackRandomByTxIndexOutput(ReplayRandomByTxIndex(addr, caller, blockNumber, txIndex, n))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
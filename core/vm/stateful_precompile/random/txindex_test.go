@@ -0,0 +1,32 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRandomByTxIndexReplay(t *testing.T) {
+	state := newMockAccessibleState()
+	state.blockCtx.BlockNumber = big.NewInt(4242)
+	state.state.txIndex = 17
+
+	live := mustRunMethod(t, state, testCaller, "randomByTxIndex", big.NewInt(5))[0].([]*big.Int)
+	replay := ReplayRandomByTxIndex(randomNCSPRNGContractAddr, testCaller, 4242, 17, 5)
+	if len(live) != len(replay) {
+		t.Fatalf("got %d values, replay has %d", len(live), len(replay))
+	}
+	for i := range live {
+		if live[i].Cmp(replay[i]) != 0 {
+			t.Fatalf("value %d: live %x, replay %x", i, live[i], replay[i])
+		}
+	}
+
+	// Another transaction of the same block must get a different draw.
+	other := ReplayRandomByTxIndex(randomNCSPRNGContractAddr, testCaller, 4242, 18, 5)
+	if other[0].Cmp(live[0]) == 0 {
+		t.Fatalf("different transaction indexes produced the same draw")
+	}
+}
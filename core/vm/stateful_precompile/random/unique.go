@@ -0,0 +1,82 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	UniquePerTxGasCost = 1024 + contract.ReadGasCostPerSlot + contract.WriteGasCostPerSlot
+	// UniquePerTxRetryGas is charged for every value skipped because it was already used.
+	UniquePerTxRetryGas = contract.ReadGasCostPerSlot
+)
+
+func PackUniquePerTxInput(purposeTag common.Hash) ([]byte, error) {
+	return randomABI.Pack("uniquePerTx", [32]byte(purposeTag))
+}
+
+func UnpackUniquePerTxInput(input []byte) (common.Hash, error) {
+	var purposeTag [32]byte
+	if err := unpackInput("uniquePerTx", input, &purposeTag); err != nil {
+		return common.Hash{}, err
+	}
+	return purposeTag, nil
+}
+
+func PackUniquePerTxOutput(value *big.Int) ([]byte, error) {
+	return randomABI.Methods["uniquePerTx"].Outputs.Pack(value)
+}
+
+// uniqueValueKey returns the slot marking [value] as used for [purposeTag].
+func uniqueValueKey(purposeTag common.Hash, value *big.Int) common.Hash {
+	return stateKey("unique.used", purposeTag.Bytes(), common.BigToHash(value).Bytes())
+}
+
+// uniquePerTx draws from the stream of [purposeTag] in the transaction [txHash] the first value
+// never returned before for that purpose, marks it as used and returns it together with the
+// number of used values skipped on the way. Tracking used values makes uniqueness a guarantee
+// rather than a likely outcome, even when the same purpose is drawn twice in a transaction.
+func uniquePerTx(state contract.StateDB, addr common.Address, purposeTag common.Hash, txHash common.Hash) (*big.Int, uint64) {
+	stream := newKeyedStream(addr, "uniquePerTx", append(purposeTag.Bytes(), txHash.Bytes()...))
+	for skipped := uint64(0); ; skipped++ {
+		v := stream.next()
+		key := uniqueValueKey(purposeTag, v)
+		if state.GetState(addr, key) == (common.Hash{}) {
+			state.SetState(addr, key, common.BytesToHash([]byte{1}))
+			return v, skipped
+		}
+	}
+}
+
+func UniquePerTxFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	if remainingGas, err = contract.DeductGas(suppliedGas, UniquePerTxGasCost); err != nil {
+		return nil, 0, err
+	}
+	if readOnly {
+		return nil, remainingGas, vm.ErrWriteProtection
+	}
+
+	purposeTag, err := UnpackUniquePerTxInput(input)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	state := accessibleState.GetStateDB()
+	value, skipped := uniquePerTx(state, addr, purposeTag, state.GetTxHash())
+	if remainingGas, err = contract.DeductGas(remainingGas, skipped*UniquePerTxRetryGas); err != nil {
+		return nil, 0, err
+	}
+
+	ret, err = PackUniquePerTxOutput(value)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
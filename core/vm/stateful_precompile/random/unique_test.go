@@ -0,0 +1,50 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+func TestUniquePerTx(t *testing.T) {
+	state := newMockAccessibleState()
+	purpose := [32]byte(common.HexToHash("0x7a6"))
+
+	seen := make(map[string]bool)
+	for tx := int64(0); tx < 20; tx++ {
+		state.state.txHash = common.BigToHash(big.NewInt(tx))
+		// Drawing twice for the same purpose in one transaction still yields distinct values.
+		for i := 0; i < 2; i++ {
+			v := mustRunMethod(t, state, testCaller, "uniquePerTx", purpose)[0].(*big.Int)
+			if seen[v.String()] {
+				t.Fatalf("tx %d: value %x reused", tx, v)
+			}
+			seen[v.String()] = true
+		}
+	}
+
+	// A transaction whose first value was already used for the purpose gets the next one.
+	state.state.txHash = common.HexToHash("0x1234")
+	stream := newKeyedStream(randomNCSPRNGContractAddr, "uniquePerTx", append(common.Hash(purpose).Bytes(), state.state.txHash.Bytes()...))
+	first, second := stream.next(), stream.next()
+	state.state.SetState(randomNCSPRNGContractAddr, uniqueValueKey(purpose, first), common.BytesToHash([]byte{1}))
+	if v := mustRunMethod(t, state, testCaller, "uniquePerTx", purpose)[0].(*big.Int); v.Cmp(second) != 0 {
+		t.Fatalf("got %x, want the next unused value %x", v, second)
+	}
+}
+
+func TestUniquePerTxReadOnly(t *testing.T) {
+	state := newMockAccessibleState()
+	input, err := PackUniquePerTxInput(common.Hash{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := CreateRandomNCSPRNGPrecompile(DefaultConfig()).Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, true); err != vm.ErrWriteProtection {
+		t.Fatalf("got %v, want %v", err, vm.ErrWriteProtection)
+	}
+}
@@ -0,0 +1,90 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	RandomWalkBaseGas = 1024
+)
+
+var errInvalidWalkBounds = errors.New("random walk requires min <= start <= max")
+
+// RandomWalkInput is the input of the randomWalk method.
+type RandomWalkInput struct {
+	Steps    *big.Int
+	StepSize *big.Int
+	Start    *big.Int
+	Min      *big.Int
+	Max      *big.Int
+}
+
+func PackRandomWalkInput(steps, stepSize, start, min, max *big.Int) ([]byte, error) {
+	return randomABI.Pack("randomWalk", steps, stepSize, start, min, max)
+}
+
+func UnpackRandomWalkInput(input []byte) (RandomWalkInput, error) {
+	var in RandomWalkInput
+	if err := unpackInput("randomWalk", input, &in); err != nil {
+		return RandomWalkInput{}, err
+	}
+	if !in.Steps.IsUint64() || in.Steps.Uint64() > MaxRandomValues {
+		return RandomWalkInput{}, errTooManyValues
+	}
+	if in.Min.Cmp(in.Start) > 0 || in.Start.Cmp(in.Max) > 0 {
+		return RandomWalkInput{}, errInvalidWalkBounds
+	}
+	return in, nil
+}
+
+func PackRandomWalkOutput(positions []*big.Int) ([]byte, error) {
+	return randomABI.Methods["randomWalk"].Outputs.Pack(positions)
+}
+
+// generateRandomWalk returns the position after each step of a walk from in.Start. Every step
+// draws a fair direction from the stream and moves by in.StepSize, clamping the position to
+// [in.Min, in.Max] instead of leaving the interval.
+func generateRandomWalk(stream *randomStream, in RandomWalkInput) []*big.Int {
+	positions := make([]*big.Int, in.Steps.Uint64())
+	position := new(big.Int).Set(in.Start)
+	for i := range positions {
+		if stream.uniformUint64(2) == 1 {
+			position.Add(position, in.StepSize)
+			if position.Cmp(in.Max) > 0 {
+				position.Set(in.Max)
+			}
+		} else {
+			position.Sub(position, in.StepSize)
+			if position.Cmp(in.Min) < 0 {
+				position.Set(in.Min)
+			}
+		}
+		positions[i] = new(big.Int).Set(position)
+	}
+	return positions
+}
+
+func RandomWalkFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackRandomWalkInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	if remainingGas, err = contract.DeductGas(suppliedGas, RandomWalkBaseGas+in.Steps.Uint64()*RandomPerValueGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackRandomWalkOutput(generateRandomWalk(stream, in))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
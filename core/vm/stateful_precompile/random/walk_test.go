@@ -0,0 +1,48 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRandomWalk(t *testing.T) {
+	state := newMockAccessibleState()
+	steps, stepSize, start := big.NewInt(500), big.NewInt(7), big.NewInt(20)
+	min, max := big.NewInt(3), big.NewInt(60)
+
+	walk := func(nonce uint64) []*big.Int {
+		state.state.SetNonce(testCaller, nonce)
+		return mustRunMethod(t, state, testCaller, "randomWalk", steps, stepSize, start, min, max)[0].([]*big.Int)
+	}
+	first := walk(1)
+	if len(first) != int(steps.Int64()) {
+		t.Fatalf("got %d positions, want %d", len(first), steps)
+	}
+	for i, p := range first {
+		if p.Cmp(min) < 0 || p.Cmp(max) > 0 {
+			t.Fatalf("step %d: position %v outside [%v, %v]", i, p, min, max)
+		}
+	}
+
+	again, other := walk(1), walk(2)
+	same := true
+	for i := range first {
+		if first[i].Cmp(again[i]) != 0 {
+			t.Fatalf("step %d: walk not reproducible for the same nonce", i)
+		}
+		same = same && first[i].Cmp(other[i]) == 0
+	}
+	if same {
+		t.Fatalf("different nonces produced the same walk")
+	}
+}
+
+func TestRandomWalkInvalidBounds(t *testing.T) {
+	state := newMockAccessibleState()
+	if _, _, err := runMethod(state, testCaller, "randomWalk", big.NewInt(1), big.NewInt(1), big.NewInt(10), big.NewInt(11), big.NewInt(20)); err != errInvalidWalkBounds {
+		t.Fatalf("got %v, want %v", err, errInvalidWalkBounds)
+	}
+}
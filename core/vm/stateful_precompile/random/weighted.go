@@ -0,0 +1,119 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	WeightedPickBaseGas = 1024
+	// WeightedPickPerWeightGas covers adding a weight to the running sum.
+	WeightedPickPerWeightGas = 16
+	// WeightedPickPerPickGas covers drawing a pick and packing it.
+	WeightedPickPerPickGas = 64
+	// WeightedPickPerSearchStepGas covers one step of the binary search locating a pick.
+	WeightedPickPerSearchStepGas = 16
+
+	// MaxWeightedPickWeights bounds the number of weights of weightedPick.
+	MaxWeightedPickWeights = MaxRandomValues
+)
+
+var errWeightSumOverflow = errors.New("sum of weights overflows uint256")
+
+// WeightedPickInput is the input of the weightedPick method.
+type WeightedPickInput struct {
+	Weights []*big.Int
+	N       *big.Int
+}
+
+func PackWeightedPickInput(weights []*big.Int, n *big.Int) ([]byte, error) {
+	return randomABI.Pack("weightedPick", weights, n)
+}
+
+func UnpackWeightedPickInput(input []byte) (WeightedPickInput, error) {
+	var in WeightedPickInput
+	if err := unpackInput("weightedPick", input, &in); err != nil {
+		return WeightedPickInput{}, err
+	}
+	if len(in.Weights) == 0 || len(in.Weights) > MaxWeightedPickWeights {
+		return WeightedPickInput{}, errInvalidWeightCount
+	}
+	if !in.N.IsUint64() || in.N.Uint64() > MaxRandomValues {
+		return WeightedPickInput{}, errTooManyValues
+	}
+	return in, nil
+}
+
+func PackWeightedPickOutput(picks []*big.Int) ([]byte, error) {
+	return randomABI.Methods["weightedPick"].Outputs.Pack(picks)
+}
+
+// weightedPickGas returns the gas charged for drawing [n] picks among [weights] weights: the
+// running sums are built once, then every pick takes a binary search of about log2(weights)
+// steps.
+func weightedPickGas(weights uint64, n uint64) uint64 {
+	searchSteps := uint64(bits.Len64(weights))
+	return WeightedPickBaseGas + weights*WeightedPickPerWeightGas + n*(WeightedPickPerPickGas+searchSteps*WeightedPickPerSearchStepGas)
+}
+
+// cumulativeWeights returns the running sums of [weights], the last of which is the total
+// weight. The total must fit in a uint256, as Solidity callers could not sum the weights
+// themselves otherwise.
+func cumulativeWeights(weights []*big.Int) ([]*big.Int, error) {
+	cumulative := make([]*big.Int, len(weights))
+	total := new(big.Int)
+	for i, w := range weights {
+		total.Add(total, w)
+		if total.Cmp(math.MaxBig256) > 0 {
+			return nil, errWeightSumOverflow
+		}
+		cumulative[i] = new(big.Int).Set(total)
+	}
+	return cumulative, nil
+}
+
+// generateWeightedPick draws [n] indices of [weights], each with probability proportional to
+// its weight. Picks are independent, so an index can be picked more than once.
+func generateWeightedPick(stream *randomStream, weights []*big.Int, n uint64) ([]*big.Int, error) {
+	cumulative, err := cumulativeWeights(weights)
+	if err != nil {
+		return nil, err
+	}
+	if cumulative[len(cumulative)-1].Sign() == 0 {
+		return nil, errZeroTotalWeight
+	}
+	return pickCumulative(stream, cumulative, n), nil
+}
+
+// WeightedPickFunc draws [n] indices of [weights] with probability proportional to their
+// weight, e.g. the rarity tiers of a loot box.
+func WeightedPickFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	in, err := UnpackWeightedPickInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	n := in.N.Uint64()
+	if remainingGas, err = contract.DeductGas(suppliedGas, weightedPickGas(uint64(len(in.Weights)), n)); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	picks, err := generateWeightedPick(stream, in.Weights, n)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+	ret, err = PackWeightedPickOutput(picks)
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
@@ -0,0 +1,93 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+func countWeightedPicks(t *testing.T, weights []*big.Int, n int64) []int {
+	t.Helper()
+	state := newMockAccessibleState()
+	picks := mustRunMethod(t, state, testCaller, "weightedPick", weights, big.NewInt(n))[0].([]*big.Int)
+	if int64(len(picks)) != n {
+		t.Fatalf("got %d picks, want %d", len(picks), n)
+	}
+	counts := make([]int, len(weights))
+	for _, p := range picks {
+		if !p.IsInt64() || p.Int64() >= int64(len(weights)) {
+			t.Fatalf("pick %v out of range", p)
+		}
+		counts[p.Int64()]++
+	}
+	return counts
+}
+
+func TestWeightedPickExtremeWeights(t *testing.T) {
+	huge := new(big.Int).Lsh(big.NewInt(1), 200)
+	weights := []*big.Int{big.NewInt(1), huge, big.NewInt(1), big.NewInt(1)}
+	counts := countWeightedPicks(t, weights, MaxRandomValues)
+	if counts[1] != MaxRandomValues {
+		t.Fatalf("heavy index picked %d times of %d, want every time", counts[1], MaxRandomValues)
+	}
+}
+
+func TestWeightedPickUniform(t *testing.T) {
+	weights := []*big.Int{big.NewInt(1), big.NewInt(1), big.NewInt(1)}
+	counts := countWeightedPicks(t, weights, MaxRandomValues)
+	// Each index expects about 341 picks with a standard deviation of about 15.
+	for i, c := range counts {
+		if c < 280 || c > 402 {
+			t.Errorf("index %d picked %d times of %d, want about 341", i, c, MaxRandomValues)
+		}
+	}
+}
+
+func TestWeightedPickInvalid(t *testing.T) {
+	state := newMockAccessibleState()
+	tests := []struct {
+		name    string
+		weights []*big.Int
+		want    error
+	}{
+		{"no weights", []*big.Int{}, errInvalidWeightCount},
+		{"zero weights", []*big.Int{big.NewInt(0), big.NewInt(0)}, errZeroTotalWeight},
+		{"overflow", []*big.Int{math.MaxBig256, big.NewInt(1)}, errWeightSumOverflow},
+	}
+	for _, tt := range tests {
+		if _, _, err := runMethod(state, testCaller, "weightedPick", tt.weights, big.NewInt(1)); err != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	// A total of exactly the largest uint256 is fine.
+	if _, _, err := runMethod(state, testCaller, "weightedPick", []*big.Int{new(big.Int).Sub(math.MaxBig256, big.NewInt(1)), big.NewInt(1)}, big.NewInt(1)); err != nil {
+		t.Errorf("total of MaxUint256: %v", err)
+	}
+}
+
+func TestWeightedPickGas(t *testing.T) {
+	state := newMockAccessibleState()
+	weights := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4), big.NewInt(5)}
+	for _, n := range []uint64{0, 1, MaxRandomValues} {
+		_, remaining, err := runMethod(state, testCaller, "weightedPick", weights, new(big.Int).SetUint64(n))
+		if err != nil {
+			t.Fatalf("n=%d: %v", n, err)
+		}
+		// Five weights take a binary search of three steps per pick.
+		want := WeightedPickBaseGas + 5*WeightedPickPerWeightGas + n*(WeightedPickPerPickGas+3*WeightedPickPerSearchStepGas)
+		if used := testGas - remaining; used != uint64(want) {
+			t.Errorf("n=%d: used %d gas, want %d", n, used, want)
+		}
+	}
+
+	// One gas short of the full cost fails before picking anything.
+	suppliedGas := weightedPickGas(uint64(len(weights)), MaxRandomValues) - 1
+	if _, remaining, err := runMethodWithGas(state, testCaller, suppliedGas, "weightedPick", weights, big.NewInt(MaxRandomValues)); err != vm.ErrOutOfGas || remaining != 0 {
+		t.Errorf("got %d gas left and %v, want 0 and %v", remaining, err, vm.ErrOutOfGas)
+	}
+}
@@ -0,0 +1,113 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+const (
+	DrawWinnersBaseGas    = 1024
+	DrawWinnersPerTierGas = 64
+
+	// MaxPrizeTiers bounds the number of prize tiers drawn by drawWinners.
+	MaxPrizeTiers = 256
+)
+
+var (
+	errTooManyTiers     = errors.New("too many prize tiers")
+	errTooManyWinners   = errors.New("prize tiers hold more winners than there are entrants")
+	errEntrantsOverflow = errors.New("number of entrants overflows uint64")
+)
+
+// DrawWinnersInput is the input of the drawWinners method.
+type DrawWinnersInput struct {
+	NumEntrants *big.Int
+	TierCounts  []*big.Int
+}
+
+func PackDrawWinnersInput(numEntrants *big.Int, tierCounts []*big.Int) ([]byte, error) {
+	return randomABI.Pack("drawWinners", numEntrants, tierCounts)
+}
+
+// UnpackDrawWinnersInput returns the number of entrants and the number of winners of every
+// tier. There must be at most MaxPrizeTiers tiers, holding at most MaxRandomValues winners in
+// total, and no more than there are entrants.
+func UnpackDrawWinnersInput(input []byte) (uint64, []uint64, error) {
+	var in DrawWinnersInput
+	if err := unpackInput("drawWinners", input, &in); err != nil {
+		return 0, nil, err
+	}
+	if len(in.TierCounts) > MaxPrizeTiers {
+		return 0, nil, errTooManyTiers
+	}
+	tierCounts := make([]uint64, len(in.TierCounts))
+	var total uint64
+	for i, count := range in.TierCounts {
+		if !count.IsUint64() || count.Uint64() > MaxRandomValues-total {
+			return 0, nil, errTooManyValues
+		}
+		tierCounts[i] = count.Uint64()
+		total += tierCounts[i]
+	}
+	if !in.NumEntrants.IsUint64() {
+		return 0, nil, errEntrantsOverflow
+	}
+	if in.NumEntrants.Uint64() < total {
+		return 0, nil, errTooManyWinners
+	}
+	return in.NumEntrants.Uint64(), tierCounts, nil
+}
+
+func PackDrawWinnersOutput(winners [][]*big.Int) ([]byte, error) {
+	return randomABI.Methods["drawWinners"].Outputs.Pack(winners)
+}
+
+// drawWinners draws the winners of every tier among entrants 0 to [numEntrants]-1. A single
+// partial Fisher-Yates shuffle provides the winners of all tiers in order, so no entrant wins
+// twice, even across tiers.
+func drawWinners(stream *randomStream, numEntrants uint64, tierCounts []uint64) [][]*big.Int {
+	var total uint64
+	for _, count := range tierCounts {
+		total += count
+	}
+	order := stream.sparsePartialPermutation(numEntrants, total)
+	winners := make([][]*big.Int, len(tierCounts))
+	for i, count := range tierCounts {
+		winners[i] = make([]*big.Int, count)
+		for j := range winners[i] {
+			winners[i][j] = new(big.Int).SetUint64(order[0])
+			order = order[1:]
+		}
+	}
+	return winners
+}
+
+// DrawWinnersFunc draws distinct winners for every prize tier of a lottery in one call.
+func DrawWinnersFunc(accessibleState contract.AccessibleState, caller common.Address, addr common.Address, input []byte, suppliedGas uint64, readOnly bool) (ret []byte, remainingGas uint64, err error) {
+	numEntrants, tierCounts, err := UnpackDrawWinnersInput(input)
+	if err != nil {
+		return nil, suppliedGas, err
+	}
+	var total uint64
+	for _, count := range tierCounts {
+		total += count
+	}
+	requiredGas := DrawWinnersBaseGas + uint64(len(tierCounts))*DrawWinnersPerTierGas + total*RandomPerValueGas
+	if remainingGas, err = contract.DeductGas(suppliedGas, requiredGas); err != nil {
+		return nil, 0, err
+	}
+
+	stream := newCallerStream(addr, caller, accessibleState.GetStateDB())
+	ret, err = PackDrawWinnersOutput(drawWinners(stream, numEntrants, tierCounts))
+	if err != nil {
+		return nil, remainingGas, err
+	}
+
+	return ret, remainingGas, nil
+}
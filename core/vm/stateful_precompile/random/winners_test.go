@@ -0,0 +1,95 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestDrawWinners(t *testing.T) {
+	state := newMockAccessibleState()
+	tierCounts := []*big.Int{big.NewInt(1), big.NewInt(3), big.NewInt(10)}
+	for _, numEntrants := range []int64{14, 20, 1_000_000} {
+		out := mustRunMethod(t, state, testCaller, "drawWinners", big.NewInt(numEntrants), tierCounts)
+		winners := out[0].([][]*big.Int)
+		if len(winners) != len(tierCounts) {
+			t.Fatalf("got %d tiers, want %d", len(winners), len(tierCounts))
+		}
+		seen := make(map[uint64]bool)
+		for tier, tierWinners := range winners {
+			if len(tierWinners) != int(tierCounts[tier].Int64()) {
+				t.Errorf("tier %d: got %d winners, want %d", tier, len(tierWinners), tierCounts[tier])
+			}
+			for _, w := range tierWinners {
+				if w.Cmp(big.NewInt(numEntrants)) >= 0 {
+					t.Errorf("tier %d: winner %d out of range [0, %d)", tier, w, numEntrants)
+				}
+				if seen[w.Uint64()] {
+					t.Errorf("tier %d: entrant %d won twice", tier, w)
+				}
+				seen[w.Uint64()] = true
+			}
+		}
+	}
+}
+
+func TestSparsePartialPermutation(t *testing.T) {
+	for _, n := range []uint64{1, 5, 52, 300} {
+		for _, k := range []uint64{0, 1, n / 2, n} {
+			dense := newRandomStream([]byte("key"), []byte("seed"), n).partialPermutation(n, k)
+			sparse := newRandomStream([]byte("key"), []byte("seed"), n).sparsePartialPermutation(n, k)
+			for i := range dense {
+				if dense[i] != sparse[i] {
+					t.Fatalf("n=%d, k=%d, entry %d: got %d, want %d", n, k, i, sparse[i], dense[i])
+				}
+			}
+		}
+	}
+}
+
+func TestDrawWinnersInvalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		numEntrants *big.Int
+		tierCounts  []*big.Int
+		want        error
+	}{
+		{"too many winners", big.NewInt(3), []*big.Int{big.NewInt(2), big.NewInt(2)}, errTooManyWinners},
+		{"too many values", big.NewInt(1 << 20), []*big.Int{big.NewInt(MaxRandomValues), big.NewInt(1)}, errTooManyValues},
+		{"entrants overflow", new(big.Int).Lsh(big.NewInt(1), 64), []*big.Int{big.NewInt(1)}, errEntrantsOverflow},
+		{"tier overflow", big.NewInt(math.MaxInt64), []*big.Int{new(big.Int).Lsh(big.NewInt(1), 64)}, errTooManyValues},
+		{"too many tiers", big.NewInt(10), emptyTiers(MaxPrizeTiers + 1), errTooManyTiers},
+	}
+	state := newMockAccessibleState()
+	for _, tt := range tests {
+		if _, _, err := runMethod(state, testCaller, "drawWinners", tt.numEntrants, tt.tierCounts); err != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestDrawWinnersGas(t *testing.T) {
+	state := newMockAccessibleState()
+	// Empty tiers draw nobody but are still paid for.
+	tierCounts := emptyTiers(MaxPrizeTiers)
+	tierCounts[0] = big.NewInt(3)
+	_, remaining, err := runMethod(state, testCaller, "drawWinners", big.NewInt(10), tierCounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if used, want := testGas-remaining, uint64(DrawWinnersBaseGas+MaxPrizeTiers*DrawWinnersPerTierGas+3*RandomPerValueGas); used != want {
+		t.Fatalf("used %d gas, want %d", used, want)
+	}
+}
+
+// emptyTiers returns the counts of [n] tiers without winners.
+func emptyTiers(n int) []*big.Int {
+	tierCounts := make([]*big.Int, n)
+	for i := range tierCounts {
+		tierCounts[i] = new(big.Int)
+	}
+	return tierCounts
+}
@@ -0,0 +1,74 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/core/vm/stateful_precompile/contract"
+)
+
+var errEmptyServerSeed = errors.New("witness has no server seed")
+
+// RandomnessWitness holds every input the values of a randomNCSPRNG call are derived from, so
+// that they can be recomputed without access to the chain state, e.g. inside a rollup proof.
+type RandomnessWitness struct {
+	// ServerSeed is the server seed the call was keyed with, after any block seed, epoch,
+	// parent hash, PREVRANDAO and call counter mixing.
+	ServerSeed common.Hash
+	// Caller is the account the values were drawn for.
+	Caller common.Address
+	// Salt is the salt of the call, zero for the unsalted randomNCSPRNG(uint256).
+	Salt common.Hash
+	// Nonce is the account nonce of Caller at the time of the call.
+	Nonce uint64
+	// WarmupDiscard is the number of leading words skipped by the precompile.
+	WarmupDiscard uint
+	// CounterByteOrder is the byte order of the stream counter used by the precompile.
+	CounterByteOrder ByteOrder
+	// HMACHash is the hash function of the stream HMAC used by the precompile.
+	HMACHash HMACHash
+	// Whitened is set when the precompile whitened the values, see WithWhitening.
+	Whitened bool
+	// N is the number of values drawn.
+	N uint64
+}
+
+// NewRandomnessWitness captures the witness of a call to randomNCSPRNG for [n] values made by
+// [caller] against [state] in the block of [blockCtx], on a precompile at [precompileAddr]
+// built with [opts]. The witness of a salted call additionally needs its Salt set.
+func NewRandomnessWitness(state contract.StateDB, precompileAddr common.Address, caller common.Address, blockCtx *vm.BlockContext, n uint64, opts ...Option) RandomnessWitness {
+	return newRandomnessWitness(state, precompileAddr, caller, blockCtx, n, newOptions(opts))
+}
+
+// newRandomnessWitness is NewRandomnessWitness for the already applied [options].
+func newRandomnessWitness(state contract.StateDB, precompileAddr common.Address, caller common.Address, blockCtx *vm.BlockContext, n uint64, options options) RandomnessWitness {
+	seed, _ := ncsprngServerSeed(state, precompileAddr, blockCtx, options)
+	seed, _ = callCounterServerSeed(state, precompileAddr, caller, seed, options)
+	return RandomnessWitness{
+		ServerSeed:       common.BytesToHash(seed),
+		Caller:           caller,
+		Nonce:            state.GetNonce(caller),
+		WarmupDiscard:    options.warmupDiscard,
+		CounterByteOrder: options.counterByteOrder,
+		HMACHash:         options.hmacHash,
+		Whitened:         options.whitening,
+		N:                n,
+	}
+}
+
+// ComputeFromWitness returns the values of the randomNCSPRNG call described by [w].
+func ComputeFromWitness(w RandomnessWitness) ([]*big.Int, error) {
+	if w.ServerSeed == (common.Hash{}) {
+		return nil, errEmptyServerSeed
+	}
+	stream := newSeededNCSPRNGStream(w.ServerSeed.Bytes(), w.Caller, w.Salt, w.Nonce, w.WarmupDiscard, w.CounterByteOrder, w.HMACHash)
+	if w.Whitened {
+		stream.whiten()
+	}
+	return stream.values(w.N), nil
+}
@@ -0,0 +1,60 @@
+// (c) 2023, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package random
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestComputeFromWitness(t *testing.T) {
+	const (
+		n           = 4
+		warmup      = 2
+		epochLength = 10
+	)
+	state := newMockAccessibleState()
+	state.state.SetNonce(testCaller, 11)
+	state.state.SetState(randomNCSPRNGContractAddr, serverSeedActiveKey, common.HexToHash("0x5eed"))
+
+	input, err := PackRandomNCSPRNGInput(big.NewInt(n))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret, _, err := CreateRandomNCSPRNGPrecompile(DefaultConfig(), WithWarmupDiscard(warmup), WithEpochLength(epochLength)).Run(state, testCaller, randomNCSPRNGContractAddr, input, testGas, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := randomABI.Methods["randomNCSPRNG"].Outputs.Unpack(ret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := out[0].([]*big.Int)
+
+	witness := NewRandomnessWitness(state.state, randomNCSPRNGContractAddr, testCaller, state.blockCtx, n, WithWarmupDiscard(warmup), WithEpochLength(epochLength))
+	// The witness must not depend on live state once captured.
+	state.state.SetNonce(testCaller, 12)
+	state.state.SetState(randomNCSPRNGContractAddr, serverSeedActiveKey, common.Hash{})
+
+	replayed, err := ComputeFromWitness(witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed) != len(values) {
+		t.Fatalf("got %d values, want %d", len(replayed), len(values))
+	}
+	for i := range values {
+		if replayed[i].Cmp(values[i]) != 0 {
+			t.Errorf("value %d: got %x, want %x", i, replayed[i], values[i])
+		}
+	}
+}
+
+func TestComputeFromWitnessEmptySeed(t *testing.T) {
+	if _, err := ComputeFromWitness(RandomnessWitness{N: 1}); err != errEmptyServerSeed {
+		t.Fatalf("got %v, want %v", err, errEmptyServerSeed)
+	}
+}